@@ -0,0 +1,97 @@
+package dbservice
+
+import (
+	"errors"
+	"time"
+
+	ankr_default "github.com/Ankr-network/dccn-common/protos"
+	common_proto "github.com/Ankr-network/dccn-common/protos/common"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// StreamTarget is the app/<id> or namespace/<id> a DCStream or its feedback is about, empty for other payloads
+func StreamTarget(stream *common_proto.DCStream) string {
+	switch {
+	case stream.GetAppDeployment() != nil:
+		return "app/" + stream.GetAppDeployment().AppId
+	case stream.GetAppReport() != nil && stream.GetAppReport().AppDeployment != nil:
+		return "app/" + stream.GetAppReport().AppDeployment.AppId
+	case stream.GetNamespace() != nil:
+		return "namespace/" + stream.GetNamespace().NsId
+	case stream.GetNsReport() != nil && stream.GetNsReport().Namespace != nil:
+		return "namespace/" + stream.GetNsReport().Namespace.NsId
+	}
+	return ""
+}
+
+// CorrelationRetention is how long the correlation of a published op is kept for its feedback
+var CorrelationRetention = 7 * 24 * time.Hour
+
+// ensureCorrelationIndexes keeps a single correlation per op type and target, and lets mongodb remove those
+// whose feedback never came
+func (p *DB) ensureCorrelationIndexes() error {
+	session := p.session.Copy()
+	defer session.Close()
+
+	c := p.collection(session, "correlation")
+	if err := c.EnsureIndex(mgo.Index{Key: []string{"target", "optype"}, Unique: true}); err != nil {
+		return err
+	}
+	return c.EnsureIndex(mgo.Index{Key: []string{"expireat"}, ExpireAfter: time.Second})
+}
+
+// streamKey selects the correlation of the op of a DCStream on its target, dcmgr answers an op with a feedback
+// of the same op type, so feedback of an earlier op is not taken for the last one. DCStream carries no id of
+// its op, so of two ops of the same type on a target the feedback of both is logged under the later one.
+func streamKey(target string, stream *common_proto.DCStream) bson.M {
+	return bson.M{"target": target, "optype": stream.GetOpType()}
+}
+
+func (p *DB) SetStreamCorrelation(stream *common_proto.DCStream, correlation CorrelationRecord) error {
+	target := StreamTarget(stream)
+	if len(target) == 0 || (len(correlation.CorrelationID) == 0 && len(correlation.TraceContext) == 0) {
+		return nil
+	}
+
+	session := p.session.Clone()
+	defer session.Close()
+
+	now := time.Now()
+	correlation.Target = target
+	correlation.OpType = stream.GetOpType()
+	correlation.LastModifiedDate = &timestamp.Timestamp{Seconds: now.Unix()}
+	correlation.ExpireAt = now.Add(CorrelationRetention)
+	c := p.collection(session, "correlation")
+	_, err := c.Upsert(streamKey(target, stream), correlation)
+	if mgo.IsDup(err) {
+		// an upsert of the same op inserted first, the unique index refused ours, which now finds its record
+		_, err = c.Upsert(streamKey(target, stream), correlation)
+	}
+	if err != nil {
+		return errors.New(ankr_default.DbError + err.Error())
+	}
+
+	return nil
+}
+
+func (p *DB) GetStreamCorrelation(stream *common_proto.DCStream) (CorrelationRecord, error) {
+	target := StreamTarget(stream)
+	if len(target) == 0 {
+		return CorrelationRecord{}, mgo.ErrNotFound
+	}
+
+	session := p.session.Clone()
+	defer session.Close()
+
+	var correlation CorrelationRecord
+	if err := p.collection(session, "correlation").Find(streamKey(target, stream)).One(&correlation); err != nil {
+		if err == mgo.ErrNotFound {
+			return CorrelationRecord{}, err
+		}
+		return CorrelationRecord{}, errors.New(ankr_default.DbError + err.Error())
+	}
+
+	return correlation, nil
+}
@@ -0,0 +1,30 @@
+package dbservice
+
+import (
+	"testing"
+
+	common_proto "github.com/Ankr-network/dccn-common/protos/common"
+)
+
+func TestStreamTarget(t *testing.T) {
+	app := &common_proto.AppDeployment{AppId: "app-1"}
+	namespace := &common_proto.Namespace{NsId: "ns-1"}
+	cases := []struct {
+		stream *common_proto.DCStream
+		target string
+	}{
+		{&common_proto.DCStream{OpPayload: &common_proto.DCStream_AppDeployment{AppDeployment: app}}, "app/app-1"},
+		{&common_proto.DCStream{OpPayload: &common_proto.DCStream_AppReport{
+			AppReport: &common_proto.AppReport{AppDeployment: app}}}, "app/app-1"},
+		{&common_proto.DCStream{OpPayload: &common_proto.DCStream_Namespace{Namespace: namespace}}, "namespace/ns-1"},
+		{&common_proto.DCStream{OpPayload: &common_proto.DCStream_NsReport{
+			NsReport: &common_proto.NamespaceReport{Namespace: namespace}}}, "namespace/ns-1"},
+		{&common_proto.DCStream{OpPayload: &common_proto.DCStream_AppReport{AppReport: &common_proto.AppReport{}}}, ""},
+		{&common_proto.DCStream{OpPayload: &common_proto.DCStream_DataCenter{DataCenter: &common_proto.DataCenterStatus{}}}, ""},
+	}
+	for i, c := range cases {
+		if target := StreamTarget(c.stream); target != c.target {
+			t.Errorf("case %d: got target %q, want %q", i, target, c.target)
+		}
+	}
+}
@@ -2,9 +2,9 @@ package dbservice
 
 import (
 	"errors"
-	"log"
 	"time"
 
+	"github.com/Ankr-network/dccn-appmgr/logger"
 	dbcommon "github.com/Ankr-network/dccn-common/db"
 	ankr_default "github.com/Ankr-network/dccn-common/protos"
 	common_proto "github.com/Ankr-network/dccn-common/protos/common"
@@ -18,6 +18,10 @@ type DBService interface {
 	GetApp(id string) (AppRecord, error)
 	// GetRunningApps gets running app related to team id.
 	GetRunningApps(teamId string) ([]AppRecord, error)
+	// GetAllRunningApps gets running apps of all teams
+	GetAllRunningApps() ([]AppRecord, error)
+	// GetLiveAppsByChart gets apps not canceled which run or update to a chart version, of one team if teamId is not empty
+	GetLiveAppsByChart(teamId, repo, name, version string) ([]AppRecord, error)
 	// GetAllApps get all app app related to team id
 	GetAllApps(teamID string) ([]AppRecord, error)
 	// GetAllAppsByNamespaceId gets all app related to namespace id.
@@ -42,6 +46,8 @@ type DBService interface {
 	GetRunningNamespaces(teamId string) ([]NamespaceRecord, error)
 	// CountRunningNamespaces count all running namespace
 	CountRunningNamespaces() (int, error)
+	// CountByStatus counts the records of all teams in app, namespace or clusterconnection by status
+	CountByStatus(collection string) (map[int32]int, error)
 	// GetAllNamespaces get all namespace items by teamID
 	GetAllNamespaces(teamID string) ([]NamespaceRecord, error)
 	// GetRunningNamespacesByTeamIDAndClusterID get running namespace related to teamId & clusterId
@@ -70,6 +76,41 @@ type DBService interface {
 	GetClusterConnection(clusterID string) (ClusterConnectionRecord, error)
 	// GetAvailableClusterConnections count available cluster
 	GetAvailableClusterConnections() ([]ClusterConnectionRecord, error)
+	// MarkStaleClustersUnavailable marks available clusters not heard of since before unavailable, with their
+	// running namespaces and apps, and returns their ids
+	MarkStaleClustersUnavailable(before int64) ([]string, error)
+	// SetClusterDraining marks a cluster draining or back in service, its last heartbeat is kept
+	SetClusterDraining(clusterID string, draining bool) error
+	// GetAppsByClusterAndStatus gets apps of all teams in a cluster with one of the statuses, any cluster or status if empty
+	GetAppsByClusterAndStatus(clusterId string, statuses []common_proto.AppStatus) ([]AppRecord, error)
+	// GetNamespacesByClusterAndStatus gets namespaces of all teams in a cluster with one of the statuses, any cluster or status if empty
+	GetNamespacesByClusterAndStatus(clusterId string, statuses []common_proto.NamespaceStatus) ([]NamespaceRecord, error)
+	// CreateAudit records an operator action
+	CreateAudit(audit AuditRecord) error
+	// GetUsage gets the usage of a namespace, team or cluster from to before to, in unix seconds
+	GetUsage(scope, id string, from, to int64) ([]UsagePoint, error)
+	// GetUsageRecords gets the usage records of all namespaces at a resolution from to before to, in unix seconds
+	GetUsageRecords(resolution, from, to int64) ([]UsageRecord, error)
+	// SaveMeteringRecords creates or replaces metering records by id
+	SaveMeteringRecords(records []MeteringRecord) error
+	// GetMeteredUntil gets the end of the last hour metered, mgo.ErrNotFound if none is
+	GetMeteredUntil() (int64, error)
+	// SetMeteredUntil sets the end of the last hour metered and exported
+	SetMeteredUntil(end int64) error
+	// GetMemberRole gets the role of a user in a team, mgo.ErrNotFound if none is set
+	GetMemberRole(teamId, userId string) (string, error)
+	// SetMemberRole sets the role of a user in a team
+	SetMemberRole(teamId, userId, role string) error
+	// CountMemberRoles counts the members of a team by the role set for them, empty if no role is set in the team
+	CountMemberRoles(teamId string) (map[string]int, error)
+	// SetStreamCorrelation keeps the correlation id and trace context of a DCStream about to be published for the op
+	// type and the app or namespace it names
+	SetStreamCorrelation(stream *common_proto.DCStream, correlation CorrelationRecord) error
+	// GetStreamCorrelation gets the correlation kept for the op type and the app or namespace of a DCStream,
+	// mgo.ErrNotFound if none
+	GetStreamCorrelation(stream *common_proto.DCStream) (CorrelationRecord, error)
+	// Ping checks the db can be reached
+	Ping() error
 	// Close closes db connection
 	Close()
 	// for test usage
@@ -82,20 +123,30 @@ type DB struct {
 	collectionName      string
 	eventCollectionName string
 	session             *mgo.Session
+	logger              *logger.Logger
 }
 
-// New returns DBService.
-func New(conf dbcommon.Config) (*DB, error) {
+// New returns DBService, logging what heartbeats change to appLogger.
+func New(conf dbcommon.Config, appLogger *logger.Logger) (*DB, error) {
 	session, err := dbcommon.CreateDBConnection(conf)
 	if err != nil {
 		return nil, err
 	}
 
-	return &DB{
+	db := &DB{
 		dbName:         conf.DB,
 		collectionName: conf.Collection,
 		session:        session,
-	}, nil
+		logger:         appLogger,
+	}
+	if err := db.ensureUsageIndexes(); err != nil {
+		return nil, errors.New(ankr_default.DbError + err.Error())
+	}
+	if err := db.ensureCorrelationIndexes(); err != nil {
+		return nil, errors.New(ankr_default.DbError + err.Error())
+	}
+
+	return db, nil
 }
 
 func (p *DB) collection(session *mgo.Session, collection string) *mgo.Collection {
@@ -136,6 +187,27 @@ func (p *DB) CountRunningApps() (int, error) {
 	return count, nil
 }
 
+func (p *DB) CountByStatus(collection string) (map[int32]int, error) {
+	session := p.session.Clone()
+	defer session.Close()
+
+	var groups []struct {
+		Status int32 `bson:"_id"`
+		Count  int   `bson:"count"`
+	}
+	if err := p.collection(session, collection).Pipe([]bson.M{
+		{"$group": bson.M{"_id": "$status", "count": bson.M{"$sum": 1}}},
+	}).All(&groups); err != nil {
+		return nil, errors.New(ankr_default.DbError + err.Error())
+	}
+
+	counts := make(map[int32]int, len(groups))
+	for _, group := range groups {
+		counts[group.Status] = group.Count
+	}
+	return counts, nil
+}
+
 func (p *DB) CountRunningNamespacesByClusterID(clusterID string) (int, error) {
 	session := p.session.Clone()
 	defer session.Close()
@@ -180,7 +252,6 @@ func (p *DB) GetRunningAppsByTeamIDAndClusterID(teamId string, clusterId string)
 
 	var apps []AppRecord
 
-	log.Printf("GetRunningAppsByTeamIDAndClusterID with teamID %s clusterid %s", teamId, clusterId)
 	if err := p.collection(session, "app").Find(bson.M{"teamid": teamId, "status": common_proto.AppStatus_APP_RUNNING}).All(&apps); err != nil {
 		return nil, errors.New(ankr_default.DbError + err.Error())
 	}
@@ -222,6 +293,44 @@ func (p *DB) GetRunningApps(teamId string) ([]AppRecord, error) {
 	return apps, nil
 }
 
+func (p *DB) GetAllRunningApps() ([]AppRecord, error) {
+	session := p.session.Clone()
+	defer session.Close()
+
+	var apps []AppRecord
+
+	if err := p.collection(session, "app").Find(bson.M{"status": common_proto.AppStatus_APP_RUNNING}).All(&apps); err != nil {
+		return nil, errors.New(ankr_default.DbError + err.Error())
+	}
+	return apps, nil
+}
+
+func (p *DB) GetLiveAppsByChart(teamId, repo, name, version string) ([]AppRecord, error) {
+	session := p.session.Clone()
+	defer session.Close()
+
+	filter := bson.M{
+		"hidden": bson.M{"$ne": true},
+		"status": bson.M{"$nin": []common_proto.AppStatus{
+			common_proto.AppStatus_APP_CANCELED,
+			common_proto.AppStatus_APP_CANCELING,
+		}},
+		"$or": []bson.M{
+			{"chartdetail.chartrepo": repo, "chartdetail.chartname": name, "chartdetail.chartver": version},
+			{"chartupdating.chartrepo": repo, "chartupdating.chartname": name, "chartupdating.chartver": version},
+		},
+	}
+	if len(teamId) > 0 {
+		filter["teamid"] = teamId
+	}
+
+	var apps []AppRecord
+	if err := p.collection(session, "app").Find(filter).All(&apps); err != nil {
+		return nil, errors.New(ankr_default.DbError + err.Error())
+	}
+	return apps, nil
+}
+
 func (p *DB) GetAllApps(teamId string) ([]AppRecord, error) {
 	session := p.session.Clone()
 	defer session.Close()
@@ -240,8 +349,6 @@ func (p *DB) GetAllAppsByNamespaceId(namespaceId string) ([]AppRecord, error) {
 
 	var apps []AppRecord
 
-	log.Printf("find apps with namespace id %s", namespaceId)
-
 	if err := p.collection(session, "app").Find(bson.M{"namespaceid": namespaceId}).All(&apps); err != nil {
 		return nil, errors.New(ankr_default.DbError + err.Error())
 	}
@@ -363,6 +470,16 @@ func (p *DB) CancelApp(appId string) error {
 }
 
 // Close closes the db connection.
+func (p *DB) Ping() error {
+	session := p.session.Clone()
+	defer session.Close()
+
+	if err := session.Ping(); err != nil {
+		return errors.New(ankr_default.DbError + err.Error())
+	}
+	return nil
+}
+
 func (p *DB) Close() {
 	p.session.Close()
 }
@@ -370,7 +487,7 @@ func (p *DB) Close() {
 func (p *DB) dropCollection() {
 	err := p.session.DB(p.dbName).C(p.collectionName).DropCollection()
 	if err != nil {
-		log.Println(err.Error())
+		p.logger.Error("cannot drop collection", logger.Fields{"collection": p.collectionName, "error": err})
 	}
 }
 
@@ -392,8 +509,6 @@ func (p *DB) GetRunningNamespaces(teamId string) ([]NamespaceRecord, error) {
 
 	var namespaces []NamespaceRecord
 
-	log.Printf("find apps with teamId %s", teamId)
-
 	if err := p.collection(session, "namespace").Find(bson.M{"teamid": teamId, "status": common_proto.NamespaceStatus_NS_RUNNING}).All(&namespaces); err != nil {
 		return nil, errors.New(ankr_default.DbError + err.Error())
 	}
@@ -406,8 +521,6 @@ func (p *DB) GetAllNamespaces(teamId string) ([]NamespaceRecord, error) {
 
 	var namespaces []NamespaceRecord
 
-	log.Printf("find apps with teamId %s", teamId)
-
 	if err := p.collection(session, "namespace").Find(bson.M{"teamid": teamId}).All(&namespaces); err != nil {
 		return nil, errors.New(ankr_default.DbError + err.Error())
 	}
@@ -467,18 +580,19 @@ func (p *DB) UpdateNamespace(namespace *common_proto.Namespace) error {
 }
 
 func (p *DB) UpdateByHeartbeatMetrics(clusterID string, metrics *common_proto.DCHeartbeatReport_Metrics) {
+	log := p.logger.With(logger.Fields{"cluster_id": clusterID})
 	if metrics == nil {
-		log.Printf("metrics is nil, skip")
+		log.Debug("heartbeat without metrics, skip")
 		return
 	}
 
 	session := p.session.Copy()
 	defer session.Close()
 
-	log.Printf("UpdateByHeartbeatMetrics %+v", metrics)
+	log.Debug("update by heartbeat metrics", logger.Fields{"namespaces": len(metrics.NsUsed)})
 
 	for nsID, r := range metrics.NsUsed {
-		log.Printf("mark ns %s running and update usage %+v", nsID, r)
+		log.Debug("mark namespace running and update usage", logger.Fields{"namespace_id": nsID, "usage": r})
 		if err := p.collection(session, "namespace").Update(bson.M{
 			"id": nsID,
 			"status": bson.M{
@@ -492,7 +606,7 @@ func (p *DB) UpdateByHeartbeatMetrics(clusterID string, metrics *common_proto.DC
 				"status":       common_proto.NamespaceStatus_NS_RUNNING,
 			},
 		}); err != nil {
-			log.Printf("update ns %s by metrics %+v error: %+v", nsID, r, err)
+			log.Warn("cannot update namespace by metrics", logger.Fields{"namespace_id": nsID, "error": err})
 		} else {
 			changeInfo, err := p.collection(session, "app").UpdateAll(bson.M{
 				"namespaceid": nsID,
@@ -507,44 +621,48 @@ func (p *DB) UpdateByHeartbeatMetrics(clusterID string, metrics *common_proto.DC
 				},
 			})
 			if err != nil {
-				log.Printf("UpdateAll app change unavailable or failed app to available error: %v", err)
+				log.Warn("cannot mark unavailable apps running", logger.Fields{"namespace_id": nsID, "error": err})
+			} else if changeInfo.Updated > 0 {
+				log.Info("unavailable apps running again", logger.Fields{"namespace_id": nsID, "apps": changeInfo.Updated})
 			}
-			log.Printf("mark unavailable or failed apps available of namespace %s, changeInfo %+v", nsID, changeInfo)
 		}
 	}
 
 	nss, err := p.GetRunningNamespacesByClusterId(clusterID)
 	if err != nil {
-		log.Printf("get cluster %s namespaces error: %v", clusterID, err)
+		log.Warn("cannot get running namespaces of cluster", logger.Fields{"error": err})
 	}
 
 	dbNsIDMap := make(map[string]struct{})
+	namespaces := make(map[string]NamespaceRecord, len(nss))
 	for _, ns := range nss {
 		dbNsIDMap[ns.ID] = struct{}{}
+		namespaces[ns.ID] = ns
 	}
+	p.recordUsage(session, log, clusterID, namespaces, metrics, time.Now())
 
 	for nsID := range metrics.NsUsed {
 		if _, ok := dbNsIDMap[nsID]; !ok {
-			log.Printf("ns %s not in db", nsID)
+			log.Debug("namespace of heartbeat not running in db", logger.Fields{"namespace_id": nsID})
 		}
 	}
 
 	for _, ns := range nss {
 		if _, ok := metrics.NsUsed[ns.ID]; !ok {
-			p.markNamespaceUnavailable(session, ns.ID)
+			p.markNamespaceUnavailable(session, log, ns.ID)
 			apps, err := p.GetRunningAppsByNamespaceId(ns.ID)
 			if err != nil {
-				log.Printf("get namespace %s all app error: %+v", ns.ID, err)
+				log.Warn("cannot get running apps of namespace", logger.Fields{"namespace_id": ns.ID, "error": err})
 			}
 			for _, app := range apps {
-				p.markAppUnavailable(session, app.ID)
+				p.markAppUnavailable(session, log, app.ID)
 			}
 		}
 	}
 }
 
-func (p *DB) markNamespaceUnavailable(session *mgo.Session, nsID string) {
-	log.Printf("mark namespace %s unavailable", nsID)
+func (p *DB) markNamespaceUnavailable(session *mgo.Session, log *logger.Logger, nsID string) {
+	log.Info("namespace missing from heartbeat, mark unavailable", logger.Fields{"namespace_id": nsID})
 	markThreshold := time.Now().Unix() - 60
 	if err := p.collection(session, "namespace").Update(bson.M{
 		"id":     nsID,
@@ -555,12 +673,12 @@ func (p *DB) markNamespaceUnavailable(session *mgo.Session, nsID string) {
 	}, bson.M{"$set": bson.M{
 		"status": common_proto.NamespaceStatus_NS_UNAVAILABLE,
 	}}); err != nil {
-		log.Printf("mark namespace %s unavailabe error: %+v", nsID, err)
+		log.Warn("cannot mark namespace unavailable", logger.Fields{"namespace_id": nsID, "error": err})
 	}
 }
 
-func (p *DB) markAppUnavailable(session *mgo.Session, appID string) {
-	log.Printf("mark app %s unavailable", appID)
+func (p *DB) markAppUnavailable(session *mgo.Session, log *logger.Logger, appID string) {
+	log.Info("app of namespace missing from heartbeat, mark unavailable", logger.Fields{"app_id": appID})
 	markThreshold := time.Now().Unix() - 60
 	if err := p.collection(session, "app").Update(bson.M{
 		"id":     appID,
@@ -571,7 +689,7 @@ func (p *DB) markAppUnavailable(session *mgo.Session, appID string) {
 	}, bson.M{"$set": bson.M{
 		"status": common_proto.AppStatus_APP_UNAVAILABLE,
 	}}); err != nil {
-		log.Printf("mark app %s unavailabe error: %+v", appID, err)
+		log.Warn("cannot mark app unavailable", logger.Fields{"app_id": appID, "error": err})
 	}
 }
 
@@ -612,6 +730,81 @@ func (p *DB) GetAvailableClusterConnections() ([]ClusterConnectionRecord, error)
 	return connections, nil
 }
 
+func (p *DB) MarkStaleClustersUnavailable(before int64) ([]string, error) {
+	session := p.session.Copy()
+	defer session.Close()
+
+	// connections created before lastheartbeat existed are judged by lastmodifieddate until their next heartbeat
+	notHeard := []bson.M{
+		{"lastheartbeat.seconds": bson.M{"$lt": before}},
+		{"lastheartbeat": bson.M{"$exists": false}, "lastmodifieddate.seconds": bson.M{"$lt": before}},
+	}
+
+	var stale []ClusterConnectionRecord
+	if err := p.collection(session, "clusterconnection").Find(bson.M{
+		"status": common_proto.DCStatus_AVAILABLE,
+		"$or":    notHeard,
+	}).All(&stale); err != nil {
+		return nil, errors.New(ankr_default.DbError + err.Error())
+	}
+
+	ids := make([]string, 0, len(stale))
+	for _, c := range stale {
+		// lastheartbeat is kept, a heartbeat arriving meanwhile wins
+		if err := p.collection(session, "clusterconnection").Update(bson.M{
+			"id":     c.ID,
+			"status": common_proto.DCStatus_AVAILABLE,
+			"$or":    notHeard,
+		}, bson.M{"$set": bson.M{"status": common_proto.DCStatus_UNAVAILABLE}}); err != nil {
+			if err == mgo.ErrNotFound {
+				continue
+			}
+			return ids, errors.New(ankr_default.DbError + err.Error())
+		}
+		ids = append(ids, c.ID)
+
+		var nss []NamespaceRecord
+		if err := p.collection(session, "namespace").Find(bson.M{
+			"clusterid": c.ID,
+			"status":    common_proto.NamespaceStatus_NS_RUNNING,
+		}).All(&nss); err != nil {
+			return ids, errors.New(ankr_default.DbError + err.Error())
+		}
+		nsids := make([]string, len(nss))
+		for i := range nss {
+			nsids[i] = nss[i].ID
+		}
+
+		// the next heartbeat of the cluster marks them running again, see UpdateByHeartbeatMetrics
+		if _, err := p.collection(session, "namespace").UpdateAll(bson.M{
+			"id":     bson.M{"$in": nsids},
+			"status": common_proto.NamespaceStatus_NS_RUNNING,
+		}, bson.M{"$set": bson.M{"status": common_proto.NamespaceStatus_NS_UNAVAILABLE}}); err != nil {
+			return ids, errors.New(ankr_default.DbError + err.Error())
+		}
+		if _, err := p.collection(session, "app").UpdateAll(bson.M{
+			"namespaceid": bson.M{"$in": nsids},
+			"status":      common_proto.AppStatus_APP_RUNNING,
+		}, bson.M{"$set": bson.M{"status": common_proto.AppStatus_APP_UNAVAILABLE}}); err != nil {
+			return ids, errors.New(ankr_default.DbError + err.Error())
+		}
+	}
+
+	return ids, nil
+}
+
+func (p *DB) SetClusterDraining(clusterID string, draining bool) error {
+	session := p.session.Copy()
+	defer session.Close()
+
+	err := p.collection(session, "clusterconnection").Update(bson.M{"id": clusterID},
+		bson.M{"$set": bson.M{"draining": draining, "lastmodifieddate": &timestamp.Timestamp{Seconds: time.Now().Unix()}}})
+	if err != nil {
+		return errors.New(ankr_default.DbError + err.Error())
+	}
+	return nil
+}
+
 func (p *DB) CreateClusterConnection(clusterID string, clusterStatus common_proto.DCStatus, metrics *common_proto.DCHeartbeatReport_Metrics) error {
 	session := p.session.Copy()
 	defer session.Close()
@@ -623,6 +816,7 @@ func (p *DB) CreateClusterConnection(clusterID string, clusterStatus common_prot
 		Metrics:          metrics,
 		LastModifiedDate: &timestamp.Timestamp{Seconds: now},
 		CreationDate:     &timestamp.Timestamp{Seconds: now},
+		LastHeartbeat:    &timestamp.Timestamp{Seconds: now},
 	}
 
 	return p.collection(session, "clusterconnection").Insert(clusterConnection)
@@ -657,7 +851,6 @@ func (p *DB) GetRunningNamespacesByClusterId(clusterId string) ([]NamespaceRecor
 
 	var nss []NamespaceRecord
 	if err := p.collection(session, "namespace").Find(bson.M{"clusterid": clusterId, "status": common_proto.NamespaceStatus_NS_RUNNING}).All(&nss); err != nil {
-		log.Printf("get cluster %s runing namespace error: %v", clusterId, err)
 		return nss, errors.New(ankr_default.DbError + err.Error())
 	}
 
@@ -679,9 +872,115 @@ func (p *DB) GetRunningNamespacesByTeamIDAndClusterID(teamID string, clusterId s
 
 	var nss []NamespaceRecord
 	if err := p.collection(session, "namespace").Find(filter).All(&nss); err != nil {
-		log.Printf("get cluster %s runing namespace error: %v", clusterId, err)
 		return nss, errors.New(ankr_default.DbError + err.Error())
 	}
 
 	return nss, nil
 }
+
+func (p *DB) GetMemberRole(teamId, userId string) (string, error) {
+	session := p.session.Clone()
+	defer session.Close()
+
+	var member MemberRecord
+	if err := p.collection(session, "member").Find(bson.M{"teamid": teamId, "userid": userId}).One(&member); err != nil {
+		if err == mgo.ErrNotFound {
+			return "", err
+		}
+		return "", errors.New(ankr_default.DbError + err.Error())
+	}
+
+	return member.Role, nil
+}
+
+func (p *DB) SetMemberRole(teamId, userId, role string) error {
+	session := p.session.Clone()
+	defer session.Close()
+
+	member := MemberRecord{
+		TeamID:           teamId,
+		UserID:           userId,
+		Role:             role,
+		LastModifiedDate: &timestamp.Timestamp{Seconds: time.Now().Unix()},
+	}
+	if _, err := p.collection(session, "member").Upsert(bson.M{"teamid": teamId, "userid": userId}, member); err != nil {
+		return errors.New(ankr_default.DbError + err.Error())
+	}
+
+	return nil
+}
+
+func (p *DB) CountMemberRoles(teamId string) (map[string]int, error) {
+	session := p.session.Clone()
+	defer session.Close()
+
+	var members []MemberRecord
+	if err := p.collection(session, "member").Find(bson.M{"teamid": teamId}).Select(bson.M{"role": 1}).All(&members); err != nil {
+		return nil, errors.New(ankr_default.DbError + err.Error())
+	}
+
+	roles := map[string]int{}
+	for _, member := range members {
+		roles[member.Role]++
+	}
+	return roles, nil
+}
+
+func (p *DB) GetAppsByClusterAndStatus(clusterId string, statuses []common_proto.AppStatus) ([]AppRecord, error) {
+	session := p.session.Clone()
+	defer session.Close()
+
+	filter := bson.M{}
+	if len(statuses) > 0 {
+		filter["status"] = bson.M{"$in": statuses}
+	}
+	if len(clusterId) > 0 {
+		var nss []NamespaceRecord
+		if err := p.collection(session, "namespace").Find(bson.M{"clusterid": clusterId}).All(&nss); err != nil {
+			return nil, errors.New(ankr_default.DbError + err.Error())
+		}
+		nsids := make([]string, len(nss))
+		for i := range nss {
+			nsids[i] = nss[i].ID
+		}
+		filter["namespaceid"] = bson.M{"$in": nsids}
+	}
+
+	var apps []AppRecord
+	if err := p.collection(session, "app").Find(filter).All(&apps); err != nil {
+		return nil, errors.New(ankr_default.DbError + err.Error())
+	}
+
+	return apps, nil
+}
+
+func (p *DB) GetNamespacesByClusterAndStatus(clusterId string, statuses []common_proto.NamespaceStatus) ([]NamespaceRecord, error) {
+	session := p.session.Clone()
+	defer session.Close()
+
+	filter := bson.M{}
+	if len(statuses) > 0 {
+		filter["status"] = bson.M{"$in": statuses}
+	}
+	if len(clusterId) > 0 {
+		filter["clusterid"] = clusterId
+	}
+
+	var nss []NamespaceRecord
+	if err := p.collection(session, "namespace").Find(filter).All(&nss); err != nil {
+		return nil, errors.New(ankr_default.DbError + err.Error())
+	}
+
+	return nss, nil
+}
+
+func (p *DB) CreateAudit(audit AuditRecord) error {
+	session := p.session.Clone()
+	defer session.Close()
+
+	if err := p.collection(session, "audit").Insert(audit); err != nil {
+		return errors.New(ankr_default.DbError + err.Error())
+	}
+
+	return nil
+}
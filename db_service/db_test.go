@@ -5,9 +5,12 @@ package dbservice
 import (
 	"github.com/Ankr-network/dccn-appmgr/config"
 	"github.com/Ankr-network/dccn-common/protos/common"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"gopkg.in/mgo.v2"
 	"gopkg.in/mgo.v2/bson"
 	"log"
 	"testing"
+	"time"
 )
 
 var (
@@ -15,7 +18,7 @@ var (
 )
 
 func init() {
-	localDB, err := New(config.Default.DB)
+	localDB, err := New(config.Default.DB, nil)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -32,3 +35,243 @@ func TestDB_UpdateByHeartbeatMetrics(t *testing.T) {
 	t.Log(len(c.Metrics.NsUsed))
 	testDB.UpdateByHeartbeatMetrics(c.ID, c.Metrics)
 }
+
+func TestDB_GetLiveAppsByChart(t *testing.T) {
+	s := testDB.session.Copy()
+	defer s.Close()
+
+	redis := common_proto.ChartDetail{ChartRepo: "stable", ChartName: "redis-live-test", ChartVer: "1.0.0"}
+	older := common_proto.ChartDetail{ChartRepo: "stable", ChartName: "redis-live-test", ChartVer: "0.9.0"}
+	apps := []AppRecord{
+		{ID: "live-running", TeamID: "team-1", Status: common_proto.AppStatus_APP_RUNNING, ChartDetail: redis},
+		{ID: "live-updating", TeamID: "team-2", Status: common_proto.AppStatus_APP_UPDATING, ChartDetail: older,
+			ChartUpdating: redis},
+		{ID: "live-canceled", TeamID: "team-1", Status: common_proto.AppStatus_APP_CANCELED, ChartDetail: redis},
+		{ID: "live-hidden", TeamID: "team-1", Status: common_proto.AppStatus_APP_RUNNING, ChartDetail: redis, Hidden: true},
+		{ID: "live-older", TeamID: "team-1", Status: common_proto.AppStatus_APP_RUNNING, ChartDetail: older},
+	}
+	ids := make([]string, 0, len(apps))
+	for _, app := range apps {
+		if err := s.DB(testDB.dbName).C("app").Insert(app); err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, app.ID)
+	}
+	defer s.DB(testDB.dbName).C("app").RemoveAll(bson.M{"id": bson.M{"$in": ids}})
+
+	cases := []struct {
+		teamId string
+		want   []string
+	}{
+		{"", []string{"live-running", "live-updating"}},
+		{"team-1", []string{"live-running"}},
+		{"team-3", nil},
+	}
+	for _, c := range cases {
+		live, err := testDB.GetLiveAppsByChart(c.teamId, redis.ChartRepo, redis.ChartName, redis.ChartVer)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := map[string]bool{}
+		for _, app := range live {
+			got[app.ID] = true
+		}
+		if len(got) != len(c.want) {
+			t.Errorf("team %q: got apps %v, want %v", c.teamId, got, c.want)
+			continue
+		}
+		for _, id := range c.want {
+			if !got[id] {
+				t.Errorf("team %q: app %s missing from %v", c.teamId, id, got)
+			}
+		}
+	}
+}
+
+func TestDB_MarkStaleClustersUnavailable(t *testing.T) {
+	s := testDB.session.Copy()
+	defer s.Close()
+	db := s.DB(testDB.dbName)
+
+	before := time.Now().Unix()
+	stale := &timestamp.Timestamp{Seconds: before - 60}
+	fresh := &timestamp.Timestamp{Seconds: before + 60}
+	clusters := []ClusterConnectionRecord{
+		{ID: "stale-test", Status: common_proto.DCStatus_AVAILABLE, LastModifiedDate: stale, LastHeartbeat: stale},
+		{ID: "fresh-test", Status: common_proto.DCStatus_AVAILABLE, LastModifiedDate: fresh, LastHeartbeat: fresh},
+		{ID: "down-test", Status: common_proto.DCStatus_UNAVAILABLE, LastModifiedDate: stale, LastHeartbeat: stale},
+		// modified by a drain since its last heartbeat
+		{ID: "drained-test", Status: common_proto.DCStatus_AVAILABLE, LastModifiedDate: fresh, LastHeartbeat: stale},
+		// created before lastheartbeat existed
+		{ID: "legacy-test", Status: common_proto.DCStatus_AVAILABLE, LastModifiedDate: stale},
+	}
+	namespaces := []NamespaceRecord{
+		{ID: "stale-ns-running", ClusterID: "stale-test", Status: common_proto.NamespaceStatus_NS_RUNNING},
+		{ID: "stale-ns-updating", ClusterID: "stale-test", Status: common_proto.NamespaceStatus_NS_UPDATING},
+		{ID: "fresh-ns-running", ClusterID: "fresh-test", Status: common_proto.NamespaceStatus_NS_RUNNING},
+	}
+	apps := []AppRecord{
+		{ID: "stale-app-running", NamespaceID: "stale-ns-running", Status: common_proto.AppStatus_APP_RUNNING},
+		{ID: "stale-app-updating", NamespaceID: "stale-ns-running", Status: common_proto.AppStatus_APP_UPDATING},
+		{ID: "fresh-app-running", NamespaceID: "fresh-ns-running", Status: common_proto.AppStatus_APP_RUNNING},
+	}
+	for _, c := range clusters {
+		if err := db.C("clusterconnection").Insert(c); err != nil {
+			t.Fatal(err)
+		}
+		defer db.C("clusterconnection").Remove(bson.M{"id": c.ID})
+	}
+	for _, ns := range namespaces {
+		if err := db.C("namespace").Insert(ns); err != nil {
+			t.Fatal(err)
+		}
+		defer db.C("namespace").Remove(bson.M{"id": ns.ID})
+	}
+	for _, app := range apps {
+		if err := db.C("app").Insert(app); err != nil {
+			t.Fatal(err)
+		}
+		defer db.C("app").Remove(bson.M{"id": app.ID})
+	}
+
+	ids, err := testDB.MarkStaleClustersUnavailable(before)
+	if err != nil {
+		t.Fatal(err)
+	}
+	marked := map[string]bool{}
+	for _, id := range ids {
+		marked[id] = true
+	}
+	if len(ids) != 3 || !marked["stale-test"] || !marked["drained-test"] || !marked["legacy-test"] {
+		t.Errorf("marked %v, want stale-test, drained-test and legacy-test", ids)
+	}
+
+	clusterStatus := map[string]common_proto.DCStatus{
+		"stale-test":   common_proto.DCStatus_UNAVAILABLE,
+		"fresh-test":   common_proto.DCStatus_AVAILABLE,
+		"down-test":    common_proto.DCStatus_UNAVAILABLE,
+		"drained-test": common_proto.DCStatus_UNAVAILABLE,
+		"legacy-test":  common_proto.DCStatus_UNAVAILABLE,
+	}
+	for id, want := range clusterStatus {
+		var c ClusterConnectionRecord
+		if err := db.C("clusterconnection").Find(bson.M{"id": id}).One(&c); err != nil {
+			t.Fatal(err)
+		}
+		if c.Status != want {
+			t.Errorf("cluster %s is %s, want %s", id, c.Status, want)
+		}
+	}
+	// lastheartbeat is kept, a heartbeat arriving meanwhile wins
+	var c ClusterConnectionRecord
+	if err := db.C("clusterconnection").Find(bson.M{"id": "stale-test"}).One(&c); err != nil {
+		t.Fatal(err)
+	}
+	if c.LastHeartbeat == nil || c.LastHeartbeat.Seconds != stale.Seconds {
+		t.Errorf("stale cluster lastheartbeat changed to %v", c.LastHeartbeat)
+	}
+
+	nsStatus := map[string]common_proto.NamespaceStatus{
+		"stale-ns-running":  common_proto.NamespaceStatus_NS_UNAVAILABLE,
+		"stale-ns-updating": common_proto.NamespaceStatus_NS_UPDATING,
+		"fresh-ns-running":  common_proto.NamespaceStatus_NS_RUNNING,
+	}
+	for id, want := range nsStatus {
+		ns, err := testDB.GetNamespace(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ns.Status != want {
+			t.Errorf("namespace %s is %s, want %s", id, ns.Status, want)
+		}
+	}
+
+	appStatus := map[string]common_proto.AppStatus{
+		"stale-app-running":  common_proto.AppStatus_APP_UNAVAILABLE,
+		"stale-app-updating": common_proto.AppStatus_APP_UPDATING,
+		"fresh-app-running":  common_proto.AppStatus_APP_RUNNING,
+	}
+	for id, want := range appStatus {
+		app, err := testDB.GetApp(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if app.Status != want {
+			t.Errorf("app %s is %s, want %s", id, app.Status, want)
+		}
+	}
+}
+
+func TestDB_StreamCorrelation(t *testing.T) {
+	s := testDB.session.Copy()
+	defer s.Close()
+	defer s.DB(testDB.dbName).C("correlation").RemoveAll(bson.M{"target": "app/correlation-test"})
+
+	app := &common_proto.DCStream_AppDeployment{AppDeployment: &common_proto.AppDeployment{AppId: "correlation-test"}}
+	create := &common_proto.DCStream{OpType: common_proto.DCOperation_APP_CREATE, OpPayload: app}
+	update := &common_proto.DCStream{OpType: common_proto.DCOperation_APP_UPDATE, OpPayload: app}
+	if err := testDB.SetStreamCorrelation(create, CorrelationRecord{CorrelationID: "create"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := testDB.SetStreamCorrelation(update, CorrelationRecord{CorrelationID: "update"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// feedback of the create arriving after the update was published is still logged under the create
+	feedback := &common_proto.DCStream{OpType: common_proto.DCOperation_APP_CREATE, OpPayload: &common_proto.DCStream_AppReport{
+		AppReport: &common_proto.AppReport{AppDeployment: app.AppDeployment}}}
+	if correlation, err := testDB.GetStreamCorrelation(feedback); err != nil || correlation.CorrelationID != "create" {
+		t.Errorf("create feedback gives correlation %q, %v, want create", correlation.CorrelationID, err)
+	}
+	if correlation, err := testDB.GetStreamCorrelation(update); err != nil || correlation.CorrelationID != "update" {
+		t.Errorf("update gives correlation %q, %v, want update", correlation.CorrelationID, err)
+	}
+	cancel := &common_proto.DCStream{OpType: common_proto.DCOperation_APP_CANCEL, OpPayload: app}
+	if _, err := testDB.GetStreamCorrelation(cancel); err != mgo.ErrNotFound {
+		t.Errorf("cancel never published gives %v, want not found", err)
+	}
+
+	// publishing the op again replaces its correlation, the unique index keeps it single
+	if err := testDB.SetStreamCorrelation(create, CorrelationRecord{CorrelationID: "create again"}); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := s.DB(testDB.dbName).C("correlation").Find(bson.M{"target": "app/correlation-test"}).Count(); err != nil || n != 2 {
+		t.Errorf("%d correlations, %v, want 2", n, err)
+	}
+	if correlation, err := testDB.GetStreamCorrelation(feedback); err != nil || correlation.CorrelationID != "create again" {
+		t.Errorf("create feedback gives correlation %q, %v, want create again", correlation.CorrelationID, err)
+	} else if correlation.ExpireAt.Before(time.Now()) {
+		t.Errorf("correlation expires at %v, want later than now", correlation.ExpireAt)
+	}
+
+	indexes, err := s.DB(testDB.dbName).C("correlation").Indexes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	unique, ttl := false, false
+	for _, index := range indexes {
+		unique = unique || index.Unique && len(index.Key) == 2 && index.Key[0] == "target" && index.Key[1] == "optype"
+		ttl = ttl || index.ExpireAfter > 0 && len(index.Key) == 1 && index.Key[0] == "expireat"
+	}
+	if !unique || !ttl {
+		t.Errorf("indexes %+v, want unique target and optype and ttl on expireat", indexes)
+	}
+}
+
+func TestDB_CountMemberRoles(t *testing.T) {
+	s := testDB.session.Copy()
+	defer s.Close()
+	defer s.DB(testDB.dbName).C("member").RemoveAll(bson.M{"teamid": "member-test"})
+
+	if roles, err := testDB.CountMemberRoles("member-test"); err != nil || len(roles) != 0 {
+		t.Fatalf("team without roles gives %v, %v", roles, err)
+	}
+	for user, role := range map[string]string{"alice": "admin", "bob": "viewer", "carol": "viewer"} {
+		if err := testDB.SetMemberRole("member-test", user, role); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if roles, err := testDB.CountMemberRoles("member-test"); err != nil || roles["admin"] != 1 || roles["viewer"] != 2 {
+		t.Errorf("got roles %v, %v, want an admin and two viewers", roles, err)
+	}
+}
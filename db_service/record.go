@@ -1,6 +1,8 @@
 package dbservice
 
 import (
+	"time"
+
 	"github.com/Ankr-network/dccn-common/protos/common"
 	"github.com/golang/protobuf/ptypes/timestamp"
 )
@@ -24,8 +26,32 @@ type AppRecord struct {
 	Creator              string
 	NodePorts            []uint32
 	GatewayAddr          string
+	Upgrade              AppUpgrade
+	AutoUpgrade          AutoUpgrade
+	MigratedFrom         string // app this one replaces on another cluster, canceled once this one runs
+	MigratedTo           string // app replacing this one while its cluster drains
+}
+
+// AppUpgrade is the newest chart version available for an app, found by the periodic upgrade check
+type AppUpgrade struct {
+	Version     string
+	Kind        string               // patch, minor or major, empty if no upgrade is available
+	CheckedDate *timestamp.Timestamp // when the check found this version, not rewritten while it stays the same
 }
 
+// AutoUpgrade is the upgrade policy of an app and the state of the upgrade the scheduler started
+type AutoUpgrade struct {
+	Policy        string // patch or minor, no automatic upgrade if empty
+	Upgrading     bool   // the running update was started by the scheduler, rolled back if it fails
+	FailedVersion string // the version which failed and was rolled back, not tried again
+}
+
+const (
+	UpgradePatch = "patch"
+	UpgradeMinor = "minor"
+	UpgradeMajor = "major"
+)
+
 type NamespaceRecord struct {
 	ID                   string // short hash of uid+name+cluster_id
 	Name                 string
@@ -49,6 +75,85 @@ type NamespaceRecord struct {
 	Hidden               bool
 	Creator              string
 	Report               string
+	MigratedFrom         string // namespace this one replaces on another cluster
+	MigratedTo           string // namespace replacing this one while its cluster drains
+}
+
+// UsageRecord is the resource usage of a namespace sampled by heartbeats, over Resolution seconds from Time.
+// Usage is summed over the samples, raw samples have resolution 0 and are one sample each.
+type UsageRecord struct {
+	NamespaceID string
+	TeamID      string
+	ClusterID   string
+	Resolution  int64
+	Time        int64 // unix seconds the sample was taken or the bucket starts
+	Samples     int64
+	CpuSum      uint64
+	MemSum      uint64
+	StorageSum  uint64
+	// limits of the namespace when sampled, summed the same way
+	CpuLimitSum     uint64
+	MemLimitSum     uint64
+	StorageLimitSum uint64
+	ExpireAt        time.Time // removed by the ttl index of the usage collection after it
+}
+
+// UsagePoint is the average usage at a time, summed over the namespaces queried
+type UsagePoint struct {
+	Time    int64
+	Cpu     float64
+	Mem     float64
+	Storage float64
+}
+
+// MeteringRecord is the usage and limits of a namespace or of a team integrated over an hour, for billing.
+// Its id is the same every time the hour is rolled up, and the json schema is kept stable for exports.
+type MeteringRecord struct {
+	ID            string `json:"id"` // scope/id/start
+	SchemaVersion int    `json:"schema_version"`
+	Scope         string `json:"scope"` // namespace or team
+	NamespaceID   string `json:"namespace_id,omitempty"`
+	TeamID        string `json:"team_id"`
+	ClusterID     string `json:"cluster_id,omitempty"`
+	Start         int64  `json:"start"` // unix seconds
+	End           int64  `json:"end"`
+	// seconds the namespace reported usage, summed over the namespaces of a team
+	Seconds int64 `json:"seconds"`
+	// usage and limits in the units of the heartbeat metrics multiplied by seconds
+	CpuUsage     float64 `json:"cpu_usage"`
+	MemUsage     float64 `json:"mem_usage"`
+	StorageUsage float64 `json:"storage_usage"`
+	CpuLimit     float64 `json:"cpu_limit"`
+	MemLimit     float64 `json:"mem_limit"`
+	StorageLimit float64 `json:"storage_limit"`
+}
+
+// MemberRecord is the role of a user in a team, which limits the rpcs the user may call
+type MemberRecord struct {
+	TeamID           string
+	UserID           string
+	Role             string
+	LastModifiedDate *timestamp.Timestamp
+}
+
+// AuditRecord is an operator action on records of any team
+type AuditRecord struct {
+	UserID    string
+	Operation string
+	Target    string // kind/id of the record acted on, empty for listings
+	Detail    string
+	Date      *timestamp.Timestamp
+}
+
+// CorrelationRecord is the correlation id and trace context of the call which published the last DCStream of an
+// op type for an app or namespace, which the feedback of dcmgr to it is logged and traced under
+type CorrelationRecord struct {
+	Target           string // app/<id> or namespace/<id>
+	OpType           common_proto.DCOperation
+	CorrelationID    string
+	TraceContext     map[string]string // traceparent and tracestate of the publish span
+	LastModifiedDate *timestamp.Timestamp
+	ExpireAt         time.Time // removed by the ttl index of the correlation collection after it
 }
 
 type ClusterConnectionRecord struct {
@@ -57,4 +162,6 @@ type ClusterConnectionRecord struct {
 	Metrics          *common_proto.DCHeartbeatReport_Metrics
 	LastModifiedDate *timestamp.Timestamp
 	CreationDate     *timestamp.Timestamp
+	LastHeartbeat    *timestamp.Timestamp // when the cluster last sent metrics, unlike LastModifiedDate only heartbeats set it
+	Draining         bool                 // no namespaces or apps are placed on the cluster, its namespaces move elsewhere
 }
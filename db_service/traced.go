@@ -0,0 +1,365 @@
+package dbservice
+
+import (
+	"context"
+
+	"github.com/Ankr-network/dccn-appmgr/tracing"
+	common_proto "github.com/Ankr-network/dccn-common/protos/common"
+	"go.opentelemetry.io/otel/api/kv"
+	"go.opentelemetry.io/otel/api/trace"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// tracedDB makes each call to the db a span of the call of ctx
+type tracedDB struct {
+	DBService
+	ctx context.Context
+}
+
+// Traced returns db with each call made a span named db.<Method>, as a child of the span of ctx
+func Traced(ctx context.Context, db DBService) DBService {
+	return &tracedDB{DBService: db, ctx: ctx}
+}
+
+func (t *tracedDB) start(method string) trace.Span {
+	_, span := tracing.Start(t.ctx, "db."+method, trace.SpanKindClient,
+		kv.String("db.type", "mongo"), kv.String("db.operation", method))
+	return span
+}
+
+// endCall ends the span of a call, a record not found is an answer rather than a failure
+func endCall(span trace.Span, err error) {
+	if err == mgo.ErrNotFound {
+		err = nil
+	}
+	tracing.End(span, err)
+}
+
+func (t *tracedDB) GetApp(id string) (AppRecord, error) {
+	span := t.start("GetApp")
+	result, err := t.DBService.GetApp(id)
+	endCall(span, err)
+	return result, err
+}
+
+func (t *tracedDB) GetRunningApps(teamId string) ([]AppRecord, error) {
+	span := t.start("GetRunningApps")
+	result, err := t.DBService.GetRunningApps(teamId)
+	endCall(span, err)
+	return result, err
+}
+
+func (t *tracedDB) GetAllRunningApps() ([]AppRecord, error) {
+	span := t.start("GetAllRunningApps")
+	result, err := t.DBService.GetAllRunningApps()
+	endCall(span, err)
+	return result, err
+}
+
+func (t *tracedDB) GetLiveAppsByChart(teamId, repo, name, version string) ([]AppRecord, error) {
+	span := t.start("GetLiveAppsByChart")
+	result, err := t.DBService.GetLiveAppsByChart(teamId, repo, name, version)
+	endCall(span, err)
+	return result, err
+}
+
+func (t *tracedDB) GetAllApps(teamID string) ([]AppRecord, error) {
+	span := t.start("GetAllApps")
+	result, err := t.DBService.GetAllApps(teamID)
+	endCall(span, err)
+	return result, err
+}
+
+func (t *tracedDB) GetAllAppsByNamespaceId(namespaceId string) ([]AppRecord, error) {
+	span := t.start("GetAllAppsByNamespaceId")
+	result, err := t.DBService.GetAllAppsByNamespaceId(namespaceId)
+	endCall(span, err)
+	return result, err
+}
+
+func (t *tracedDB) GetRunningAppsByNamespaceId(namespaceId string) ([]AppRecord, error) {
+	span := t.start("GetRunningAppsByNamespaceId")
+	result, err := t.DBService.GetRunningAppsByNamespaceId(namespaceId)
+	endCall(span, err)
+	return result, err
+}
+
+func (t *tracedDB) GetRunningAppsByClusterID(clusterID string) ([]AppRecord, error) {
+	span := t.start("GetRunningAppsByClusterID")
+	result, err := t.DBService.GetRunningAppsByClusterID(clusterID)
+	endCall(span, err)
+	return result, err
+}
+
+func (t *tracedDB) CountRunningAppsByClusterID(clusterID string) (int, error) {
+	span := t.start("CountRunningAppsByClusterID")
+	result, err := t.DBService.CountRunningAppsByClusterID(clusterID)
+	endCall(span, err)
+	return result, err
+}
+
+func (t *tracedDB) CountRunningApps() (int, error) {
+	span := t.start("CountRunningApps")
+	result, err := t.DBService.CountRunningApps()
+	endCall(span, err)
+	return result, err
+}
+
+func (t *tracedDB) GetRunningAppsByTeamIDAndClusterID(teamId string, clusterId string) ([]AppRecord, error) {
+	span := t.start("GetRunningAppsByTeamIDAndClusterID")
+	result, err := t.DBService.GetRunningAppsByTeamIDAndClusterID(teamId, clusterId)
+	endCall(span, err)
+	return result, err
+}
+
+func (t *tracedDB) GetNamespace(namespaceId string) (NamespaceRecord, error) {
+	span := t.start("GetNamespace")
+	result, err := t.DBService.GetNamespace(namespaceId)
+	endCall(span, err)
+	return result, err
+}
+
+func (t *tracedDB) GetRunningNamespacesByClusterId(clusterId string) ([]NamespaceRecord, error) {
+	span := t.start("GetRunningNamespacesByClusterId")
+	result, err := t.DBService.GetRunningNamespacesByClusterId(clusterId)
+	endCall(span, err)
+	return result, err
+}
+
+func (t *tracedDB) CountRunningNamespacesByClusterID(clusterID string) (int, error) {
+	span := t.start("CountRunningNamespacesByClusterID")
+	result, err := t.DBService.CountRunningNamespacesByClusterID(clusterID)
+	endCall(span, err)
+	return result, err
+}
+
+func (t *tracedDB) GetRunningNamespaces(teamId string) ([]NamespaceRecord, error) {
+	span := t.start("GetRunningNamespaces")
+	result, err := t.DBService.GetRunningNamespaces(teamId)
+	endCall(span, err)
+	return result, err
+}
+
+func (t *tracedDB) CountRunningNamespaces() (int, error) {
+	span := t.start("CountRunningNamespaces")
+	result, err := t.DBService.CountRunningNamespaces()
+	endCall(span, err)
+	return result, err
+}
+
+func (t *tracedDB) CountByStatus(collection string) (map[int32]int, error) {
+	span := t.start("CountByStatus")
+	result, err := t.DBService.CountByStatus(collection)
+	endCall(span, err)
+	return result, err
+}
+
+func (t *tracedDB) GetAllNamespaces(teamID string) ([]NamespaceRecord, error) {
+	span := t.start("GetAllNamespaces")
+	result, err := t.DBService.GetAllNamespaces(teamID)
+	endCall(span, err)
+	return result, err
+}
+
+func (t *tracedDB) GetRunningNamespacesByTeamIDAndClusterID(teamID string, clusterId string) ([]NamespaceRecord, error) {
+	span := t.start("GetRunningNamespacesByTeamIDAndClusterID")
+	result, err := t.DBService.GetRunningNamespacesByTeamIDAndClusterID(teamID, clusterId)
+	endCall(span, err)
+	return result, err
+}
+
+func (t *tracedDB) CancelApp(appId string) error {
+	span := t.start("CancelApp")
+	err := t.DBService.CancelApp(appId)
+	endCall(span, err)
+	return err
+}
+
+func (t *tracedDB) CancelNamespace(namespaceId string) error {
+	span := t.start("CancelNamespace")
+	err := t.DBService.CancelNamespace(namespaceId)
+	endCall(span, err)
+	return err
+}
+
+func (t *tracedDB) CreateNamespace(namespace *common_proto.Namespace, teamId string, creator string) error {
+	span := t.start("CreateNamespace")
+	err := t.DBService.CreateNamespace(namespace, teamId, creator)
+	endCall(span, err)
+	return err
+}
+
+func (t *tracedDB) CreateApp(appDeployment *common_proto.AppDeployment, teamId string, creator string) error {
+	span := t.start("CreateApp")
+	err := t.DBService.CreateApp(appDeployment, teamId, creator)
+	endCall(span, err)
+	return err
+}
+
+func (t *tracedDB) Update(collectId string, id string, update bson.M) error {
+	span := t.start("Update")
+	err := t.DBService.Update(collectId, id, update)
+	endCall(span, err)
+	return err
+}
+
+func (t *tracedDB) UpdateMany(collection string, filter, update bson.M) (*mgo.ChangeInfo, error) {
+	span := t.start("UpdateMany")
+	result, err := t.DBService.UpdateMany(collection, filter, update)
+	endCall(span, err)
+	return result, err
+}
+
+func (t *tracedDB) UpdateApp(app *common_proto.AppDeployment) error {
+	span := t.start("UpdateApp")
+	err := t.DBService.UpdateApp(app)
+	endCall(span, err)
+	return err
+}
+
+func (t *tracedDB) UpdateNamespace(namespace *common_proto.Namespace) error {
+	span := t.start("UpdateNamespace")
+	err := t.DBService.UpdateNamespace(namespace)
+	endCall(span, err)
+	return err
+}
+
+func (t *tracedDB) UpdateByHeartbeatMetrics(clusterID string, metrics *common_proto.DCHeartbeatReport_Metrics) {
+	span := t.start("UpdateByHeartbeatMetrics")
+	t.DBService.UpdateByHeartbeatMetrics(clusterID, metrics)
+	span.End()
+}
+
+func (t *tracedDB) CreateClusterConnection(clusterID string, clusterStatus common_proto.DCStatus, metrics *common_proto.DCHeartbeatReport_Metrics) error {
+	span := t.start("CreateClusterConnection")
+	err := t.DBService.CreateClusterConnection(clusterID, clusterStatus, metrics)
+	endCall(span, err)
+	return err
+}
+
+func (t *tracedDB) GetClusterConnection(clusterID string) (ClusterConnectionRecord, error) {
+	span := t.start("GetClusterConnection")
+	result, err := t.DBService.GetClusterConnection(clusterID)
+	endCall(span, err)
+	return result, err
+}
+
+func (t *tracedDB) GetAvailableClusterConnections() ([]ClusterConnectionRecord, error) {
+	span := t.start("GetAvailableClusterConnections")
+	result, err := t.DBService.GetAvailableClusterConnections()
+	endCall(span, err)
+	return result, err
+}
+
+func (t *tracedDB) MarkStaleClustersUnavailable(before int64) ([]string, error) {
+	span := t.start("MarkStaleClustersUnavailable")
+	result, err := t.DBService.MarkStaleClustersUnavailable(before)
+	endCall(span, err)
+	return result, err
+}
+
+func (t *tracedDB) SetClusterDraining(clusterID string, draining bool) error {
+	span := t.start("SetClusterDraining")
+	err := t.DBService.SetClusterDraining(clusterID, draining)
+	endCall(span, err)
+	return err
+}
+
+func (t *tracedDB) GetAppsByClusterAndStatus(clusterId string, statuses []common_proto.AppStatus) ([]AppRecord, error) {
+	span := t.start("GetAppsByClusterAndStatus")
+	result, err := t.DBService.GetAppsByClusterAndStatus(clusterId, statuses)
+	endCall(span, err)
+	return result, err
+}
+
+func (t *tracedDB) GetNamespacesByClusterAndStatus(clusterId string, statuses []common_proto.NamespaceStatus) ([]NamespaceRecord, error) {
+	span := t.start("GetNamespacesByClusterAndStatus")
+	result, err := t.DBService.GetNamespacesByClusterAndStatus(clusterId, statuses)
+	endCall(span, err)
+	return result, err
+}
+
+func (t *tracedDB) CreateAudit(audit AuditRecord) error {
+	span := t.start("CreateAudit")
+	err := t.DBService.CreateAudit(audit)
+	endCall(span, err)
+	return err
+}
+
+func (t *tracedDB) GetUsage(scope, id string, from, to int64) ([]UsagePoint, error) {
+	span := t.start("GetUsage")
+	result, err := t.DBService.GetUsage(scope, id, from, to)
+	endCall(span, err)
+	return result, err
+}
+
+func (t *tracedDB) GetUsageRecords(resolution, from, to int64) ([]UsageRecord, error) {
+	span := t.start("GetUsageRecords")
+	result, err := t.DBService.GetUsageRecords(resolution, from, to)
+	endCall(span, err)
+	return result, err
+}
+
+func (t *tracedDB) SaveMeteringRecords(records []MeteringRecord) error {
+	span := t.start("SaveMeteringRecords")
+	err := t.DBService.SaveMeteringRecords(records)
+	endCall(span, err)
+	return err
+}
+
+func (t *tracedDB) GetMeteredUntil() (int64, error) {
+	span := t.start("GetMeteredUntil")
+	result, err := t.DBService.GetMeteredUntil()
+	endCall(span, err)
+	return result, err
+}
+
+func (t *tracedDB) SetMeteredUntil(end int64) error {
+	span := t.start("SetMeteredUntil")
+	err := t.DBService.SetMeteredUntil(end)
+	endCall(span, err)
+	return err
+}
+
+func (t *tracedDB) GetMemberRole(teamId, userId string) (string, error) {
+	span := t.start("GetMemberRole")
+	result, err := t.DBService.GetMemberRole(teamId, userId)
+	endCall(span, err)
+	return result, err
+}
+
+func (t *tracedDB) SetMemberRole(teamId, userId, role string) error {
+	span := t.start("SetMemberRole")
+	err := t.DBService.SetMemberRole(teamId, userId, role)
+	endCall(span, err)
+	return err
+}
+
+func (t *tracedDB) CountMemberRoles(teamId string) (map[string]int, error) {
+	span := t.start("CountMemberRoles")
+	result, err := t.DBService.CountMemberRoles(teamId)
+	endCall(span, err)
+	return result, err
+}
+
+func (t *tracedDB) SetStreamCorrelation(stream *common_proto.DCStream, correlation CorrelationRecord) error {
+	span := t.start("SetStreamCorrelation")
+	err := t.DBService.SetStreamCorrelation(stream, correlation)
+	endCall(span, err)
+	return err
+}
+
+func (t *tracedDB) GetStreamCorrelation(stream *common_proto.DCStream) (CorrelationRecord, error) {
+	span := t.start("GetStreamCorrelation")
+	result, err := t.DBService.GetStreamCorrelation(stream)
+	endCall(span, err)
+	return result, err
+}
+
+func (t *tracedDB) Ping() error {
+	span := t.start("Ping")
+	err := t.DBService.Ping()
+	endCall(span, err)
+	return err
+}
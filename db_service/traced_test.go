@@ -0,0 +1,63 @@
+package dbservice
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/otel/api/global"
+	export "go.opentelemetry.io/otel/sdk/export/trace"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"google.golang.org/grpc/codes"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+type spanRecorder map[string]*export.SpanData
+
+func (r spanRecorder) ExportSpan(_ context.Context, span *export.SpanData) {
+	r[span.Name] = span
+}
+
+type fakeDB struct {
+	DBService
+}
+
+func (fakeDB) GetApp(id string) (AppRecord, error) {
+	return AppRecord{}, mgo.ErrNotFound
+}
+
+func (fakeDB) Update(collection string, id string, update bson.M) error {
+	return errors.New("no reachable servers")
+}
+
+func TestTraced(t *testing.T) {
+	spans := spanRecorder{}
+	provider, err := sdktrace.NewProvider(sdktrace.WithSyncer(spans))
+	if err != nil {
+		t.Fatal(err)
+	}
+	global.SetTraceProvider(provider)
+
+	ctx, rpc := global.Tracer("test").Start(context.Background(), "rpc")
+	db := Traced(ctx, fakeDB{})
+	if _, err := db.GetApp("app-1"); err != mgo.ErrNotFound {
+		t.Errorf("got error %v through the traced db", err)
+	}
+	if err := db.Update("app", "app-1", bson.M{}); err == nil {
+		t.Error("update error was dropped")
+	}
+	rpc.End()
+
+	for name, code := range map[string]codes.Code{"db.GetApp": codes.OK, "db.Update": codes.Unknown} {
+		span, ok := spans[name]
+		if !ok {
+			t.Errorf("no span %s", name)
+			continue
+		}
+		if span.ParentSpanID != spans["rpc"].SpanContext.SpanID || span.StatusCode != code {
+			t.Errorf("span %s has parent %s and code %s, want the rpc span and %s", name, span.ParentSpanID,
+				span.StatusCode, code)
+		}
+	}
+}
@@ -0,0 +1,217 @@
+package dbservice
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/Ankr-network/dccn-appmgr/logger"
+	ankr_default "github.com/Ankr-network/dccn-common/protos"
+	common_proto "github.com/Ankr-network/dccn-common/protos/common"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// scopes usage is queried by, the usage record field holding the id
+const (
+	UsageByNamespace = "namespaceid"
+	UsageByTeam      = "teamid"
+	UsageByCluster   = "clusterid"
+)
+
+// UsageTier is a resolution heartbeat samples are kept at and for how long, resolution 0 keeps them raw
+type UsageTier struct {
+	Resolution time.Duration
+	Retention  time.Duration
+}
+
+// UsageTiers each heartbeat sample is written to, finest first
+var UsageTiers = []UsageTier{
+	{Resolution: 0, Retention: 24 * time.Hour},
+	{Resolution: 5 * time.Minute, Retention: 30 * 24 * time.Hour},
+}
+
+// ensureUsageIndexes lets mongodb remove expired usage records and find them by scope and time
+func (p *DB) ensureUsageIndexes() error {
+	session := p.session.Copy()
+	defer session.Close()
+
+	c := p.collection(session, "usage")
+	if err := c.EnsureIndex(mgo.Index{Key: []string{"expireat"}, ExpireAfter: time.Second}); err != nil {
+		return err
+	}
+	for _, scope := range []string{UsageByNamespace, UsageByTeam, UsageByCluster} {
+		if err := c.EnsureIndexKey(scope, "resolution", "time"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// recordUsage writes the usage and limits of each namespace of the heartbeat to every usage tier
+func (p *DB) recordUsage(session *mgo.Session, log *logger.Logger, clusterID string, namespaces map[string]NamespaceRecord,
+	metrics *common_proto.DCHeartbeatReport_Metrics, now time.Time) {
+	c := p.collection(session, "usage")
+	for nsID, r := range metrics.NsUsed {
+		ns, ok := namespaces[nsID]
+		if !ok || r == nil {
+			continue
+		}
+		for _, tier := range UsageTiers {
+			resolution := int64(tier.Resolution / time.Second)
+			if resolution == 0 {
+				err := c.Insert(UsageRecord{
+					NamespaceID:     nsID,
+					TeamID:          ns.TeamID,
+					ClusterID:       clusterID,
+					Time:            now.Unix(),
+					Samples:         1,
+					CpuSum:          uint64(r.CPU),
+					MemSum:          uint64(r.Memory),
+					StorageSum:      uint64(r.Storage),
+					CpuLimitSum:     uint64(ns.CpuLimit),
+					MemLimitSum:     uint64(ns.MemLimit),
+					StorageLimitSum: uint64(ns.StorageLimit),
+					ExpireAt:        now.Add(tier.Retention),
+				})
+				if err != nil {
+					log.Warn("cannot record usage", logger.Fields{"namespace_id": nsID, "error": err})
+				}
+				continue
+			}
+
+			start := now.Truncate(tier.Resolution)
+			if _, err := c.Upsert(bson.M{
+				"namespaceid": nsID,
+				"resolution":  resolution,
+				"time":        start.Unix(),
+			}, bson.M{
+				"$inc": bson.M{
+					"samples":         1,
+					"cpusum":          uint64(r.CPU),
+					"memsum":          uint64(r.Memory),
+					"storagesum":      uint64(r.Storage),
+					"cpulimitsum":     uint64(ns.CpuLimit),
+					"memlimitsum":     uint64(ns.MemLimit),
+					"storagelimitsum": uint64(ns.StorageLimit),
+				},
+				"$setOnInsert": bson.M{
+					"teamid":    ns.TeamID,
+					"clusterid": clusterID,
+					"expireat":  start.Add(tier.Retention),
+				},
+			}); err != nil {
+				log.Warn("cannot record usage", logger.Fields{"namespace_id": nsID, "resolution": tier.Resolution.String(),
+					"error": err})
+			}
+		}
+	}
+}
+
+func (p *DB) GetUsage(scope, id string, from, to int64) ([]UsagePoint, error) {
+	session := p.session.Clone()
+	defer session.Close()
+
+	tier := usageTier(scope, time.Unix(from, 0), time.Now())
+	var records []UsageRecord
+	if err := p.collection(session, "usage").Find(bson.M{
+		scope:        id,
+		"resolution": int64(tier.Resolution / time.Second),
+		"time":       bson.M{"$gte": from, "$lt": to},
+	}).Sort("time").All(&records); err != nil {
+		return nil, errors.New(ankr_default.DbError + err.Error())
+	}
+
+	return usagePoints(records), nil
+}
+
+func (p *DB) GetUsageRecords(resolution, from, to int64) ([]UsageRecord, error) {
+	session := p.session.Clone()
+	defer session.Close()
+
+	var records []UsageRecord
+	if err := p.collection(session, "usage").Find(bson.M{
+		"resolution": resolution,
+		"time":       bson.M{"$gte": from, "$lt": to},
+	}).All(&records); err != nil {
+		return nil, errors.New(ankr_default.DbError + err.Error())
+	}
+
+	return records, nil
+}
+
+// usageTier is the finest tier still holding samples from the start of the range. Teams and clusters
+// sum several namespaces, whose raw samples are not taken at the same times, so they use buckets.
+func usageTier(scope string, from, now time.Time) UsageTier {
+	var coarsest UsageTier
+	for _, tier := range UsageTiers {
+		if tier.Resolution == 0 && scope != UsageByNamespace {
+			continue
+		}
+		if !from.Before(now.Add(-tier.Retention)) {
+			return tier
+		}
+		coarsest = tier
+	}
+	return coarsest
+}
+
+// usagePoints averages each record over its samples and sums the namespaces at each time
+func usagePoints(records []UsageRecord) []UsagePoint {
+	points := make([]UsagePoint, 0)
+	index := make(map[int64]int)
+	for _, r := range records {
+		if r.Samples == 0 {
+			continue
+		}
+		i, ok := index[r.Time]
+		if !ok {
+			i = len(points)
+			index[r.Time] = i
+			points = append(points, UsagePoint{Time: r.Time})
+		}
+		points[i].Cpu += float64(r.CpuSum) / float64(r.Samples)
+		points[i].Mem += float64(r.MemSum) / float64(r.Samples)
+		points[i].Storage += float64(r.StorageSum) / float64(r.Samples)
+	}
+	sort.Slice(points, func(i, j int) bool { return points[i].Time < points[j].Time })
+	return points
+}
+
+func (p *DB) SaveMeteringRecords(records []MeteringRecord) error {
+	session := p.session.Copy()
+	defer session.Close()
+
+	c := p.collection(session, "metering")
+	for _, record := range records {
+		if _, err := c.Upsert(bson.M{"id": record.ID}, record); err != nil {
+			return errors.New(ankr_default.DbError + err.Error())
+		}
+	}
+	return nil
+}
+
+func (p *DB) GetMeteredUntil() (int64, error) {
+	session := p.session.Clone()
+	defer session.Close()
+
+	var state struct{ MeteredUntil int64 }
+	if err := p.collection(session, "meteringstate").Find(bson.M{"id": "metering"}).One(&state); err != nil {
+		if err == mgo.ErrNotFound {
+			return 0, err
+		}
+		return 0, errors.New(ankr_default.DbError + err.Error())
+	}
+	return state.MeteredUntil, nil
+}
+
+func (p *DB) SetMeteredUntil(end int64) error {
+	session := p.session.Copy()
+	defer session.Close()
+
+	if _, err := p.collection(session, "meteringstate").Upsert(bson.M{"id": "metering"},
+		bson.M{"$set": bson.M{"metereduntil": end}}); err != nil {
+		return errors.New(ankr_default.DbError + err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package dbservice
+
+import (
+	"testing"
+	"time"
+)
+
+func TestUsageTier(t *testing.T) {
+	now := time.Unix(1570000000, 0)
+	cases := []struct {
+		scope      string
+		from       time.Time
+		resolution time.Duration
+	}{
+		{UsageByNamespace, now.Add(-time.Hour), 0},
+		{UsageByNamespace, now.Add(-24 * time.Hour), 0},
+		{UsageByNamespace, now.Add(-25 * time.Hour), 5 * time.Minute},
+		{UsageByNamespace, now.Add(-90 * 24 * time.Hour), 5 * time.Minute},
+		{UsageByTeam, now.Add(-time.Hour), 5 * time.Minute},
+		{UsageByCluster, now.Add(-7 * 24 * time.Hour), 5 * time.Minute},
+	}
+	for _, c := range cases {
+		if tier := usageTier(c.scope, c.from, now); tier.Resolution != c.resolution {
+			t.Errorf("%s from %s ago uses %s, want %s", c.scope, now.Sub(c.from), tier.Resolution, c.resolution)
+		}
+	}
+}
+
+func TestUsagePoints(t *testing.T) {
+	points := usagePoints([]UsageRecord{
+		{NamespaceID: "ns-2", Time: 600, Samples: 2, CpuSum: 300, MemSum: 100, StorageSum: 10},
+		{NamespaceID: "ns-1", Time: 300, Samples: 3, CpuSum: 300, MemSum: 30, StorageSum: 3},
+		{NamespaceID: "ns-1", Time: 600, Samples: 1, CpuSum: 50, MemSum: 10, StorageSum: 1},
+		{NamespaceID: "ns-3", Time: 600},
+	})
+
+	want := []UsagePoint{
+		{Time: 300, Cpu: 100, Mem: 10, Storage: 1},
+		{Time: 600, Cpu: 200, Mem: 60, Storage: 6},
+	}
+	if len(points) != len(want) {
+		t.Fatalf("got %d points %+v, want %+v", len(points), points, want)
+	}
+	for i := range want {
+		if points[i] != want[i] {
+			t.Errorf("point %d is %+v, want %+v", i, points[i], want[i])
+		}
+	}
+}
@@ -1,21 +1,21 @@
 package handler
 
 import (
+	"github.com/Ankr-network/dccn-appmgr/logger"
 	appmgr "github.com/Ankr-network/dccn-common/protos/appmgr/v1/grpc"
 	//common_proto "github.com/Ankr-network/dccn-common/protos/common"
 	"context"
-	"log"
 )
 
 func (p *AppMgrHandler) NamespaceCount(ctx context.Context,
 	req *appmgr.NamespaceCountRequest) (*appmgr.NamespaceCountResponse, error) {
-	log.Printf(">>>>>>>>>Debug into NamespaceCount %+v\nctx: %+v \n", req, ctx)
 	rsp := &appmgr.NamespaceCountResponse{}
+	log := p.logger.WithContext(ctx)
 
 	if len(req.ClusterId) > 0 {
 		nsCount, err := p.db.CountRunningNamespacesByClusterID(req.ClusterId)
 		if err != nil {
-			log.Printf("CountRunningNamespacesByClusterID error %v", err)
+			log.Error("cannot count running namespaces", logger.Fields{"cluster_id": req.ClusterId, "error": err})
 			return rsp, err
 		}
 
@@ -23,7 +23,7 @@ func (p *AppMgrHandler) NamespaceCount(ctx context.Context,
 
 		appCount, err := p.db.CountRunningAppsByClusterID(req.ClusterId)
 		if err != nil {
-			log.Printf("CountRunningAppsByClusterID error %v", err)
+			log.Error("cannot count running apps", logger.Fields{"cluster_id": req.ClusterId, "error": err})
 			return rsp, err
 		}
 
@@ -31,7 +31,7 @@ func (p *AppMgrHandler) NamespaceCount(ctx context.Context,
 	} else {
 		nsCount, err := p.db.CountRunningNamespaces()
 		if err != nil {
-			log.Printf("CountRunningNamespaces error %v", err)
+			log.Error("cannot count running namespaces", logger.Fields{"error": err})
 			return rsp, err
 		}
 
@@ -39,7 +39,7 @@ func (p *AppMgrHandler) NamespaceCount(ctx context.Context,
 
 		appCount, err := p.db.CountRunningApps()
 		if err != nil {
-			log.Printf("CountRunningApps error %v", err)
+			log.Error("cannot count running apps", logger.Fields{"error": err})
 			return rsp, err
 		}
 
@@ -2,13 +2,14 @@ package handler
 
 import (
 	"context"
-	"errors"
+	"github.com/Ankr-network/dccn-appmgr/logger"
 	appmgr "github.com/Ankr-network/dccn-common/protos/appmgr/v1/grpc"
 	common_proto "github.com/Ankr-network/dccn-common/protos/common"
 	common_util "github.com/Ankr-network/dccn-common/util"
 	"github.com/golang/protobuf/ptypes/timestamp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"gopkg.in/mgo.v2/bson"
-	"log"
 	"time"
 )
 
@@ -18,12 +19,13 @@ func (p *AppMgrHandler) NamespaceList(ctx context.Context, req *common_proto.Emp
 
 	_, teamId := common_util.GetUserIDAndTeamID(ctx)
 	if len(teamId) == 0 {
-		return rsp, errors.New("timeId not found in context")
+		return rsp, status.Error(codes.Unauthenticated, "teamId not found in context")
 	}
 
+	log := p.logger.WithContext(ctx)
 	namespaceRecords, err := p.db.GetAllNamespaces(teamId)
 	if err != nil {
-		log.Printf("GetAllNamespaces error: %v", err)
+		log.Error("cannot get namespaces", logger.Fields{"error": err})
 		return rsp, err
 	}
 
@@ -40,7 +42,7 @@ func (p *AppMgrHandler) NamespaceList(ctx context.Context, req *common_proto.Emp
 		if !ns.Hidden {
 			namespaceMessage := convertFromNamespaceRecord(ns)
 			if len(ns.ClusterID) == 0 {
-				log.Printf("clusterID for ns %+v is empty, skip check connection", ns)
+				log.Debug("namespace has no cluster, skip check connection", logger.Fields{"namespace_id": ns.ID})
 			} else {
 				clusterConnection, err := p.db.GetClusterConnection(ns.ClusterID)
 				if err != nil || clusterConnection.Status == common_proto.DCStatus_UNAVAILABLE {
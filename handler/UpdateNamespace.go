@@ -1,49 +1,52 @@
 package handler
 
 import (
+	"context"
+	"errors"
+	"github.com/Ankr-network/dccn-appmgr/logger"
 	ankr_default "github.com/Ankr-network/dccn-common/protos"
 	appmgr "github.com/Ankr-network/dccn-common/protos/appmgr/v1/grpc"
 	common_proto "github.com/Ankr-network/dccn-common/protos/common"
 	common_util "github.com/Ankr-network/dccn-common/util"
 	"google.golang.org/grpc/status"
-	"context"
-	"log"
-	"errors"
 )
 
 // UpdateNamespace will update a namespace with cpu/mem/storage limit
 func (p *AppMgrHandler) UpdateNamespace(ctx context.Context,
 	req *appmgr.UpdateNamespaceRequest) (*common_proto.Empty, error) {
 
-	log.Printf(">>>>>>>>>Debug into UpdateNamespace: %+v\nctx: %+v\n", req, ctx)
 	_, teamId := common_util.GetUserIDAndTeamID(ctx)
+	log := p.logger.WithContext(ctx)
 
 	if req.Namespace == nil || (req.Namespace.NsCpuLimit == 0 ||
 		req.Namespace.NsMemLimit == 0 || req.Namespace.NsStorageLimit == 0) {
-		log.Printf("invalid input: empty namespace properties not accepted \n")
+		log.Warn("invalid input: empty namespace properties not accepted")
 		return &common_proto.Empty{}, ankr_default.ErrNsEmpty
 	}
+	log = log.With(logger.Fields{"namespace_id": req.Namespace.NsId})
 
 	namespaceRecord, err := p.db.GetNamespace(req.Namespace.NsId)
 	if err != nil {
-		log.Println(err.Error())
+		log.Error("cannot get namespace", logger.Fields{"error": err})
 		return &common_proto.Empty{}, err
 	}
 	if err := checkNsId(teamId, namespaceRecord.TeamID); err != nil {
-		log.Println(err.Error())
+		log.Warn("namespace not owned by team", logger.Fields{"error": err})
 		return &common_proto.Empty{}, err
 	}
 
 	if namespaceRecord.Status != common_proto.NamespaceStatus_NS_RUNNING &&
 		namespaceRecord.Status != common_proto.NamespaceStatus_NS_UPDATE_FAILED {
-		log.Println("namespace status is not running, cannot update")
-		return &common_proto.Empty{}, ankr_default.ErrNSStatusCanNotUpdate
+		log.Warn("namespace status is not running, cannot update", logger.Fields{"status": namespaceRecord.Status.String()})
+		return &common_proto.Empty{}, statusBlocked("namespace/"+namespaceRecord.ID, namespaceRecord.Status.String(),
+			ankr_default.ErrNSStatusCanNotUpdate)
 	}
 
 	clusterConnection, err := p.db.GetClusterConnection(namespaceRecord.ClusterID)
 	if err != nil || clusterConnection.Status != common_proto.DCStatus_AVAILABLE {
-		log.Println("cluster connection not available, namespace can not be updated")
-		return &common_proto.Empty{}, errors.New("cluster connection not available, namespace can not be updated")
+		log.Warn("cluster connection not available, namespace can not be updated",
+			logger.Fields{"cluster_id": namespaceRecord.ClusterID})
+		return &common_proto.Empty{}, clusterUnavailable(namespaceRecord.ClusterID, "namespace can not be updated")
 	}
 
 	namespaceReport := convertFromNamespaceRecord(namespaceRecord)
@@ -59,15 +62,13 @@ func (p *AppMgrHandler) UpdateNamespace(ctx context.Context,
 		OpPayload: &common_proto.DCStream_Namespace{Namespace: namespaceReport.Namespace},
 	}
 
-	if err := p.deployApp.Publish(&event); err != nil {
-		log.Println(err.Error())
+	if err := p.publish(ctx, &event); err != nil {
 		return &common_proto.Empty{}, errors.New(ankr_default.PublishError + err.Error())
 	}
 	// TODO: wait deamon notify
 	if err := p.db.UpdateNamespace(req.Namespace); err != nil {
-		log.Printf("Err Status: %s, Err Message: %s", status.Code(err), err.Error())
+		log.Error("cannot update namespace", logger.Fields{"code": status.Code(err).String(), "error": err})
 		return &common_proto.Empty{}, err
 	}
 	return &common_proto.Empty{}, nil
 }
-
@@ -2,13 +2,12 @@ package handler
 
 import (
 	"context"
+	"github.com/Ankr-network/dccn-appmgr/logger"
 	appmgr "github.com/Ankr-network/dccn-common/protos/appmgr/v1/grpc"
-	"log"
 )
 
 func (p *AppMgrHandler) AppCount(ctx context.Context,
 	req *appmgr.AppCountRequest) (*appmgr.AppCountResponse, error) {
-	log.Printf(">>>>>>>>>Debug into AppCount %+v\nctx: %+v \n", req, ctx)
 	rsp := &appmgr.AppCountResponse{}
 	if len(req.TeamId) == 0 || len(req.ClusterId) == 0 {
 		rsp.AppCount = 0
@@ -16,7 +15,8 @@ func (p *AppMgrHandler) AppCount(ctx context.Context,
 
 	apps, err := p.db.GetRunningAppsByTeamIDAndClusterID(req.TeamId, req.ClusterId)
 	if err != nil {
-		log.Println(err.Error())
+		p.logger.WithContext(ctx).Error("cannot get running apps", logger.Fields{"team_id": req.TeamId,
+			"cluster_id": req.ClusterId, "error": err})
 		return rsp, err
 	}
 
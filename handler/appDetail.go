@@ -1,36 +1,32 @@
 package handler
 
 import (
+	"context"
+	"github.com/Ankr-network/dccn-appmgr/logger"
 	ankr_default "github.com/Ankr-network/dccn-common/protos"
 	appmgr "github.com/Ankr-network/dccn-common/protos/appmgr/v1/grpc"
 	common_proto "github.com/Ankr-network/dccn-common/protos/common"
-	"log"
-	"context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 func (p *AppMgrHandler) AppDetail(ctx context.Context, req *appmgr.AppID) (*appmgr.AppDetailResponse, error) {
-	log.Printf(">>>>>>>>>Debug into AppDetail: %+v\nctx: %+v \n", req, ctx)
 
 	rsp := &appmgr.AppDetailResponse{}
+	log := p.logger.WithContext(ctx).With(logger.Fields{"app_id": req.AppId})
 	appRecord, err := p.db.GetApp(req.AppId)
 	if err != nil {
-		log.Println(err.Error())
-		return rsp, err
-	}
-
-	log.Printf("appMessage  %+v \n", appRecord)
-	if err != nil {
-		log.Println(err.Error())
+		log.Error("cannot get app", logger.Fields{"error": err})
 		return rsp, err
 	}
 
 	if appRecord.Hidden == true {
-		log.Printf("app id %s already purged \n", req.AppId)
-		return rsp, ankr_default.ErrAlreadyPurged
+		log.Warn("app already purged")
+		return rsp, status.Error(codes.NotFound, ankr_default.ErrAlreadyPurged.Error())
 	}
 
-	appMessage := convertToAppMessage(appRecord, p.db)
-	log.Printf("appMessage %+v \n", appMessage)
+	p.setUpgradeHeader(ctx, appRecord)
+	appMessage := p.convertToAppMessage(ctx, appRecord)
 	rsp.AppReport = &appMessage
 
 	event := common_proto.DCStream{
@@ -38,8 +34,7 @@ func (p *AppMgrHandler) AppDetail(ctx context.Context, req *appmgr.AppID) (*appm
 		OpPayload: &common_proto.DCStream_AppDeployment{AppDeployment: appMessage.AppDeployment},
 	}
 
-	if err := p.deployApp.Publish(&event); err != nil {
-		log.Println(err.Error())
+	if err := p.publish(ctx, &event); err != nil {
 		return rsp, ankr_default.ErrPublish
 	}
 
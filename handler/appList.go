@@ -2,24 +2,23 @@ package handler
 
 import (
 	"context"
+	"github.com/Ankr-network/dccn-appmgr/logger"
 	appmgr "github.com/Ankr-network/dccn-common/protos/appmgr/v1/grpc"
 	common_proto "github.com/Ankr-network/dccn-common/protos/common"
 	common_util "github.com/Ankr-network/dccn-common/util"
 	"github.com/golang/protobuf/ptypes/timestamp"
 	"gopkg.in/mgo.v2/bson"
-	"log"
 	"time"
 )
 
 func (p *AppMgrHandler) AppList(ctx context.Context, req *common_proto.Empty) (*appmgr.AppListResponse, error) {
 	_, teamId := common_util.GetUserIDAndTeamID(ctx)
-	log.Printf(">>>>>>>>>Debug into AppList, ctx: %+v \n", ctx)
 
+	log := p.logger.WithContext(ctx)
 	apps, err := p.db.GetAllApps(teamId)
 	rsp := &appmgr.AppListResponse{}
-	log.Printf("appMessage  %+v \n", apps)
 	if err != nil {
-		log.Println(err.Error())
+		log.Error("cannot get apps", logger.Fields{"error": err})
 		return rsp, err
 	}
 
@@ -29,14 +28,13 @@ func (p *AppMgrHandler) AppList(ctx context.Context, req *common_proto.Empty) (*
 		if !apps[i].Hidden && (apps[i].Status == common_proto.AppStatus_APP_CANCELED ||
 			apps[i].Status == common_proto.AppStatus_APP_CANCELING) && (apps[i].LastModifiedDate == nil ||
 			apps[i].LastModifiedDate.Seconds < (time.Now().Unix()-7200)) {
-			log.Printf("Canceled App time exceeded 7200 seconds, Now: %+v \n", time.Now().Unix())
+			log.Debug("hide app canceled over 7200 seconds ago", logger.Fields{"app_id": apps[i].ID})
 			apps[i].Hidden = true
 			p.db.Update("app", apps[i].ID, bson.M{"$set": bson.M{"hidden": true,
 				"lastmodifieddate": &timestamp.Timestamp{Seconds: time.Now().Unix()}}})
 		}
 		if !apps[i].Hidden {
-			appMessage := convertToAppMessage(apps[i], p.db)
-			log.Printf("appMessage  %+v \n", appMessage)
+			appMessage := p.convertToAppMessage(ctx, apps[i])
 			if len(appMessage.AppDeployment.Namespace.ClusterId) > 0 {
 				clusterConnection, err := p.db.GetClusterConnection(appMessage.AppDeployment.Namespace.ClusterId)
 				if err != nil || clusterConnection.Status == common_proto.DCStatus_UNAVAILABLE {
@@ -50,14 +48,14 @@ func (p *AppMgrHandler) AppList(ctx context.Context, req *common_proto.Empty) (*
 					OpType:    common_proto.DCOperation_APP_DETAIL,
 					OpPayload: &common_proto.DCStream_AppDeployment{AppDeployment: appMessage.AppDeployment},
 				}
-				if err := p.deployApp.Publish(&event); err != nil {
-					log.Println(err.Error())
-				}
+				// publish logs its failure, the list does not depend on it
+				_ = p.publish(ctx, &event)
 			}
 		}
 	}
 
 	rsp.AppReports = appsWithoutHidden
+	p.setUpgradeHeader(ctx, apps...)
 
 	return rsp, nil
 }
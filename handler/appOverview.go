@@ -2,27 +2,26 @@ package handler
 
 import (
 	"context"
+	"github.com/Ankr-network/dccn-appmgr/logger"
 	appmgr "github.com/Ankr-network/dccn-common/protos/appmgr/v1/grpc"
 	common_proto "github.com/Ankr-network/dccn-common/protos/common"
 	common_util "github.com/Ankr-network/dccn-common/util"
-	"log"
 )
 
 func (p *AppMgrHandler) AppOverview(ctx context.Context, req *common_proto.Empty) (*appmgr.AppOverviewResponse, error) {
-	log.Printf(">>>>>>>>>Debug into AppOverview, ctx: %+v\n", ctx)
 	rsp := &appmgr.AppOverviewResponse{}
 	_, teamId := common_util.GetUserIDAndTeamID(ctx)
+	log := p.logger.WithContext(ctx)
 
 	nss, err := p.db.GetRunningNamespaces(teamId)
 	if err != nil {
-		log.Println(err.Error())
+		log.Error("cannot get running namespaces", logger.Fields{"error": err})
 		return rsp, err
 	}
 
 	rsp.NamespaceCount = uint32(len(nss))
 
 	for _, ns := range nss {
-		log.Printf("caculating ns %+v", ns)
 		rsp.CpuTotal += float32(ns.CpuLimit)
 		rsp.CpuUsage += float32(ns.CpuUsage)
 		rsp.MemTotal += float32(ns.MemLimit)
@@ -32,7 +31,7 @@ func (p *AppMgrHandler) AppOverview(ctx context.Context, req *common_proto.Empty
 	}
 
 	if clusterConnections, err := p.db.GetAvailableClusterConnections(); err != nil {
-		log.Printf("GetAvailableClusterConnections error: %v", err)
+		log.Error("cannot get available cluster connections", logger.Fields{"error": err})
 	} else {
 		rsp.ClusterCount = uint32(len(clusterConnections))
 		for _, connection := range clusterConnections {
@@ -43,7 +42,7 @@ func (p *AppMgrHandler) AppOverview(ctx context.Context, req *common_proto.Empty
 		}
 	}
 
-	charts, err := getCharts("team", "stable")
+	charts, err := p.chartIndex(ctx, "team", "stable")
 	if err != nil {
 		return rsp, err
 	}
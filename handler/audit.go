@@ -0,0 +1,26 @@
+package handler
+
+import (
+	"context"
+	"time"
+
+	db "github.com/Ankr-network/dccn-appmgr/db_service"
+	"github.com/Ankr-network/dccn-appmgr/logger"
+	common_util "github.com/Ankr-network/dccn-common/util"
+	"github.com/golang/protobuf/ptypes/timestamp"
+)
+
+// audit records an operator action before it is done, an action which can not be recorded is not done
+func (p *AppMgrHandler) audit(ctx context.Context, operation, target, detail string) error {
+	userId, _ := common_util.GetUserIDAndTeamID(ctx)
+	p.logger.WithContext(ctx).Info("audit", logger.Fields{"operation": operation, "target": target, "user_id": userId,
+		"detail": detail})
+
+	return p.db.CreateAudit(db.AuditRecord{
+		UserID:    userId,
+		Operation: operation,
+		Target:    target,
+		Detail:    detail,
+		Date:      &timestamp.Timestamp{Seconds: time.Now().Unix()},
+	})
+}
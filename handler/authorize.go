@@ -0,0 +1,213 @@
+package handler
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Ankr-network/dccn-appmgr/logger"
+	"github.com/Ankr-network/dccn-appmgr/protos/appmgrext"
+	ankr_default "github.com/Ankr-network/dccn-common/protos"
+	appmgr "github.com/Ankr-network/dccn-common/protos/appmgr/v1/grpc"
+	common_util "github.com/Ankr-network/dccn-common/util"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gopkg.in/mgo.v2"
+)
+
+// roles of team members, each role may call all the rpcs the roles before it may
+const (
+	RoleViewer   = "viewer"
+	RoleDeployer = "deployer"
+	RoleAdmin    = "admin"
+	// RoleOperator is not a team role, it belongs to the users configured as operators of the service
+	RoleOperator = "operator"
+)
+
+var roleRanks = map[string]int{
+	RoleViewer:   1,
+	RoleDeployer: 2,
+	RoleAdmin:    3,
+}
+
+// rpcRoles is the least role needed to call each rpc, rpcs not listed are denied to everyone
+var rpcRoles = map[string]string{
+	"AppList":                RoleViewer,
+	"AppDetail":              RoleViewer,
+	"AppCount":               RoleViewer,
+	"AppOverview":            RoleViewer,
+	"AppUpgrades":            RoleViewer,
+	"ChartList":              RoleViewer,
+	"ChartDetail":            RoleViewer,
+	"ListCharts":             RoleViewer,
+	"GetChartDetail":         RoleViewer,
+	"DownloadChart":          RoleViewer,
+	"DownloadChartStream":    RoleViewer,
+	"SearchCharts":           RoleViewer,
+	"PreviewApp":             RoleViewer,
+	"NamespaceList":          RoleViewer,
+	"NamespaceCount":         RoleViewer,
+	"UsageHistory":           RoleViewer,
+	"CreateApp":              RoleDeployer,
+	"UpdateApp":              RoleDeployer,
+	"CancelApp":              RoleDeployer,
+	"SetUpgradePolicy":       RoleDeployer,
+	"UploadChart":            RoleDeployer,
+	"UploadChartStream":      RoleDeployer,
+	"SaveAsChart":            RoleDeployer,
+	"CreateNamespace":        RoleDeployer,
+	"UpdateNamespace":        RoleDeployer,
+	"PurgeApp":               RoleAdmin,
+	"DeleteChart":            RoleAdmin,
+	"DeleteChartVersion":     RoleAdmin,
+	"PromoteChart":           RoleAdmin,
+	"DeleteNamespace":        RoleAdmin,
+	"SetMemberRole":          RoleAdmin,
+	"OperatorListApps":       RoleOperator,
+	"OperatorListNamespaces": RoleOperator,
+	"OperatorSetStatus":      RoleOperator,
+	"OperatorRepublish":      RoleOperator,
+	"DrainCluster":           RoleOperator,
+	"ClusterUsageHistory":    RoleOperator,
+}
+
+// operatorTeamRpcs are the team rpcs operators may call in any team, to give a team its first admin
+var operatorTeamRpcs = map[string]bool{
+	"SetMemberRole": true,
+}
+
+// identity returns the user and team of the caller, a variable so tests can set them
+var identity = common_util.GetUserIDAndTeamID
+
+// AuthInterceptor checks the caller may call the rpc and owns the app, namespace or team the request names
+func (p *AppMgrHandler) AuthInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler) (interface{}, error) {
+	method := info.FullMethod[strings.LastIndex(info.FullMethod, "/")+1:]
+	if err := p.authorize(ctx, method, req); err != nil {
+		p.logger.WithContext(ctx).Warn("rpc denied", logger.Fields{"method": method, "error": err})
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// AuthStreamInterceptor checks the caller may call the streaming rpc. The chart streams only name charts of
+// the team of the caller, so there is no request to check the owner of.
+func (p *AppMgrHandler) AuthStreamInterceptor(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler) error {
+	method := info.FullMethod[strings.LastIndex(info.FullMethod, "/")+1:]
+	if err := p.authorize(stream.Context(), method, nil); err != nil {
+		p.logger.WithContext(stream.Context()).Warn("rpc denied", logger.Fields{"method": method, "error": err})
+		return err
+	}
+	return handler(srv, stream)
+}
+
+// authorize checks the role of the caller in its team allows the rpc, and that the request stays in the team
+func (p *AppMgrHandler) authorize(ctx context.Context, method string, req interface{}) error {
+	required, ok := rpcRoles[method]
+	if !ok {
+		return status.Errorf(codes.PermissionDenied, "rpc %s is not allowed", method)
+	}
+
+	userId, teamId := identity(ctx)
+	if len(userId) == 0 || len(teamId) == 0 {
+		return status.Error(codes.Unauthenticated, ankr_default.ErrUserNotExist.Error())
+	}
+
+	if required == RoleOperator || (operatorTeamRpcs[method] && p.operators[userId]) {
+		if !p.operators[userId] {
+			return status.Errorf(codes.PermissionDenied, "%s is for operators only", method)
+		}
+		return nil
+	}
+
+	role, err := p.memberRole(teamId, userId)
+	if err != nil {
+		return err
+	}
+	if roleRanks[role] < roleRanks[required] {
+		return status.Errorf(codes.PermissionDenied, "%s role can not call %s, %s role needed", role, method, required)
+	}
+
+	return p.checkRequestOwner(teamId, req)
+}
+
+// memberRole is the role set for the user in the team, or the default role if none is set. Members of teams
+// in which no role is set yet are admins, as every member was before roles, so they can set the first ones.
+func (p *AppMgrHandler) memberRole(teamId, userId string) (string, error) {
+	role, err := p.db.GetMemberRole(teamId, userId)
+	if err != mgo.ErrNotFound {
+		return role, err
+	}
+	roles, err := p.db.CountMemberRoles(teamId)
+	if err != nil {
+		return "", err
+	}
+	if len(roles) == 0 {
+		return RoleAdmin, nil
+	}
+	return p.defaultRole, nil
+}
+
+// checkRequestOwner checks the app, namespace or team the request names belongs to the team
+func (p *AppMgrHandler) checkRequestOwner(teamId string, req interface{}) error {
+	switch req := req.(type) {
+	case *appmgr.AppID:
+		return p.checkAppOwner(teamId, req.AppId)
+	case *appmgr.UpdateAppRequest:
+		if req.AppDeployment != nil {
+			return p.checkAppOwner(teamId, req.AppDeployment.AppId)
+		}
+	case *appmgrext.AppUpgradesRequest:
+		if len(req.AppId) > 0 {
+			return p.checkAppOwner(teamId, req.AppId)
+		}
+	case *appmgrext.SetUpgradePolicyRequest:
+		return p.checkAppOwner(teamId, req.AppId)
+	case *appmgr.CreateAppRequest:
+		if req.App != nil && len(req.App.GetNsId()) > 0 {
+			return p.checkNamespaceOwner(teamId, req.App.GetNsId())
+		}
+	case *appmgr.UpdateNamespaceRequest:
+		if req.Namespace != nil {
+			return p.checkNamespaceOwner(teamId, req.Namespace.NsId)
+		}
+	case *appmgr.DeleteNamespaceRequest:
+		return p.checkNamespaceOwner(teamId, req.NsId)
+	case *appmgrext.UsageHistoryRequest:
+		if len(req.NsId) > 0 {
+			return p.checkNamespaceOwner(teamId, req.NsId)
+		}
+	case *appmgr.AppCountRequest:
+		if len(req.TeamId) > 0 && req.TeamId != teamId {
+			return ankr_default.ErrUserNotOwn
+		}
+	case *appmgrext.SetMemberRoleRequest:
+		if len(req.TeamId) > 0 && req.TeamId != teamId {
+			return ankr_default.ErrUserNotOwn
+		}
+	}
+	return nil
+}
+
+func (p *AppMgrHandler) checkAppOwner(teamId, appId string) error {
+	appRecord, err := p.db.GetApp(appId)
+	if err != nil {
+		return err
+	}
+	if appRecord.TeamID != teamId {
+		return ankr_default.ErrUserNotOwn
+	}
+	return nil
+}
+
+func (p *AppMgrHandler) checkNamespaceOwner(teamId, nsId string) error {
+	namespaceRecord, err := p.db.GetNamespace(nsId)
+	if err != nil {
+		return err
+	}
+	if namespaceRecord.TeamID != teamId {
+		return ankr_default.ErrUserNotOwn
+	}
+	return nil
+}
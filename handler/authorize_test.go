@@ -0,0 +1,295 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	db "github.com/Ankr-network/dccn-appmgr/db_service"
+	"github.com/Ankr-network/dccn-appmgr/protos/appmgrext"
+	ankr_default "github.com/Ankr-network/dccn-common/protos"
+	appmgr "github.com/Ankr-network/dccn-common/protos/appmgr/v1/grpc"
+	common_proto "github.com/Ankr-network/dccn-common/protos/common"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gopkg.in/mgo.v2"
+)
+
+// authDB keeps the records authorization reads, other DBService methods are not used
+type authDB struct {
+	db.DBService
+	apps       map[string]db.AppRecord
+	namespaces map[string]db.NamespaceRecord
+	roles      map[string]string
+}
+
+func (d *authDB) GetApp(id string) (db.AppRecord, error) {
+	app, ok := d.apps[id]
+	if !ok {
+		return app, errors.New(ankr_default.DbError + mgo.ErrNotFound.Error())
+	}
+	return app, nil
+}
+
+func (d *authDB) GetNamespace(id string) (db.NamespaceRecord, error) {
+	ns, ok := d.namespaces[id]
+	if !ok {
+		return ns, errors.New(ankr_default.DbError + mgo.ErrNotFound.Error())
+	}
+	return ns, nil
+}
+
+func (d *authDB) GetMemberRole(teamId, userId string) (string, error) {
+	role, ok := d.roles[teamId+"/"+userId]
+	if !ok {
+		return "", mgo.ErrNotFound
+	}
+	return role, nil
+}
+
+func (d *authDB) SetMemberRole(teamId, userId, role string) error {
+	d.roles[teamId+"/"+userId] = role
+	return nil
+}
+
+func (d *authDB) CountMemberRoles(teamId string) (map[string]int, error) {
+	roles := map[string]int{}
+	for member, role := range d.roles {
+		if strings.HasPrefix(member, teamId+"/") {
+			roles[role]++
+		}
+	}
+	return roles, nil
+}
+
+type identityKey struct{}
+
+// withIdentity sets the caller seen by authorization, as the gateway metadata does
+func withIdentity(userId, teamId string) context.Context {
+	return context.WithValue(context.Background(), identityKey{}, [2]string{userId, teamId})
+}
+
+func testIdentity(ctx context.Context) (string, string) {
+	id, _ := ctx.Value(identityKey{}).([2]string)
+	return id[0], id[1]
+}
+
+// useTestIdentity makes authorization read the caller set by withIdentity until the returned func is called
+func useTestIdentity() func() {
+	saved := identity
+	identity = testIdentity
+	return func() { identity = saved }
+}
+
+func newAuthHandler() *AppMgrHandler {
+	return &AppMgrHandler{
+		db: &authDB{
+			apps: map[string]db.AppRecord{
+				"app-own":   {ID: "app-own", TeamID: "team-1"},
+				"app-other": {ID: "app-other", TeamID: "team-2"},
+			},
+			namespaces: map[string]db.NamespaceRecord{
+				"ns-own":   {ID: "ns-own", TeamID: "team-1"},
+				"ns-other": {ID: "ns-other", TeamID: "team-2"},
+			},
+			roles: map[string]string{
+				"team-1/viewer":   RoleViewer,
+				"team-1/deployer": RoleDeployer,
+				"team-1/admin":    RoleAdmin,
+				"team-2/owner":    RoleAdmin,
+			},
+		},
+		defaultRole: RoleViewer,
+		operators:   map[string]bool{"operator": true},
+	}
+}
+
+// authRequests has a request of each rpc naming resources of team-1 and of team-2
+var authRequests = map[string][2]interface{}{
+	"AppList":             {&common_proto.Empty{}, nil},
+	"AppDetail":           {&appmgr.AppID{AppId: "app-own"}, &appmgr.AppID{AppId: "app-other"}},
+	"AppCount":            {&appmgr.AppCountRequest{TeamId: "team-1"}, &appmgr.AppCountRequest{TeamId: "team-2"}},
+	"AppOverview":         {&common_proto.Empty{}, nil},
+	"AppUpgrades":         {&appmgrext.AppUpgradesRequest{AppId: "app-own"}, &appmgrext.AppUpgradesRequest{AppId: "app-other"}},
+	"ChartList":           {&appmgr.ChartListRequest{ChartRepo: "user"}, nil},
+	"ChartDetail":         {&appmgr.ChartDetailRequest{}, nil},
+	"ListCharts":          {&appmgrext.ListChartsRequest{ChartRepo: "user"}, nil},
+	"GetChartDetail":      {&appmgrext.GetChartDetailRequest{}, nil},
+	"DownloadChart":       {&appmgr.DownloadChartRequest{}, nil},
+	"DownloadChartStream": {&appmgrext.DownloadChartStreamRequest{}, nil},
+	"SearchCharts":        {&appmgrext.SearchChartsRequest{Query: "redis"}, nil},
+	"PreviewApp":          {&appmgrext.PreviewAppRequest{}, nil},
+	"NamespaceList":       {&common_proto.Empty{}, nil},
+	"NamespaceCount":      {&appmgr.NamespaceCountRequest{}, nil},
+	"UsageHistory":        {&appmgrext.UsageHistoryRequest{NsId: "ns-own"}, &appmgrext.UsageHistoryRequest{NsId: "ns-other"}},
+	"CreateApp": {
+		&appmgr.CreateAppRequest{App: &common_proto.App{NamespaceData: &common_proto.App_NsId{NsId: "ns-own"}}},
+		&appmgr.CreateAppRequest{App: &common_proto.App{NamespaceData: &common_proto.App_NsId{NsId: "ns-other"}}},
+	},
+	"UpdateApp": {
+		&appmgr.UpdateAppRequest{AppDeployment: &common_proto.AppDeployment{AppId: "app-own"}},
+		&appmgr.UpdateAppRequest{AppDeployment: &common_proto.AppDeployment{AppId: "app-other"}},
+	},
+	"CancelApp":         {&appmgr.AppID{AppId: "app-own"}, &appmgr.AppID{AppId: "app-other"}},
+	"SetUpgradePolicy":  {&appmgrext.SetUpgradePolicyRequest{AppId: "app-own"}, &appmgrext.SetUpgradePolicyRequest{AppId: "app-other"}},
+	"UploadChart":       {&appmgr.UploadChartRequest{}, nil},
+	"UploadChartStream": {nil, nil},
+	"SaveAsChart":       {&appmgr.SaveAsChartRequest{}, nil},
+	"CreateNamespace":   {&appmgr.CreateNamespaceRequest{}, nil},
+	"UpdateNamespace": {
+		&appmgr.UpdateNamespaceRequest{Namespace: &common_proto.Namespace{NsId: "ns-own"}},
+		&appmgr.UpdateNamespaceRequest{Namespace: &common_proto.Namespace{NsId: "ns-other"}},
+	},
+	"PurgeApp":           {&appmgr.AppID{AppId: "app-own"}, &appmgr.AppID{AppId: "app-other"}},
+	"DeleteChart":        {&appmgr.DeleteChartRequest{}, nil},
+	"DeleteChartVersion": {&appmgrext.DeleteChartVersionRequest{}, nil},
+	"PromoteChart":       {&appmgrext.PromoteChartRequest{}, nil},
+	"DeleteNamespace":    {&appmgr.DeleteNamespaceRequest{NsId: "ns-own"}, &appmgr.DeleteNamespaceRequest{NsId: "ns-other"}},
+	"SetMemberRole":      {&appmgrext.SetMemberRoleRequest{}, &appmgrext.SetMemberRoleRequest{TeamId: "team-2"}},
+}
+
+func TestAuthorizeRoles(t *testing.T) {
+	defer useTestIdentity()()
+	p := newAuthHandler()
+
+	for method, required := range rpcRoles {
+		if required == RoleOperator {
+			continue
+		}
+		reqs, ok := authRequests[method]
+		if !ok {
+			t.Errorf("no request to test %s with", method)
+			continue
+		}
+		for _, role := range []string{RoleViewer, RoleDeployer, RoleAdmin} {
+			err := p.authorize(withIdentity(role, "team-1"), method, reqs[0])
+			if allowed := roleRanks[role] >= roleRanks[required]; allowed && err != nil {
+				t.Errorf("%s denied to %s: %v", method, role, err)
+			} else if !allowed && status.Code(err) != codes.PermissionDenied {
+				t.Errorf("%s by %s gives %v, want PermissionDenied", method, role, err)
+			}
+		}
+	}
+}
+
+func TestAuthorizeOwner(t *testing.T) {
+	defer useTestIdentity()()
+	p := newAuthHandler()
+
+	for method, reqs := range authRequests {
+		if reqs[1] == nil {
+			continue
+		}
+		if err := p.authorize(withIdentity("admin", "team-1"), method, reqs[1]); err != ankr_default.ErrUserNotOwn {
+			t.Errorf("%s of another team gives %v, want %v", method, err, ankr_default.ErrUserNotOwn)
+		}
+	}
+
+	err := p.authorize(withIdentity("admin", "team-1"), "AppDetail", &appmgr.AppID{AppId: "app-none"})
+	if code := status.Code(toStatus(err)); code != codes.NotFound {
+		t.Errorf("AppDetail of missing app gives %s, want NotFound", code)
+	}
+}
+
+func TestAuthorizeIdentity(t *testing.T) {
+	defer useTestIdentity()()
+	p := newAuthHandler()
+
+	if err := p.authorize(context.Background(), "AppList", &common_proto.Empty{}); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("anonymous AppList gives %v, want Unauthenticated", err)
+	}
+	if err := p.authorize(withIdentity("admin", "team-1"), "DropEverything", nil); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("unlisted rpc gives %v, want PermissionDenied", err)
+	}
+
+	// users without a role set get the default one
+	if err := p.authorize(withIdentity("newcomer", "team-1"), "AppList", &common_proto.Empty{}); err != nil {
+		t.Errorf("AppList by default role: %v", err)
+	}
+	if err := p.authorize(withIdentity("newcomer", "team-1"), "CancelApp", &appmgr.AppID{AppId: "app-own"}); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("CancelApp by default viewer role gives %v, want PermissionDenied", err)
+	}
+	// roles are per team
+	if err := p.authorize(withIdentity("admin", "team-2"), "PurgeApp", &appmgr.AppID{AppId: "app-other"}); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("PurgeApp by admin of another team gives %v, want PermissionDenied", err)
+	}
+}
+
+func TestAuthorizeOperator(t *testing.T) {
+	defer useTestIdentity()()
+	p := newAuthHandler()
+
+	for method, required := range rpcRoles {
+		if required != RoleOperator {
+			continue
+		}
+		if err := p.authorize(withIdentity("operator", "team-ops"), method, nil); err != nil {
+			t.Errorf("%s denied to operator: %v", method, err)
+		}
+		// being a team admin is not enough
+		if err := p.authorize(withIdentity("admin", "team-1"), method, nil); status.Code(err) != codes.PermissionDenied {
+			t.Errorf("%s by team admin gives %v, want PermissionDenied", method, err)
+		}
+	}
+}
+
+func TestAuthInterceptorCoversServer(t *testing.T) {
+	defer useTestIdentity()()
+	p := newAuthHandler()
+
+	// every rpc served has a role, and every role is of an rpc served
+	served := map[string]bool{}
+	for _, server := range []reflect.Type{
+		reflect.TypeOf((*appmgr.AppMgrServer)(nil)).Elem(),
+		reflect.TypeOf((*appmgrext.AppMgrExtServer)(nil)).Elem(),
+	} {
+		for i := 0; i < server.NumMethod(); i++ {
+			served[server.Method(i).Name] = true
+			if _, ok := rpcRoles[server.Method(i).Name]; !ok {
+				t.Errorf("rpc %s of %s has no role", server.Method(i).Name, server.Name())
+			}
+		}
+	}
+	for method := range rpcRoles {
+		if !served[method] {
+			t.Errorf("role of %s, which no service serves", method)
+		}
+	}
+
+	called := false
+	s := NewServer(p, StatusInterceptor, p.AuthInterceptor, func(ctx context.Context, req interface{},
+		info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		called = true
+		return nil, ankr_default.ErrAppNotExist
+	})
+	if _, err := s.PurgeApp(withIdentity("viewer", "team-1"), &appmgr.AppID{AppId: "app-own"}); status.Code(err) != codes.PermissionDenied || called {
+		t.Errorf("PurgeApp by viewer gives %v, handler called %v", err, called)
+	}
+	if _, err := s.AppDetail(withIdentity("viewer", "team-1"), &appmgr.AppID{AppId: "app-other"}); status.Code(err) != codes.PermissionDenied || called {
+		t.Errorf("AppDetail of another team gives %v, handler called %v", err, called)
+	}
+	if _, err := s.AppDetail(withIdentity("viewer", "team-1"), &appmgr.AppID{AppId: "app-own"}); !called {
+		t.Errorf("AppDetail of own app not passed on, %v", err)
+	}
+}
+
+func TestAuthStreamInterceptor(t *testing.T) {
+	defer useTestIdentity()()
+	p := newAuthHandler()
+
+	called := false
+	s := NewExtServer(p, nil, []grpc.StreamServerInterceptor{StreamStatusInterceptor, p.AuthStreamInterceptor,
+		func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			called = true
+			return nil
+		}})
+	if err := s.UploadChartStream(&fakeChartStream{ctx: withIdentity("viewer", "team-1")}); status.Code(err) != codes.PermissionDenied || called {
+		t.Errorf("UploadChartStream by viewer gives %v, handler called %v", err, called)
+	}
+	if err := s.DownloadChartStream(&appmgrext.DownloadChartStreamRequest{}, &fakeChartStream{ctx: withIdentity("viewer", "team-1")}); err != nil || !called {
+		t.Errorf("DownloadChartStream by viewer not passed on, %v", err)
+	}
+}
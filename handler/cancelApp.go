@@ -2,46 +2,46 @@ package handler
 
 import (
 	"context"
+	"github.com/Ankr-network/dccn-appmgr/logger"
 	ankr_default "github.com/Ankr-network/dccn-common/protos"
 	appmgr "github.com/Ankr-network/dccn-common/protos/appmgr/v1/grpc"
 	common_proto "github.com/Ankr-network/dccn-common/protos/common"
 	common_util "github.com/Ankr-network/dccn-common/util"
 	"github.com/golang/protobuf/ptypes/timestamp"
 	"gopkg.in/mgo.v2/bson"
-	"log"
 	"time"
 )
 
 func (p *AppMgrHandler) CancelApp(ctx context.Context, req *appmgr.AppID) (*common_proto.Empty, error) {
 	_, teamId := common_util.GetUserIDAndTeamID(ctx)
-	log.Printf(">>>>>>>>>Debug into CancelApp: %+v\nctx: %+v \n", req, ctx)
+	log := p.logger.WithContext(ctx).With(logger.Fields{"app_id": req.AppId})
 
 	if err := checkId(teamId, req.AppId); err != nil {
-		log.Println(err.Error())
+		log.Warn("invalid input: bad app id", logger.Fields{"error": err})
 		return &common_proto.Empty{}, err
 	}
 
-	app, err := p.checkOwner(teamId, req.AppId)
+	app, err := p.checkOwner(ctx, teamId, req.AppId)
 	if err != nil {
-		log.Println(err.Error())
+		log.Warn("cannot check owner of app", logger.Fields{"error": err})
 		return &common_proto.Empty{}, err
 	}
 
 	if app.AppStatus == common_proto.AppStatus_APP_UNAVAILABLE || app.AppStatus == common_proto.AppStatus_APP_FAILED {
-		log.Printf("app %s is unavailable or failed, cacel directly", req.AppId)
+		log.Info("app is unavailable or failed, cancel directly", logger.Fields{"status": app.AppStatus.String()})
 		if err := p.db.Update("app", req.AppId, bson.M{"$set": bson.M{
 			"status":           common_proto.AppStatus_APP_CANCELED,
 			"hidden":           true,
 			"lastmodifieddate": &timestamp.Timestamp{Seconds: time.Now().Unix()},
 		}}); err != nil {
-			log.Printf("Update app %s to canceled status error: %v", req.AppId, err)
+			log.Error("cannot mark app canceled", logger.Fields{"error": err})
 			return &common_proto.Empty{}, err
 		}
 		return &common_proto.Empty{}, nil
 	}
 
 	if app.AppStatus == common_proto.AppStatus_APP_CANCELED {
-		return &common_proto.Empty{}, ankr_default.ErrCanceledTwice
+		return &common_proto.Empty{}, statusBlocked("app/"+req.AppId, app.AppStatus.String(), ankr_default.ErrCanceledTwice)
 	}
 
 	/*
@@ -57,8 +57,7 @@ func (p *AppMgrHandler) CancelApp(ctx context.Context, req *appmgr.AppID) (*comm
 		OpPayload: &common_proto.DCStream_AppDeployment{AppDeployment: app.AppDeployment},
 	}
 
-	if err := p.deployApp.Publish(&event); err != nil {
-		log.Println(err.Error())
+	if err := p.publish(ctx, &event); err != nil {
 		return &common_proto.Empty{}, ankr_default.ErrPublish
 	}
 
@@ -66,7 +65,7 @@ func (p *AppMgrHandler) CancelApp(ctx context.Context, req *appmgr.AppID) (*comm
 		"status":           common_proto.AppStatus_APP_CANCELING,
 		"lastmodifieddate": &timestamp.Timestamp{Seconds: time.Now().Unix()},
 	}}); err != nil {
-		log.Println(err.Error())
+		log.Error("cannot mark app canceling", logger.Fields{"error": err})
 		return &common_proto.Empty{}, err
 	}
 
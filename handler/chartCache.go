@@ -0,0 +1,187 @@
+package handler
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// chartCache keeps chartmuseum indexes per repo, and chart archives and the files extracted from them
+// per name@version, in memory for ttl. A zero ttl disables caching. Archives and files together are kept
+// within maxBytes, the least recently used are dropped first to make room.
+type chartCache struct {
+	ttl      time.Duration
+	maxBytes int64
+	mu       sync.Mutex
+	indexes  map[string]cachedIndex
+	archives map[string]*list.Element
+	files    map[string]*list.Element
+	// versions orders the archives and files, most recently used first
+	versions *list.List
+	size     int64
+}
+
+type cachedIndex struct {
+	charts  map[string][]Chart
+	expires time.Time
+}
+
+// cachedVersion is the archive or the extracted files of a chart version, in the versions list
+type cachedVersion struct {
+	key     string
+	archive []byte
+	files   map[string]string
+	size    int64
+	expires time.Time
+	// in is the map of archives or files the entry is kept in
+	in map[string]*list.Element
+}
+
+func newChartCache(ttl time.Duration, maxBytes int64) *chartCache {
+	return &chartCache{
+		ttl:      ttl,
+		maxBytes: maxBytes,
+		indexes:  map[string]cachedIndex{},
+		archives: map[string]*list.Element{},
+		files:    map[string]*list.Element{},
+		versions: list.New(),
+	}
+}
+
+// repoKey identifies a repo, user repos are per team
+func repoKey(teamId, repo string) string {
+	return getChartURL("", teamId, repo)
+}
+
+// chartKey identifies a chart version inside a repo
+func chartKey(teamId, repo, name, version string) string {
+	return repoKey(teamId, repo) + "/" + name + "@" + version
+}
+
+func (c *chartCache) getIndex(key string) (map[string][]Chart, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.indexes[key]
+	if !ok || time.Now().After(entry.expires) {
+		return nil, false
+	}
+	return entry.charts, true
+}
+
+func (c *chartCache) setIndex(key string, charts map[string][]Chart) {
+	if c.ttl <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.evictExpired()
+	c.indexes[key] = cachedIndex{charts: charts, expires: time.Now().Add(c.ttl)}
+}
+
+func (c *chartCache) getArchive(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.getVersion(c.archives, key)
+	if !ok {
+		return nil, false
+	}
+	return entry.archive, true
+}
+
+func (c *chartCache) setArchive(key string, archive []byte) {
+	c.setVersion(c.archives, &cachedVersion{key: key, archive: archive, size: int64(len(archive))})
+}
+
+func (c *chartCache) getFiles(key string) (map[string]string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.getVersion(c.files, key)
+	if !ok {
+		return nil, false
+	}
+	return entry.files, true
+}
+
+func (c *chartCache) setFiles(key string, files map[string]string) {
+	size := int64(0)
+	for name, content := range files {
+		size += int64(len(name) + len(content))
+	}
+	c.setVersion(c.files, &cachedVersion{key: key, files: files, size: size})
+}
+
+// getVersion returns the fresh entry of key in m and marks it used, it must be called with the lock held
+func (c *chartCache) getVersion(m map[string]*list.Element, key string) (*cachedVersion, bool) {
+	element, ok := m[key]
+	if !ok {
+		return nil, false
+	}
+	entry := element.Value.(*cachedVersion)
+	if time.Now().After(entry.expires) {
+		return nil, false
+	}
+	c.versions.MoveToFront(element)
+	return entry, true
+}
+
+// setVersion keeps entry in m, dropping the least recently used entries until it fits in maxBytes.
+// An entry larger than maxBytes is not kept.
+func (c *chartCache) setVersion(m map[string]*list.Element, entry *cachedVersion) {
+	if c.ttl <= 0 || entry.size > c.maxBytes {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.evictExpired()
+
+	if element, ok := m[entry.key]; ok {
+		c.remove(element)
+	}
+	for c.size+entry.size > c.maxBytes {
+		c.remove(c.versions.Back())
+	}
+
+	entry.expires = time.Now().Add(c.ttl)
+	entry.in = m
+	m[entry.key] = c.versions.PushFront(entry)
+	c.size += entry.size
+}
+
+// remove drops an element of versions, it must be called with the lock held
+func (c *chartCache) remove(element *list.Element) {
+	entry := c.versions.Remove(element).(*cachedVersion)
+	delete(entry.in, entry.key)
+	c.size -= entry.size
+}
+
+// invalidate drops the index of the repo and everything cached for the chart version,
+// called after the chart version is uploaded or deleted
+func (c *chartCache) invalidate(teamId, repo, name, version string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.indexes, repoKey(teamId, repo))
+	key := chartKey(teamId, repo, name, version)
+	if element, ok := c.archives[key]; ok {
+		c.remove(element)
+	}
+	if element, ok := c.files[key]; ok {
+		c.remove(element)
+	}
+}
+
+// evictExpired must be called with the lock held
+func (c *chartCache) evictExpired() {
+	now := time.Now()
+	for key, entry := range c.indexes {
+		if now.After(entry.expires) {
+			delete(c.indexes, key)
+		}
+	}
+	for element := c.versions.Front(); element != nil; {
+		next := element.Next()
+		if now.After(element.Value.(*cachedVersion).expires) {
+			c.remove(element)
+		}
+		element = next
+	}
+}
@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestChartCache(t *testing.T) {
+	c := newChartCache(time.Minute, 1<<10)
+	key := chartKey("team-1", "user", "test", "1.0.0")
+
+	c.setIndex(repoKey("team-1", "user"), map[string][]Chart{"test": {{Name: "test", Version: "1.0.0"}}})
+	c.setArchive(key, []byte("archive"))
+	c.setFiles(key, map[string]string{"values.yaml": "a: 1"})
+
+	if charts, ok := c.getIndex(repoKey("team-1", "user")); !ok || len(charts["test"]) != 1 {
+		t.Errorf("index not cached: %v", charts)
+	}
+	if archive, ok := c.getArchive(key); !ok || string(archive) != "archive" {
+		t.Errorf("archive not cached: %q", archive)
+	}
+	if files, ok := c.getFiles(key); !ok || files["values.yaml"] != "a: 1" {
+		t.Errorf("files not cached: %v", files)
+	}
+	if c.size != int64(len("archive")+len("values.yaml")+len("a: 1")) {
+		t.Errorf("cache size is %d", c.size)
+	}
+
+	c.invalidate("team-1", "user", "test", "1.0.0")
+	if _, ok := c.getIndex(repoKey("team-1", "user")); ok {
+		t.Error("index cached after invalidate")
+	}
+	if _, ok := c.getArchive(key); ok {
+		t.Error("archive cached after invalidate")
+	}
+	if _, ok := c.getFiles(key); ok {
+		t.Error("files cached after invalidate")
+	}
+	if c.size != 0 || c.versions.Len() != 0 {
+		t.Errorf("invalidate left %d bytes in %d entries", c.size, c.versions.Len())
+	}
+}
+
+func TestChartCacheDisabled(t *testing.T) {
+	c := newChartCache(0, 1<<10)
+	c.setIndex("repo", map[string][]Chart{})
+	c.setArchive("chart", []byte("archive"))
+	if _, ok := c.getIndex("repo"); ok {
+		t.Error("index cached with a zero ttl")
+	}
+	if _, ok := c.getArchive("chart"); ok {
+		t.Error("archive cached with a zero ttl")
+	}
+}
+
+func TestChartCacheEvictExpired(t *testing.T) {
+	c := newChartCache(time.Minute, 1<<10)
+	c.setIndex("repo", map[string][]Chart{})
+	c.setArchive("old", []byte("old"))
+	c.setArchive("new", []byte("new"))
+
+	past := time.Now().Add(-time.Second)
+	c.indexes["repo"] = cachedIndex{expires: past}
+	c.archives["old"].Value.(*cachedVersion).expires = past
+
+	if _, ok := c.getArchive("old"); ok {
+		t.Error("expired archive served")
+	}
+	c.mu.Lock()
+	c.evictExpired()
+	c.mu.Unlock()
+	if _, ok := c.indexes["repo"]; ok {
+		t.Error("expired index kept")
+	}
+	if _, ok := c.archives["old"]; ok {
+		t.Error("expired archive kept")
+	}
+	if _, ok := c.getArchive("new"); !ok {
+		t.Error("fresh archive evicted")
+	}
+	if c.size != int64(len("new")) {
+		t.Errorf("cache size is %d after eviction", c.size)
+	}
+}
+
+func TestChartCacheMaxBytes(t *testing.T) {
+	c := newChartCache(time.Minute, 10)
+	c.setArchive("a", []byte("aaaa"))
+	c.setArchive("b", []byte("bbbb"))
+	// a is used last, so b is dropped to make room for c
+	c.getArchive("a")
+	c.setArchive("c", []byte("cccc"))
+
+	if _, ok := c.getArchive("b"); ok {
+		t.Error("least recently used archive kept")
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, ok := c.getArchive(key); !ok {
+			t.Errorf("archive %s dropped", key)
+		}
+	}
+	if c.size > 10 {
+		t.Errorf("cache holds %d bytes, more than 10", c.size)
+	}
+
+	// replacing an entry does not count it twice
+	c.setArchive("a", []byte("aaaaa"))
+	if c.size != 9 {
+		t.Errorf("cache size is %d after replacing a, want 9", c.size)
+	}
+
+	// an archive larger than the cache is not kept, and does not empty it
+	c.setArchive("big", make([]byte, 11))
+	if _, ok := c.getArchive("big"); ok {
+		t.Error("archive larger than the cache kept")
+	}
+	if _, ok := c.getArchive("c"); !ok {
+		t.Error("archive dropped for one larger than the cache")
+	}
+}
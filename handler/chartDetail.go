@@ -1,17 +1,12 @@
 package handler
 
 import (
-	"archive/tar"
-	"compress/gzip"
 	"context"
-	"encoding/json"
-	"errors"
-	"io/ioutil"
-	"log"
-	"net/http"
 
+	"github.com/Ankr-network/dccn-appmgr/logger"
 	chartutil "k8s.io/helm/pkg/chartutil"
 
+	"github.com/Ankr-network/dccn-appmgr/protos/appmgrext"
 	ankr_default "github.com/Ankr-network/dccn-common/protos"
 	appmgr "github.com/Ankr-network/dccn-common/protos/appmgr/v1/grpc"
 	common_proto "github.com/Ankr-network/dccn-common/protos/common"
@@ -21,36 +16,45 @@ import (
 // ChartDetail will return a list of specific chart versions from the chartmuseum repo
 func (p *AppMgrHandler) ChartDetail(ctx context.Context,
 	req *appmgr.ChartDetailRequest) (*appmgr.ChartDetailResponse, error) {
+	return p.chartDetail(ctx, req, false)
+}
 
-	log.Printf(">>>>>>>>>Debug into ChartDetail... %+v\nctx: %+v\n", req, ctx)
+// GetChartDetail is ChartDetail with the option to list pre-release versions
+func (p *AppMgrHandler) GetChartDetail(ctx context.Context,
+	req *appmgrext.GetChartDetailRequest) (*appmgrext.GetChartDetailResponse, error) {
+
+	detail, err := p.chartDetail(ctx, &appmgr.ChartDetailRequest{Chart: req.Chart, ShowVersion: req.ShowVersion},
+		req.IncludePrerelease)
+	rsp := &appmgrext.GetChartDetailResponse{
+		ChartName:           detail.ChartName,
+		ChartRepo:           detail.ChartRepo,
+		ChartDescription:    detail.ChartDescription,
+		ChartVersionDetails: detail.ChartVersionDetails,
+		ReadmeMd:            detail.ReadmeMd,
+		ValuesYaml:          detail.ValuesYaml,
+		CustomValues:        detail.CustomValues,
+	}
+	return rsp, err
+}
+
+func (p *AppMgrHandler) chartDetail(ctx context.Context, req *appmgr.ChartDetailRequest,
+	includePrerelease bool) (*appmgr.ChartDetailResponse, error) {
 
 	_, teamId := common_util.GetUserIDAndTeamID(ctx)
+	log := p.logger.WithContext(ctx)
 	rsp := &appmgr.ChartDetailResponse{}
 	if req.Chart == nil || len(req.Chart.ChartName) == 0 || len(req.Chart.ChartRepo) == 0 {
-		log.Printf("invalid input: null chart provided, %+v \n", req.Chart)
+		log.Warn("invalid input: null chart provided")
 		return rsp, ankr_default.ErrChartNotExist
 	}
+	log = log.With(logger.Fields{"repo": req.Chart.ChartRepo, "chart": req.Chart.ChartName})
 
-	chartRes, err := http.Get(getChartURL(chartmuseumURL+"/api",
-		teamId, req.Chart.ChartRepo) + "/" + req.Chart.ChartName)
+	charts, err := p.chartIndex(ctx, teamId, req.Chart.ChartRepo)
 	if err != nil {
-		log.Printf("cannot get chart details, %s \n", err.Error())
-		return rsp, ankr_default.ErrChartDetailGet
-	}
-
-	defer chartRes.Body.Close()
-
-	message, err := ioutil.ReadAll(chartRes.Body)
-	if err != nil {
-		log.Printf("cannot get chart details response body, %s \n", err.Error())
-		return rsp, ankr_default.ErrCannotReadChartDetails
-	}
-
-	data := []Chart{}
-	if err := json.Unmarshal([]byte(message), &data); err != nil {
-		log.Printf("cannot unmarshal chart details, %s \n", err.Error())
-		return rsp, ankr_default.ErrUnMarshalChartDetail
+		log.Warn("cannot get chart details", logger.Fields{"error": err})
+		return rsp, err
 	}
+	data := visibleChartVersions(charts[req.Chart.ChartName], includePrerelease)
 
 	rsp.ChartName = req.Chart.ChartName
 	rsp.ChartRepo = req.Chart.ChartRepo
@@ -70,60 +74,41 @@ func (p *AppMgrHandler) ChartDetail(ctx context.Context,
 	}
 	rsp.ChartVersionDetails = versionDetails
 
-	tarballReq, err := http.NewRequest("GET", getChartURL(chartmuseumURL, teamId,
-		req.Chart.ChartRepo)+"/"+req.Chart.ChartName+"-"+req.ShowVersion+".tgz", nil)
-	if err != nil {
-		log.Printf("cannot create show version tarball request, %s \n", err.Error())
-		return rsp, ankr_default.ErrCreateRequest
-	}
-
-	tarballRes, err := http.DefaultClient.Do(tarballReq)
-	if err != nil {
-		log.Printf("cannot download chart tarball, %s \n", err.Error())
-		return rsp, errors.New(ankr_default.DialError + "Cannot download chart tarball" + err.Error())
+	if len(req.ShowVersion) == 0 {
+		req.ShowVersion = data[0].Version
 	}
-	defer tarballRes.Body.Close()
 
-	gzf, err := gzip.NewReader(tarballRes.Body)
+	readme, valuesYaml, err := p.chartReadmeAndValues(ctx, teamId, &common_proto.ChartDetail{
+		ChartName: req.Chart.ChartName,
+		ChartRepo: req.Chart.ChartRepo,
+		ChartVer:  req.ShowVersion,
+	})
 	if err != nil {
-		log.Printf("cannot open chart tarball, %s \n", err.Error())
-		return rsp, ankr_default.ErrCannotReadDownload
-	}
-	defer gzf.Close()
-
-	tarball := tar.NewReader(gzf)
-	tarf := make(map[string]string)
-	tarf[req.Chart.ChartName+"/README.md"] = ""
-	tarf[req.Chart.ChartName+"/values.yaml"] = ""
-
-	if err := extractFromTarfile(tarf, tarball); err != nil {
-		log.Printf("cannot find readme/value in chart tarball, %s \n", err.Error())
-		return rsp, ankr_default.ErrNoChartReadme
+		return rsp, err
 	}
-	if tarf[req.Chart.ChartName+"/README.md"] == "" {
-		log.Printf("cannot find readme in chart tarball\n")
+	if readme == "" {
+		log.Debug("no readme in chart tarball", logger.Fields{"chart_ver": req.ShowVersion})
 	}
-	if tarf[req.Chart.ChartName+"/values.yaml"] == "" {
-		log.Printf("cannot find value in chart tarball\n")
+	if valuesYaml == "" {
+		log.Debug("no values in chart tarball", logger.Fields{"chart_ver": req.ShowVersion})
 	}
 
-	rsp.ReadmeMd = tarf[req.Chart.ChartName+"/README.md"]
-	rsp.ValuesYaml = tarf[req.Chart.ChartName+"/values.yaml"]
+	rsp.ReadmeMd = readme
+	rsp.ValuesYaml = valuesYaml
 	values, err := chartutil.ReadValues([]byte(rsp.ValuesYaml))
 	if err != nil {
-		log.Printf("parse values.yaml error: %s\n%+v\n", err, rsp.ValuesYaml)
+		log.Warn("cannot parse values.yaml", logger.Fields{"chart_ver": req.ShowVersion, "error": err})
 	}
 	if values != nil {
 		ankrCustomValues, err := values.Table("ankrCustomValues")
 		if err != nil {
-			log.Printf("get ankrCustomValue from values.yaml error: %s\n%+v\n", err, ankrCustomValues)
+			log.Debug("no ankrCustomValues in values.yaml", logger.Fields{"chart_ver": req.ShowVersion, "error": err})
 		} else {
 			if ankrCustomValues != nil {
 				ankrCustomKeys := make([]*common_proto.CustomValue, 0, len(ankrCustomValues))
 				for key := range ankrCustomValues {
 					ankrCustomKeys = append(ankrCustomKeys, &common_proto.CustomValue{Key: key, Value: ""})
 				}
-				log.Printf("ankrCustomKeys: %+v\n", ankrCustomKeys)
 				rsp.CustomValues = ankrCustomKeys
 			}
 		}
@@ -2,45 +2,73 @@ package handler
 
 import (
 	"context"
+	"github.com/Ankr-network/dccn-appmgr/protos/appmgrext"
 	appmgr "github.com/Ankr-network/dccn-common/protos/appmgr/v1/grpc"
 	common_proto "github.com/Ankr-network/dccn-common/protos/common"
 	common_util "github.com/Ankr-network/dccn-common/util"
-	"log"
 	"sort"
 )
 
 // ChartList will return a list of charts from the specific chartmuseum repo
 func (p *AppMgrHandler) ChartList(ctx context.Context, req *appmgr.ChartListRequest) (*appmgr.ChartListResponse, error) {
 
-	log.Printf(">>>>>>>>>Debug into ChartList...%+v\nctx: %+v\n", req, ctx)
-
-	_, teamId := common_util.GetUserIDAndTeamID(ctx)
 	rsp := &appmgr.ChartListResponse{}
 
-	if len(req.ChartRepo) == 0 {
-		req.ChartRepo = "stable"
+	charts, err := p.listCharts(ctx, req.ChartRepo, false)
+	if err != nil {
+		return rsp, err
 	}
+	rsp.Charts = charts
 
-	data, err := getCharts(teamId, req.ChartRepo)
+	return rsp, nil
+}
+
+// ListCharts is ChartList with the option to show pre-release versions as the latest
+func (p *AppMgrHandler) ListCharts(ctx context.Context, req *appmgrext.ListChartsRequest) (*appmgrext.ListChartsResponse, error) {
+
+	rsp := &appmgrext.ListChartsResponse{}
+
+	charts, err := p.listCharts(ctx, req.ChartRepo, req.IncludePrerelease)
 	if err != nil {
 		return rsp, err
 	}
+	rsp.Charts = charts
+
+	return rsp, nil
+}
+
+// listCharts returns the latest version of each chart of the repo, stable if empty, sorted by name
+func (p *AppMgrHandler) listCharts(ctx context.Context, repo string, includePrerelease bool) ([]*common_proto.Chart, error) {
+
+	_, teamId := common_util.GetUserIDAndTeamID(ctx)
+
+	if len(repo) == 0 {
+		repo = "stable"
+	}
+
+	data, err := p.chartIndex(ctx, teamId, repo)
+	if err != nil {
+		return nil, err
+	}
 
 	charts := make([]*common_proto.Chart, 0)
 
 	for _, v := range data {
+		latest, ok := latestChartVersion(v, includePrerelease)
+		if !ok {
+			continue
+		}
 		chart := common_proto.Chart{
-			ChartName:             v[0].Name,
-			ChartRepo:             req.ChartRepo,
-			ChartDescription:      v[0].Description,
-			ChartIconUrl:          v[0].Icon,
-			ChartLatestVersion:    v[0].Version,
-			ChartLatestAppVersion: v[0].AppVersion,
+			ChartName:             latest.Name,
+			ChartRepo:             repo,
+			ChartDescription:      latest.Description,
+			ChartIconUrl:          latest.Icon,
+			ChartLatestVersion:    latest.Version,
+			ChartLatestAppVersion: latest.AppVersion,
 		}
 		charts = append(charts, &chart)
 	}
 	sort.Sort(chartList(charts))
-	rsp.Charts = charts
 
-	return rsp, nil
+	return charts, nil
 }
@@ -0,0 +1,305 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Ankr-network/dccn-appmgr/logger"
+	"github.com/Ankr-network/dccn-appmgr/protos/appmgrext"
+	ankr_default "github.com/Ankr-network/dccn-common/protos"
+	common_proto "github.com/Ankr-network/dccn-common/protos/common"
+	common_util "github.com/Ankr-network/dccn-common/util"
+	"github.com/Masterminds/semver"
+	"k8s.io/helm/pkg/chartutil"
+)
+
+const (
+	// chartChunkSize is the size of the chunks DownloadChartStream sends, far below the gRPC message limit
+	chartChunkSize = 64 << 10
+	// maxChartSize is the largest chart tarball UploadChartStream accepts
+	maxChartSize = 64 << 20
+)
+
+var errChartChecksum = errors.New(ankr_default.LogicError + "streamed chart does not match its declared size or sha256")
+
+// UploadChartStream is UploadChart for large charts, the tarball is received in chunks into a temp file
+// and checked against the size and sha256 of the first chunk before it is uploaded to chartmuseum
+func (p *AppMgrHandler) UploadChartStream(stream appmgrext.AppMgrExt_UploadChartStreamServer) error {
+	ctx := stream.Context()
+	_, teamId := common_util.GetUserIDAndTeamID(ctx)
+
+	log := p.logger.WithContext(ctx)
+
+	first, err := stream.Recv()
+	if err != nil {
+		log.Warn("cannot receive chart upload stream", logger.Fields{"error": err})
+		return err
+	}
+	log = log.With(logger.Fields{"repo": first.ChartRepo, "chart": first.ChartName + "-" + first.ChartVer})
+	log.Debug("rpc request", logger.Fields{"method": "UploadChartStream",
+		"request": logger.Fields{"chart_repo": first.ChartRepo, "chart_name": first.ChartName, "chart_ver": first.ChartVer,
+			"size": first.Size}})
+
+	if len(first.ChartName) == 0 || len(first.ChartRepo) == 0 || len(first.ChartVer) == 0 ||
+		first.Size <= 0 || len(first.Sha256) == 0 {
+		log.Warn("invalid input: chart name, repo, version, size and sha256 required")
+		return ankr_default.ErrInvalidInput
+	}
+	if first.Size > maxChartSize {
+		return errors.New(ankr_default.LogicError + fmt.Sprintf("chart is larger than %d bytes", maxChartSize))
+	}
+
+	if _, err := semver.NewVersion(first.ChartVer); err != nil {
+		return invalidField("ChartVer", errInvalidChartVersion)
+	}
+
+	if err := p.checkChartNotExist(ctx, teamId, first.ChartRepo, first.ChartName, first.ChartVer, ankr_default.ErrChartAlreadyExist); err != nil {
+		log.Warn("chart upload refused", logger.Fields{"error": err})
+		return err
+	}
+
+	dir, err := ioutil.TempDir("", "appmgr-upload-")
+	if err != nil {
+		log.Error("cannot create chart upload dir", logger.Fields{"error": err})
+		return ankr_default.ErrCannotGetChartOutdir
+	}
+	defer os.RemoveAll(dir)
+
+	uploadPath := filepath.Join(dir, "upload")
+	if err := receiveChart(stream, first, uploadPath); err != nil {
+		log.Warn("cannot receive chart", logger.Fields{"error": err})
+		return err
+	}
+
+	loadedChart, err := chartutil.LoadFile(uploadPath)
+	if err != nil {
+		log.Warn("cannot load chart from tar file", logger.Fields{"error": err})
+		return ankr_default.ErrCannotLoadChart
+	}
+
+	loadedChart.Metadata.Version = first.ChartVer
+	loadedChart.Metadata.Name = first.ChartName
+
+	tarballName, err := chartutil.Save(loadedChart, dir)
+	if err != nil {
+		log.Error("cannot package chart", logger.Fields{"error": err})
+		return ankr_default.ErrCannotGetChartOutdir
+	}
+
+	tarball, err := os.Open(tarballName)
+	if err != nil {
+		log.Error("cannot open chart tar file", logger.Fields{"error": err})
+		return ankr_default.ErrCannotGetChartTar
+	}
+	defer tarball.Close()
+
+	if err := p.pushChartFile(ctx, teamId, first.ChartRepo, "charts", tarball); err != nil {
+		return err
+	}
+	p.charts.invalidate(teamId, first.ChartRepo, first.ChartName, first.ChartVer)
+
+	return stream.SendAndClose(&common_proto.Empty{})
+}
+
+// receiveChart writes the chunks of stream to path, checking their total size and sha256 as they come
+func receiveChart(stream appmgrext.AppMgrExt_UploadChartStreamServer, first *appmgrext.ChartChunk, path string) error {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return ankr_default.ErrCannotGetChartTar
+	}
+	defer file.Close()
+
+	hasher := sha256.New()
+	out := io.MultiWriter(file, hasher)
+	received := int64(0)
+	for chunk := first; ; {
+		received += int64(len(chunk.Data))
+		if received > first.Size {
+			return errChartChecksum
+		}
+		if _, err := out.Write(chunk.Data); err != nil {
+			return ankr_default.ErrCannotGetChartTar
+		}
+
+		chunk, err = stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if received != first.Size || !strings.EqualFold(hex.EncodeToString(hasher.Sum(nil)), first.Sha256) {
+		return errChartChecksum
+	}
+
+	return file.Close()
+}
+
+// DownloadChartStream is DownloadChart for large charts, the tarball is sent in chunks while its digest
+// is checked. The first chunk carries the size and sha256 of the tarball so the client can check it too;
+// a stream ending with an error must be discarded. With a keyring configured the tarball is spooled
+// to a temp file to verify its provenance before it is sent.
+func (p *AppMgrHandler) DownloadChartStream(req *appmgrext.DownloadChartStreamRequest,
+	stream appmgrext.AppMgrExt_DownloadChartStreamServer) error {
+	ctx := stream.Context()
+	log := p.logger.WithContext(ctx).With(logger.Fields{"repo": req.ChartRepo, "chart": req.ChartName + "-" + req.ChartVer})
+	log.Debug("rpc request", logger.Fields{"method": "DownloadChartStream", "request": logger.Redact(req)})
+
+	_, teamId := common_util.GetUserIDAndTeamID(ctx)
+	if len(req.ChartName) == 0 || len(req.ChartRepo) == 0 || len(req.ChartVer) == 0 {
+		log.Warn("invalid input: null chart detail provided")
+		return ankr_default.ErrChartDetailEmpty
+	}
+
+	first := &appmgrext.ChartChunk{ChartName: req.ChartName, ChartRepo: req.ChartRepo, ChartVer: req.ChartVer}
+
+	// verified already when it was cached
+	key := chartKey(teamId, req.ChartRepo, req.ChartName, req.ChartVer)
+	if chartFile, ok := p.charts.getArchive(key); ok {
+		sum := sha256.Sum256(chartFile)
+		first.Size = int64(len(chartFile))
+		first.Sha256 = hex.EncodeToString(sum[:])
+		if _, err := sendChart(stream, first, bytes.NewReader(chartFile)); err != nil {
+			log.Warn("cannot send chart", logger.Fields{"error": err})
+			return err
+		}
+		return nil
+	}
+
+	index, err := p.chartVersion(ctx, teamId, req.ChartRepo, req.ChartName, req.ChartVer)
+	if err != nil {
+		return err
+	}
+	if len(index.Digest) == 0 {
+		return errChartDigestMissing
+	}
+	first.Sha256 = strings.ToLower(strings.TrimPrefix(index.Digest, "sha256:"))
+
+	tarballName := req.ChartName + "-" + req.ChartVer + ".tgz"
+	res, err := chartmuseumGet(ctx, getChartURL(chartmuseumURL, teamId, req.ChartRepo)+"/"+tarballName)
+	if err != nil {
+		log.Error("cannot get chart file from chartmuseum", logger.Fields{"error": err})
+		return chartmuseumUnavailable(err, ankr_default.ErrChartMuseumGet)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		err := chartmuseumStatus(res, ankr_default.ErrChartNotExist)
+		log.Warn("chartmuseum has no chart file", logger.Fields{"status": res.StatusCode, "error": err})
+		return err
+	}
+	first.Size = res.ContentLength
+
+	var body io.Reader = res.Body
+	if p.signatory != nil {
+		dir, err := ioutil.TempDir("", "appmgr-download-")
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(dir)
+
+		chartPath := filepath.Join(dir, tarballName)
+		if first.Size, err = p.spoolVerifiedChart(ctx, teamId, req.ChartRepo, res.Body, chartPath, first.Sha256); err != nil {
+			return err
+		}
+		file, err := os.Open(chartPath)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		body = file
+	}
+
+	hasher := sha256.New()
+	sent, err := sendChart(stream, first, io.TeeReader(body, hasher))
+	if err == nil {
+		err = checkChartSum(hasher, sent, first)
+	}
+	if err != nil {
+		log.Warn("cannot send chart", logger.Fields{"error": err})
+		return err
+	}
+	return nil
+}
+
+// spoolVerifiedChart writes body to chartPath and verifies its digest and provenance, returning its size
+func (p *AppMgrHandler) spoolVerifiedChart(ctx context.Context, teamId, repo string, body io.Reader,
+	chartPath, digest string) (int64, error) {
+	file, err := os.OpenFile(chartPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	log := p.logger.WithContext(ctx).With(logger.Fields{"repo": repo, "chart": filepath.Base(chartPath)})
+	hasher := sha256.New()
+	size, err := io.Copy(io.MultiWriter(file, hasher), body)
+	if err != nil {
+		log.Error("cannot read chart file", logger.Fields{"error": err})
+		return 0, ankr_default.ErrCannotReadDownload
+	}
+	if err := file.Close(); err != nil {
+		return 0, err
+	}
+	if hex.EncodeToString(hasher.Sum(nil)) != digest {
+		log.Warn("chart verification failed", logger.Fields{"error": errChartDigestMismatch})
+		return 0, errChartDigestMismatch
+	}
+
+	provFile, err := p.getChartFile(ctx, teamId, repo, filepath.Base(chartPath)+".prov")
+	if err != nil {
+		log.Warn("cannot get provenance of chart", logger.Fields{"error": err})
+		return 0, errChartProvenance
+	}
+	if err := p.verifyChartProvenanceFile(ctx, chartPath, provFile); err != nil {
+		log.Warn("chart provenance verification failed", logger.Fields{"error": err})
+		return 0, errChartProvenance
+	}
+
+	return size, nil
+}
+
+// sendChart sends r in chunks, the first one described by first, and returns how many bytes were sent
+func sendChart(stream appmgrext.AppMgrExt_DownloadChartStreamServer, first *appmgrext.ChartChunk, r io.Reader) (int64, error) {
+	sent := int64(0)
+	chunk := first
+	buf := make([]byte, chartChunkSize)
+	for {
+		n, err := io.ReadFull(r, buf)
+		if n > 0 || chunk == first {
+			chunk.Data = buf[:n]
+			if err := stream.Send(chunk); err != nil {
+				return sent, err
+			}
+			sent += int64(n)
+			chunk = &appmgrext.ChartChunk{}
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return sent, nil
+		}
+		if err != nil {
+			return sent, ankr_default.ErrCannotReadDownload
+		}
+	}
+}
+
+// checkChartSum compares what went through hasher with the size, when known, and sha256 announced in first
+func checkChartSum(hasher hash.Hash, sent int64, first *appmgrext.ChartChunk) error {
+	if (first.Size >= 0 && sent != first.Size) || hex.EncodeToString(hasher.Sum(nil)) != first.Sha256 {
+		return errChartDigestMismatch
+	}
+	return nil
+}
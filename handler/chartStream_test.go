@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Ankr-network/dccn-appmgr/protos/appmgrext"
+	common_proto "github.com/Ankr-network/dccn-common/protos/common"
+	"google.golang.org/grpc"
+)
+
+// fakeChartStream is both sides of the chart streams, the grpc.ServerStream methods it does not override are not used
+type fakeChartStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	chunks []*appmgrext.ChartChunk
+	sent   []*appmgrext.ChartChunk
+}
+
+func (s *fakeChartStream) Recv() (*appmgrext.ChartChunk, error) {
+	if len(s.chunks) == 0 {
+		return nil, io.EOF
+	}
+	chunk := s.chunks[0]
+	s.chunks = s.chunks[1:]
+	return chunk, nil
+}
+
+func (s *fakeChartStream) SendAndClose(*common_proto.Empty) error { return nil }
+
+func (s *fakeChartStream) Send(chunk *appmgrext.ChartChunk) error {
+	data := append([]byte{}, chunk.Data...)
+	copied := *chunk
+	copied.Data = data
+	s.sent = append(s.sent, &copied)
+	return nil
+}
+
+func (s *fakeChartStream) Context() context.Context {
+	if s.ctx == nil {
+		return context.Background()
+	}
+	return s.ctx
+}
+
+func TestChartStreamRoundTrip(t *testing.T) {
+	data := bytes.Repeat([]byte("chart"), chartChunkSize/2)
+	sum := sha256.Sum256(data)
+
+	down := &fakeChartStream{}
+	first := &appmgrext.ChartChunk{ChartName: "test", Size: int64(len(data)), Sha256: hex.EncodeToString(sum[:])}
+	hasher := sha256.New()
+	sent, err := sendChart(down, first, io.TeeReader(bytes.NewReader(data), hasher))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := checkChartSum(hasher, sent, first); err != nil {
+		t.Fatal(err)
+	}
+	if len(down.sent) != 3 || down.sent[0].Sha256 != first.Sha256 || len(down.sent[2].Data) != len(data)-2*chartChunkSize {
+		t.Fatalf("sent %d chunks, want 3 with the description in the first", len(down.sent))
+	}
+
+	dir, err := ioutil.TempDir("", "chart-stream-test-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	up := &fakeChartStream{chunks: down.sent[1:]}
+	if err := receiveChart(up, down.sent[0], filepath.Join(dir, "ok")); err != nil {
+		t.Fatal(err)
+	}
+	received, _ := ioutil.ReadFile(filepath.Join(dir, "ok"))
+	if !bytes.Equal(received, data) {
+		t.Error("received chart differs from sent chart")
+	}
+
+	truncated := &fakeChartStream{chunks: down.sent[1:2]}
+	if err := receiveChart(truncated, down.sent[0], filepath.Join(dir, "truncated")); err != errChartChecksum {
+		t.Errorf("truncated stream error %v, want %v", err, errChartChecksum)
+	}
+
+	tampered := *down.sent[0]
+	tampered.Sha256 = hex.EncodeToString(make([]byte, sha256.Size))
+	if err := receiveChart(&fakeChartStream{chunks: down.sent[1:]}, &tampered, filepath.Join(dir, "tampered")); err != errChartChecksum {
+		t.Errorf("tampered stream error %v, want %v", err, errChartChecksum)
+	}
+}
@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"sort"
+
+	"github.com/Masterminds/semver"
+)
+
+// sortChartVersions returns the versions of a chart newest first by semver, without pre-releases unless
+// includePrerelease. Versions which are not semver are kept last, in their original order.
+func sortChartVersions(versions []Chart, includePrerelease bool) []Chart {
+	parsed := make([]*semver.Version, 0, len(versions))
+	sorted := make([]Chart, 0, len(versions))
+	invalid := make([]Chart, 0)
+	for _, v := range versions {
+		version, err := semver.NewVersion(v.Version)
+		if err != nil {
+			invalid = append(invalid, v)
+			continue
+		}
+		if len(version.Prerelease()) > 0 && !includePrerelease {
+			continue
+		}
+		parsed = append(parsed, version)
+		sorted = append(sorted, v)
+	}
+
+	sort.Stable(byVersion{parsed, sorted})
+
+	return append(sorted, invalid...)
+}
+
+// visibleChartVersions sorts the versions of a chart as sortChartVersions, with its pre-releases
+// when it has no other version so the chart is not hidden
+func visibleChartVersions(versions []Chart, includePrerelease bool) []Chart {
+	sorted := sortChartVersions(versions, includePrerelease)
+	if len(sorted) == 0 && !includePrerelease {
+		return sortChartVersions(versions, true)
+	}
+	return sorted
+}
+
+// latestChartVersion returns the highest version of a chart, pre-releases only if includePrerelease
+// or the chart has nothing else, false if it has no version
+func latestChartVersion(versions []Chart, includePrerelease bool) (Chart, bool) {
+	sorted := visibleChartVersions(versions, includePrerelease)
+	if len(sorted) == 0 {
+		return Chart{}, false
+	}
+	return sorted[0], true
+}
+
+// byVersion sorts charts newest first along with their parsed versions
+type byVersion struct {
+	versions []*semver.Version
+	charts   []Chart
+}
+
+func (b byVersion) Len() int {
+	return len(b.charts)
+}
+
+func (b byVersion) Swap(i, j int) {
+	b.versions[i], b.versions[j] = b.versions[j], b.versions[i]
+	b.charts[i], b.charts[j] = b.charts[j], b.charts[i]
+}
+
+func (b byVersion) Less(i, j int) bool {
+	return b.versions[i].GreaterThan(b.versions[j])
+}
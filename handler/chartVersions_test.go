@@ -0,0 +1,46 @@
+package handler
+
+import "testing"
+
+func TestSortChartVersions(t *testing.T) {
+	versions := []Chart{
+		{Version: "1.9.0"},
+		{Version: "1.10.0"},
+		{Version: "latest"},
+		{Version: "2.0.0-rc.1"},
+		{Version: "v1.10.1"},
+		{Version: "0.1.0"},
+	}
+
+	cases := []struct {
+		includePrerelease bool
+		want              []string
+	}{
+		{false, []string{"v1.10.1", "1.10.0", "1.9.0", "0.1.0", "latest"}},
+		{true, []string{"2.0.0-rc.1", "v1.10.1", "1.10.0", "1.9.0", "0.1.0", "latest"}},
+	}
+
+	for _, c := range cases {
+		sorted := sortChartVersions(versions, c.includePrerelease)
+		if len(sorted) != len(c.want) {
+			t.Fatalf("sorted versions %+v, want %v", sorted, c.want)
+		}
+		for i, v := range sorted {
+			if v.Version != c.want[i] {
+				t.Errorf("version %d is %s, want %s", i, v.Version, c.want[i])
+			}
+		}
+	}
+
+	// a chart with pre-releases only is still listed
+	prereleases := []Chart{{Version: "1.0.0-alpha"}, {Version: "1.0.0-beta"}}
+	if latest, ok := latestChartVersion(prereleases, false); !ok || latest.Version != "1.0.0-beta" {
+		t.Errorf("latest version of pre-releases only is %s, want 1.0.0-beta", latest.Version)
+	}
+	if visible := visibleChartVersions(append(prereleases, Chart{Version: "0.9.0"}), false); len(visible) != 1 {
+		t.Errorf("visible versions %+v, want the stable one only", visible)
+	}
+	if _, ok := latestChartVersion(nil, false); ok {
+		t.Error("latest version of no versions")
+	}
+}
@@ -1,37 +1,401 @@
 package handler
 
 import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	ankr_default "github.com/Ankr-network/dccn-common/protos"
+	common_proto "github.com/Ankr-network/dccn-common/protos/common"
+	"io"
 	"io/ioutil"
-	"log"
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/proto/hapi/chart"
 	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Ankr-network/dccn-appmgr/logger"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	errChartDigestMissing  = errors.New(ankr_default.LogicError + "chart has no digest in chartmuseum index, cannot verify")
+	errChartDigestMismatch = errors.New(ankr_default.LogicError + "chart tarball does not match the digest of chartmuseum index")
+	errChartProvenance     = errors.New(ankr_default.LogicError + "chart provenance cannot be verified")
 )
 
-func getCharts(teamId, repo string) (map[string][]Chart, error) {
+func (p *AppMgrHandler) getCharts(ctx context.Context, teamId, repo string) (map[string][]Chart, error) {
+	log := p.logger.WithContext(ctx).With(logger.Fields{"repo": repo})
 	res := map[string][]Chart{}
-	chartRes, err := http.Get(getChartURL(chartmuseumURL+"/api", teamId, repo))
+	chartRes, err := chartmuseumGet(ctx, getChartURL(chartmuseumURL+"/api", teamId, repo))
 	if err != nil {
-		log.Printf("cannot get chart list, %v", err)
-		return res, ankr_default.ErrCannotGetChartList
+		log.Error("cannot get chart list", logger.Fields{"error": err})
+		return res, chartmuseumUnavailable(err, ankr_default.ErrCannotGetChartList)
 	}
 
 	defer func() { _ = chartRes.Body.Close() }()
 
+	if chartRes.StatusCode != http.StatusOK {
+		return res, chartmuseumStatus(chartRes, ankr_default.ErrCannotGetChartList)
+	}
+
 	message, err := ioutil.ReadAll(chartRes.Body)
 	if err != nil {
-		log.Printf("cannot get chart list response body, %v", err)
+		log.Error("cannot read chart list", logger.Fields{"error": err})
 		return res, ankr_default.ErrCannotReadChartList
 	}
 
 	if err := json.Unmarshal([]byte(message), &res); err != nil {
-		log.Printf("cannot unmarshal chart list, %s \n", err.Error())
+		log.Error("cannot unmarshal chart list", logger.Fields{"error": err})
 		return res, ankr_default.ErrUnMarshalChartList
 	}
 
 	return res, nil
 }
 
+// getChartVersion gets the chartmuseum index entry of a single chart version
+func (p *AppMgrHandler) getChartVersion(ctx context.Context, teamId, repo, name, version string) (*Chart, error) {
+	log := p.logger.WithContext(ctx).With(logger.Fields{"repo": repo, "chart": name + "-" + version})
+	chartRes, err := chartmuseumGet(ctx, getChartURL(chartmuseumURL+"/api", teamId, repo)+"/"+name+"/"+version)
+	if err != nil {
+		log.Error("cannot get chart from chartmuseum", logger.Fields{"error": err})
+		return nil, chartmuseumUnavailable(err, ankr_default.ErrChartMuseumGet)
+	}
+
+	defer func() { _ = chartRes.Body.Close() }()
+
+	if chartRes.StatusCode != http.StatusOK {
+		err := chartmuseumStatus(chartRes, ankr_default.ErrChartNotExist)
+		log.Debug("chartmuseum has no chart", logger.Fields{"status": chartRes.StatusCode, "error": err})
+		return nil, err
+	}
+
+	message, err := ioutil.ReadAll(chartRes.Body)
+	if err != nil {
+		log.Error("cannot read chart version", logger.Fields{"error": err})
+		return nil, ankr_default.ErrCannotReadChartDetails
+	}
+
+	data := &Chart{}
+	if err := json.Unmarshal(message, data); err != nil {
+		log.Error("cannot unmarshal chart version", logger.Fields{"error": err})
+		return nil, ankr_default.ErrUnMarshalChartDetail
+	}
+
+	return data, nil
+}
+
+// chartIndex returns the chartmuseum index of the repo, from the cache while it is fresh
+func (p *AppMgrHandler) chartIndex(ctx context.Context, teamId, repo string) (map[string][]Chart, error) {
+	key := repoKey(teamId, repo)
+	if charts, ok := p.charts.getIndex(key); ok {
+		return charts, nil
+	}
+
+	charts, err := p.getCharts(ctx, teamId, repo)
+	if err != nil {
+		return charts, err
+	}
+	p.charts.setIndex(key, charts)
+
+	return charts, nil
+}
+
+// chartVersion looks a chart version up in the repo index, and asks chartmuseum directly when it is not there
+func (p *AppMgrHandler) chartVersion(ctx context.Context, teamId, repo, name, version string) (*Chart, error) {
+	if charts, err := p.chartIndex(ctx, teamId, repo); err == nil {
+		for i := range charts[name] {
+			if charts[name][i].Version == version {
+				return &charts[name][i], nil
+			}
+		}
+	}
+
+	return p.getChartVersion(ctx, teamId, repo, name, version)
+}
+
+// downloadChartArchive downloads the tarball of chartDetail's name and version from chartmuseum
+// and verifies it against the digest of the chartmuseum index, and against its provenance file
+// when a keyring is configured
+func (p *AppMgrHandler) downloadChartArchive(ctx context.Context, teamId string,
+	chartDetail *common_proto.ChartDetail) ([]byte, error) {
+	key := chartKey(teamId, chartDetail.ChartRepo, chartDetail.ChartName, chartDetail.ChartVer)
+	if chartFile, ok := p.charts.getArchive(key); ok {
+		return chartFile, nil
+	}
+
+	index, err := p.chartVersion(ctx, teamId, chartDetail.ChartRepo, chartDetail.ChartName, chartDetail.ChartVer)
+	if err != nil {
+		return nil, err
+	}
+
+	tarballName := chartDetail.ChartName + "-" + chartDetail.ChartVer + ".tgz"
+	log := p.logger.WithContext(ctx).With(logger.Fields{"repo": chartDetail.ChartRepo, "chart": tarballName})
+	chartFile, err := p.getChartFile(ctx, teamId, chartDetail.ChartRepo, tarballName)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := verifyChartDigest(chartFile, index.Digest); err != nil {
+		log.Warn("chart verification failed", logger.Fields{"error": err})
+		return nil, err
+	}
+
+	if p.signatory != nil {
+		provFile, err := p.getChartFile(ctx, teamId, chartDetail.ChartRepo, tarballName+".prov")
+		if err != nil {
+			log.Warn("cannot get provenance of chart", logger.Fields{"error": err})
+			return nil, errChartProvenance
+		}
+		if err := p.verifyChartProvenance(ctx, tarballName, chartFile, provFile); err != nil {
+			log.Warn("chart provenance verification failed", logger.Fields{"error": err})
+			return nil, errChartProvenance
+		}
+	}
+	p.charts.setArchive(key, chartFile)
+
+	return chartFile, nil
+}
+
+// checkChartNotExist fails with an AlreadyExists status carrying exists when the chart version is in the repo
+func (p *AppMgrHandler) checkChartNotExist(ctx context.Context, teamId, repo, name, version string, exists error) error {
+	_, err := p.getChartVersion(ctx, teamId, repo, name, version)
+	switch {
+	case err == nil:
+		return status.Error(codes.AlreadyExists, exists.Error())
+	case isNotFound(err):
+		return nil
+	default:
+		return err
+	}
+}
+
+// getChartArchive downloads and verifies the chart tarball of chartDetail, then loads it
+func (p *AppMgrHandler) getChartArchive(ctx context.Context, teamId string,
+	chartDetail *common_proto.ChartDetail) (*chart.Chart, error) {
+	chartFile, err := p.downloadChartArchive(ctx, teamId, chartDetail)
+	if err != nil {
+		return nil, err
+	}
+
+	loadedChart, err := chartutil.LoadArchive(bytes.NewReader(chartFile))
+	if err != nil {
+		p.logger.WithContext(ctx).Error("cannot load chart from chartmuseum", logger.Fields{
+			"chart": chartDetail.ChartName + "-" + chartDetail.ChartVer, "error": err})
+		return nil, ankr_default.ErrChartMuseumGet
+	}
+
+	return loadedChart, nil
+}
+
+// chartReadmeAndValues returns README.md and values.yaml of a chart version, extracted once from the
+// archive and cached by name@version
+func (p *AppMgrHandler) chartReadmeAndValues(ctx context.Context, teamId string,
+	chartDetail *common_proto.ChartDetail) (string, string, error) {
+	readmeName := chartDetail.ChartName + "/README.md"
+	valuesName := chartDetail.ChartName + "/values.yaml"
+
+	key := chartKey(teamId, chartDetail.ChartRepo, chartDetail.ChartName, chartDetail.ChartVer)
+	if tarf, ok := p.charts.getFiles(key); ok {
+		return tarf[readmeName], tarf[valuesName], nil
+	}
+
+	chartFile, err := p.downloadChartArchive(ctx, teamId, chartDetail)
+	if err != nil {
+		return "", "", err
+	}
+
+	gzf, err := gzip.NewReader(bytes.NewReader(chartFile))
+	if err != nil {
+		p.logger.WithContext(ctx).Error("cannot open chart tarball", logger.Fields{
+			"chart": chartDetail.ChartName + "-" + chartDetail.ChartVer, "error": err})
+		return "", "", ankr_default.ErrCannotReadDownload
+	}
+	defer gzf.Close()
+
+	tarball := tar.NewReader(gzf)
+	tarf := make(map[string]string)
+	tarf[readmeName] = ""
+	tarf[valuesName] = ""
+
+	if err := extractFromTarfile(tarf, tarball); err != nil {
+		p.logger.WithContext(ctx).Warn("cannot find readme and values in chart tarball", logger.Fields{
+			"chart": chartDetail.ChartName + "-" + chartDetail.ChartVer, "error": err})
+		return "", "", ankr_default.ErrNoChartReadme
+	}
+	p.charts.setFiles(key, tarf)
+
+	return tarf[readmeName], tarf[valuesName], nil
+}
+
+// chartmuseumGet gets url from chartmuseum as part of the call of ctx
+func chartmuseumGet(ctx context.Context, url string) (*http.Response, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return chartmuseumClient.Do(req.WithContext(ctx))
+}
+
+// getChartFile downloads a file, tarball or provenance, of the repo from chartmuseum
+func (p *AppMgrHandler) getChartFile(ctx context.Context, teamId, repo, fileName string) ([]byte, error) {
+	log := p.logger.WithContext(ctx).With(logger.Fields{"repo": repo, "file": fileName})
+	fileRes, err := chartmuseumGet(ctx, getChartURL(chartmuseumURL, teamId, repo)+"/"+fileName)
+	if err != nil {
+		log.Error("cannot get chart file from chartmuseum", logger.Fields{"error": err})
+		return nil, chartmuseumUnavailable(err, ankr_default.ErrChartMuseumGet)
+	}
+
+	defer fileRes.Body.Close()
+
+	if fileRes.StatusCode != http.StatusOK {
+		err := chartmuseumStatus(fileRes, ankr_default.ErrChartNotExist)
+		log.Warn("chartmuseum has no chart file", logger.Fields{"status": fileRes.StatusCode, "error": err})
+		return nil, err
+	}
+
+	file, err := ioutil.ReadAll(fileRes.Body)
+	if err != nil {
+		log.Error("cannot read chart file", logger.Fields{"error": err})
+		return nil, ankr_default.ErrCannotReadDownload
+	}
+
+	return file, nil
+}
+
+// pushChartFile posts a chart tarball, to the charts endpoint of the repo, or a provenance file, to the prov endpoint
+func (p *AppMgrHandler) pushChartFile(ctx context.Context, teamId, repo, endpoint string, file io.Reader) error {
+	log := p.logger.WithContext(ctx).With(logger.Fields{"repo": repo, "endpoint": endpoint})
+	url := strings.TrimSuffix(getChartURL(chartmuseumURL+"/api", teamId, repo), "/charts") + "/" + endpoint
+	req, err := http.NewRequest("POST", url, file)
+	if err != nil {
+		log.Error("cannot create push chart request", logger.Fields{"error": err})
+		return ankr_default.ErrCreateRequest
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	res, err := chartmuseumClient.Do(req.WithContext(ctx))
+	if err != nil {
+		log.Error("cannot push chart to chartmuseum", logger.Fields{"error": err})
+		return chartmuseumUnavailable(err, ankr_default.ErrCannotUploadChartTar)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusCreated {
+		err := chartmuseumStatus(res, ankr_default.ErrCannotUploadChartTar)
+		log.Warn("chartmuseum rejected chart", logger.Fields{"status": res.StatusCode, "error": err})
+		return err
+	}
+
+	return nil
+}
+
+// pushChart packages loadedChart in a temp dir of its own, pushes it to the repo and removes the dir
+func (p *AppMgrHandler) pushChart(ctx context.Context, teamId, repo string, loadedChart *chart.Chart) error {
+	log := p.logger.WithContext(ctx).With(logger.Fields{"repo": repo})
+	dir, err := ioutil.TempDir("", "appmgr-chart-")
+	if err != nil {
+		log.Error("cannot create chart outdir", logger.Fields{"error": err})
+		return ankr_default.ErrCannotGetChartOutdir
+	}
+	defer os.RemoveAll(dir)
+
+	tarballName, err := chartutil.Save(loadedChart, dir)
+	if err != nil {
+		log.Error("cannot package chart", logger.Fields{"error": err})
+		return ankr_default.ErrCannotGetChartOutdir
+	}
+	log.Debug("packaged chart", logger.Fields{"tarball": tarballName})
+
+	tarball, err := os.Open(tarballName)
+	if err != nil {
+		log.Error("cannot open chart tar file", logger.Fields{"error": err})
+		return ankr_default.ErrCannotGetChartTar
+	}
+	defer tarball.Close()
+
+	return p.pushChartFile(ctx, teamId, repo, "charts", tarball)
+}
+
+// deleteChartVersion deletes a chart version from the repo
+func (p *AppMgrHandler) deleteChartVersion(ctx context.Context, teamId, repo, name, version string) error {
+	log := p.logger.WithContext(ctx).With(logger.Fields{"repo": repo, "chart": name + "-" + version})
+	delReq, err := http.NewRequest("DELETE", getChartURL(chartmuseumURL+"/api", teamId, repo)+"/"+name+"/"+version, nil)
+	if err != nil {
+		log.Error("cannot create delete chart request", logger.Fields{"error": err})
+		return ankr_default.ErrCreateRequest
+	}
+
+	delRes, err := chartmuseumClient.Do(delReq.WithContext(ctx))
+	if err != nil {
+		log.Error("cannot delete chart file", logger.Fields{"error": err})
+		return chartmuseumUnavailable(err, errors.New(ankr_default.LogicError+"Cannot delete chart file"))
+	}
+	defer delRes.Body.Close()
+
+	if delRes.StatusCode != http.StatusOK {
+		return chartmuseumStatus(delRes, errors.New(ankr_default.LogicError+"Cannot delete chart file"))
+	}
+
+	return nil
+}
+
+// verifyChartDigest checks the sha256 of chartFile against the digest of the chartmuseum index
+func verifyChartDigest(chartFile []byte, digest string) error {
+	if len(digest) == 0 {
+		return errChartDigestMissing
+	}
+
+	sum := sha256.Sum256(chartFile)
+	if !strings.EqualFold(hex.EncodeToString(sum[:]), strings.TrimPrefix(digest, "sha256:")) {
+		return errChartDigestMismatch
+	}
+
+	return nil
+}
+
+// verifyChartProvenance checks the signature of provFile with the configured keyring and that it signs chartFile
+func (p *AppMgrHandler) verifyChartProvenance(ctx context.Context, tarballName string, chartFile, provFile []byte) error {
+	dir, err := ioutil.TempDir("", "appmgr-prov-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	chartPath := filepath.Join(dir, tarballName)
+	if err := ioutil.WriteFile(chartPath, chartFile, 0600); err != nil {
+		return err
+	}
+
+	return p.verifyChartProvenanceFile(ctx, chartPath, provFile)
+}
+
+// verifyChartProvenanceFile checks provFile signs the chart tarball at chartPath, writing it next to the tarball
+func (p *AppMgrHandler) verifyChartProvenanceFile(ctx context.Context, chartPath string, provFile []byte) error {
+	provPath := chartPath + ".prov"
+	if err := ioutil.WriteFile(provPath, provFile, 0600); err != nil {
+		return err
+	}
+
+	verification, err := p.signatory.Verify(chartPath, provPath)
+	if err != nil {
+		return err
+	}
+	signers := make([]string, 0, len(verification.SignedBy.Identities))
+	for name := range verification.SignedBy.Identities {
+		signers = append(signers, name)
+	}
+	p.logger.WithContext(ctx).Debug("chart signed", logger.Fields{"chart": filepath.Base(chartPath), "signed_by": signers})
+
+	return nil
+}
+
 func getChartURL(url string, teamId string, repo string) string {
 
 	if repo == "user" {
@@ -0,0 +1,25 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// ChartmuseumHealth checks chartmuseum answers its health endpoint, for the readiness probe. It goes around
+// chartmuseumClient so checks are neither traced nor counted as chartmuseum requests of the rpcs.
+func (p *AppMgrHandler) ChartmuseumHealth(ctx context.Context) error {
+	req, err := http.NewRequest("GET", chartmuseumURL+"/health", nil)
+	if err != nil {
+		return err
+	}
+	res, err := http.DefaultClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return errors.New("chartmuseum health answered " + res.Status)
+	}
+	return nil
+}
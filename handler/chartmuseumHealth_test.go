@@ -0,0 +1,36 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestChartmuseumHealth(t *testing.T) {
+	var unhealthy int32
+	museum := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/health" || atomic.LoadInt32(&unhealthy) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"healthy":true}`))
+	}))
+
+	defer func(url string) { chartmuseumURL = url }(chartmuseumURL)
+	chartmuseumURL = museum.URL
+
+	p := &AppMgrHandler{}
+	if err := p.ChartmuseumHealth(context.Background()); err != nil {
+		t.Errorf("healthy chartmuseum failed the check, %s", err)
+	}
+	atomic.StoreInt32(&unhealthy, 1)
+	if err := p.ChartmuseumHealth(context.Background()); err == nil {
+		t.Error("unhealthy chartmuseum passed the check")
+	}
+	museum.Close()
+	if err := p.ChartmuseumHealth(context.Background()); err == nil {
+		t.Error("unreachable chartmuseum passed the check")
+	}
+}
@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// chartmuseumCodes maps chartmuseum response status codes to grpc codes, other 4xx are FailedPrecondition
+// and other 5xx are Unavailable
+var chartmuseumCodes = map[int]codes.Code{
+	http.StatusBadRequest:            codes.InvalidArgument,
+	http.StatusUnauthorized:          codes.Unauthenticated,
+	http.StatusForbidden:             codes.PermissionDenied,
+	http.StatusNotFound:              codes.NotFound,
+	http.StatusConflict:              codes.AlreadyExists,
+	http.StatusRequestEntityTooLarge: codes.ResourceExhausted,
+	http.StatusTooManyRequests:       codes.ResourceExhausted,
+	http.StatusInternalServerError:   codes.Internal,
+	http.StatusNotImplemented:        codes.Unimplemented,
+}
+
+// chartmuseumStatus turns an unexpected chartmuseum response into a grpc status error with the message of
+// fallback, the error the handler used to return, followed by the error chartmuseum gave
+func chartmuseumStatus(res *http.Response, fallback error) error {
+	code, ok := chartmuseumCodes[res.StatusCode]
+	if !ok {
+		code = codes.FailedPrecondition
+		if res.StatusCode >= 500 {
+			code = codes.Unavailable
+		}
+	}
+
+	reason := http.StatusText(res.StatusCode)
+	if body, err := ioutil.ReadAll(res.Body); err == nil && len(body) > 0 {
+		// chartmuseum answers errors with {"error": "..."}
+		message := struct {
+			Error string `json:"error"`
+		}{}
+		if err := json.Unmarshal(body, &message); err == nil && len(message.Error) > 0 {
+			reason = message.Error
+		} else {
+			reason = strings.TrimSpace(string(body))
+		}
+	}
+
+	return status.Error(code, fallback.Error()+": "+reason)
+}
+
+// chartmuseumUnavailable turns a failed request to chartmuseum into a grpc Unavailable error
+func chartmuseumUnavailable(err error, fallback error) error {
+	return status.Error(codes.Unavailable, fallback.Error()+": "+err.Error())
+}
+
+// isNotFound tells whether chartmuseum answered that a chart or file does not exist
+func isNotFound(err error) bool {
+	return status.Code(err) == codes.NotFound
+}
@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestChartmuseumStatus(t *testing.T) {
+	fallback := errors.New("cannot upload chart")
+	cases := []struct {
+		code    int
+		body    string
+		want    codes.Code
+		message string
+	}{
+		{http.StatusConflict, `{"error":"file already exists"}`, codes.AlreadyExists, "cannot upload chart: file already exists"},
+		{http.StatusNotFound, `{"error":"not found"}`, codes.NotFound, "cannot upload chart: not found"},
+		{http.StatusForbidden, "", codes.PermissionDenied, "cannot upload chart: Forbidden"},
+		{http.StatusBadGateway, "bad gateway\n", codes.Unavailable, "cannot upload chart: bad gateway"},
+		{http.StatusInternalServerError, `{"error":"storage failure"}`, codes.Internal, "cannot upload chart: storage failure"},
+		{http.StatusGone, "", codes.FailedPrecondition, "cannot upload chart: Gone"},
+	}
+
+	for _, c := range cases {
+		err := chartmuseumStatus(&http.Response{
+			StatusCode: c.code,
+			Body:       ioutil.NopCloser(strings.NewReader(c.body)),
+		}, fallback)
+		s := status.Convert(err)
+		if s.Code() != c.want || s.Message() != c.message {
+			t.Errorf("status %d gives %s %q, want %s %q", c.code, s.Code(), s.Message(), c.want, c.message)
+		}
+	}
+}
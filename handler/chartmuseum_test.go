@@ -0,0 +1,258 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	common_proto "github.com/Ankr-network/dccn-common/protos/common"
+	"golang.org/x/crypto/openpgp"
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	"k8s.io/helm/pkg/provenance"
+)
+
+// fakeChartmuseum serves the index entries and files of a public repo, stable if repo is empty, keeps the
+// charts and provenance files pushed to it, and counts the files downloaded
+type fakeChartmuseum struct {
+	mu        sync.Mutex
+	repo      string
+	index     map[string][]Chart
+	files     map[string][]byte
+	downloads int
+	// fail answers "<method> <path>" requests with the status code instead
+	fail map[string]int
+	// tamper changes the digest of the charts pushed
+	tamper  bool
+	deleted []string
+}
+
+func (m *fakeChartmuseum) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if code, ok := m.fail[r.Method+" "+r.URL.Path]; ok {
+		w.WriteHeader(code)
+		return
+	}
+
+	repo := m.repo
+	if len(repo) == 0 {
+		repo = "stable"
+	}
+	api, files := "/api/public/"+repo+"/charts", "/public/"+repo+"/charts/"
+	switch {
+	case r.Method == http.MethodPost:
+		m.push(w, r, strings.HasSuffix(r.URL.Path, "/prov"))
+	case r.Method == http.MethodDelete && strings.HasPrefix(r.URL.Path, api+"/"):
+		parts := strings.Split(strings.TrimPrefix(r.URL.Path, api+"/"), "/")
+		if len(parts) != 2 {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		versions := make([]Chart, 0)
+		for _, c := range m.index[parts[0]] {
+			if c.Version != parts[1] {
+				versions = append(versions, c)
+			}
+		}
+		m.index[parts[0]] = versions
+		tarballName := parts[0] + "-" + parts[1] + ".tgz"
+		delete(m.files, tarballName)
+		delete(m.files, tarballName+".prov")
+		m.deleted = append(m.deleted, parts[0]+"-"+parts[1])
+	case r.URL.Path == api:
+		_ = json.NewEncoder(w).Encode(m.index)
+	case strings.HasPrefix(r.URL.Path, api+"/"):
+		parts := strings.Split(strings.TrimPrefix(r.URL.Path, api+"/"), "/")
+		for _, c := range m.index[parts[0]] {
+			if len(parts) == 2 && c.Version == parts[1] {
+				_ = json.NewEncoder(w).Encode(c)
+				return
+			}
+		}
+		w.WriteHeader(http.StatusNotFound)
+	case strings.HasPrefix(r.URL.Path, files):
+		file, ok := m.files[strings.TrimPrefix(r.URL.Path, files)]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		m.downloads++
+		_, _ = w.Write(file)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// push keeps a pushed chart in the index, or a provenance file under "pushed.prov", it must be called with the lock held
+func (m *fakeChartmuseum) push(w http.ResponseWriter, r *http.Request, prov bool) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if m.files == nil {
+		m.files = map[string][]byte{}
+	}
+	if prov {
+		m.files["pushed.prov"] = body
+		w.WriteHeader(http.StatusCreated)
+		return
+	}
+
+	loaded, err := chartutil.LoadArchive(bytes.NewReader(body))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	digest := sha256Digest(body)
+	if m.tamper {
+		digest = sha256Digest([]byte("tampered"))
+	}
+	if m.index == nil {
+		m.index = map[string][]Chart{}
+	}
+	name, version := loaded.Metadata.Name, loaded.Metadata.Version
+	m.index[name] = append(m.index[name], Chart{Name: name, Version: version, Digest: digest})
+	m.files[name+"-"+version+".tgz"] = body
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (m *fakeChartmuseum) downloaded() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.downloads
+}
+
+// serveChartmuseum points chartmuseumURL at museum until the returned func is called
+func serveChartmuseum(museum http.Handler) func() {
+	server := httptest.NewServer(museum)
+	url := chartmuseumURL
+	chartmuseumURL = server.URL
+	return func() {
+		chartmuseumURL = url
+		server.Close()
+	}
+}
+
+// testChart packages a chart of name and version into dir and returns the path of its tarball
+func testChart(t *testing.T, dir, name, version string) string {
+	chartPath, err := chartutil.Save(&chart.Chart{
+		Metadata: &chart.Metadata{Name: name, Version: version, ApiVersion: "v1"},
+	}, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return chartPath
+}
+
+func testSignatory(t *testing.T, name string) *provenance.Signatory {
+	entity, err := openpgp.NewEntity(name, "", name+"@example.com", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &provenance.Signatory{Entity: entity, KeyRing: openpgp.EntityList{entity}}
+}
+
+func sha256Digest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func TestVerifyChartDigest(t *testing.T) {
+	chartFile := []byte("chart")
+	digest := sha256Digest(chartFile)
+
+	for _, d := range []string{digest, "sha256:" + digest, strings.ToUpper(digest)} {
+		if err := verifyChartDigest(chartFile, d); err != nil {
+			t.Errorf("digest %s: %v", d, err)
+		}
+	}
+	if err := verifyChartDigest(chartFile, ""); err != errChartDigestMissing {
+		t.Errorf("no digest gives %v, want %v", err, errChartDigestMissing)
+	}
+	if err := verifyChartDigest([]byte("tampered"), digest); err != errChartDigestMismatch {
+		t.Errorf("tampered chart gives %v, want %v", err, errChartDigestMismatch)
+	}
+}
+
+func TestDownloadChartArchive(t *testing.T) {
+	dir, err := ioutil.TempDir("", "download-chart-test-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	chartPath := testChart(t, dir, "test", "1.0.0")
+	chartFile, err := ioutil.ReadFile(chartPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signatory := testSignatory(t, "appmgr")
+	prov, err := signatory.ClearSign(chartPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	forged, err := testSignatory(t, "someone else").ClearSign(chartPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	detail := &common_proto.ChartDetail{ChartName: "test", ChartRepo: "stable", ChartVer: "1.0.0"}
+	cases := []struct {
+		name      string
+		digest    string
+		chartFile []byte
+		prov      string
+		signatory *provenance.Signatory
+		err       error
+	}{
+		{"verified digest", sha256Digest(chartFile), chartFile, "", nil, nil},
+		{"digest mismatch", sha256Digest(chartFile), []byte("tampered"), "", nil, errChartDigestMismatch},
+		{"digest missing", "", chartFile, "", nil, errChartDigestMissing},
+		{"verified provenance", "sha256:" + sha256Digest(chartFile), chartFile, prov, signatory, nil},
+		{"provenance missing", sha256Digest(chartFile), chartFile, "", signatory, errChartProvenance},
+		{"provenance of another key", sha256Digest(chartFile), chartFile, forged, signatory, errChartProvenance},
+	}
+	for _, c := range cases {
+		museum := &fakeChartmuseum{
+			index: map[string][]Chart{"test": {{Name: "test", Version: "1.0.0", Digest: c.digest}}},
+			files: map[string][]byte{"test-1.0.0.tgz": c.chartFile},
+		}
+		if len(c.prov) > 0 {
+			museum.files["test-1.0.0.tgz.prov"] = []byte(c.prov)
+		}
+		p := &AppMgrHandler{charts: newChartCache(time.Minute, 1<<20), signatory: c.signatory}
+		stop := serveChartmuseum(museum)
+
+		got, err := p.downloadChartArchive(context.Background(), "team-1", detail)
+		switch {
+		case err != c.err:
+			t.Errorf("%s: got error %v, want %v", c.name, err, c.err)
+		case err != nil:
+			if _, ok := p.charts.getArchive(chartKey("team-1", "stable", "test", "1.0.0")); ok {
+				t.Errorf("%s: unverified chart was cached", c.name)
+			}
+		case string(got) != string(chartFile):
+			t.Errorf("%s: got %d bytes, want the chart", c.name, len(got))
+		default:
+			// verified charts are served from the cache
+			downloads := museum.downloaded()
+			if _, err := p.downloadChartArchive(context.Background(), "team-1", detail); err != nil || museum.downloaded() != downloads {
+				t.Errorf("%s: second download gives %v after %d more downloads", c.name, err, museum.downloaded()-downloads)
+			}
+		}
+		stop()
+	}
+}
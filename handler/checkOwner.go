@@ -1,25 +1,26 @@
 package handler
 
 import (
+	"context"
+
+	"github.com/Ankr-network/dccn-appmgr/logger"
 	ankr_default "github.com/Ankr-network/dccn-common/protos"
 	common_proto "github.com/Ankr-network/dccn-common/protos/common"
-	"log"
 )
 
-func (p *AppMgrHandler) checkOwner(teamId, appId string) (*common_proto.AppReport, error) {
+func (p *AppMgrHandler) checkOwner(ctx context.Context, teamId, appId string) (*common_proto.AppReport, error) {
 	appRecord, err := p.db.GetApp(appId)
 
 	if err != nil {
 		return nil, err
 	}
 
-	log.Printf("appid : %s user id -%s-   user_token_id -%s-  ", appId, appRecord.TeamID, teamId)
-
 	if appRecord.TeamID != teamId {
+		p.logger.WithContext(ctx).Warn("app not owned by team", logger.Fields{"app_id": appId, "team_id": teamId})
 		return nil, ankr_default.ErrUserNotOwn
 	}
 
-	appMessage := convertToAppMessage(appRecord, p.db)
+	appMessage := p.convertToAppMessage(ctx, appRecord)
 
 	return &appMessage, nil
 }
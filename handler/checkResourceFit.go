@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+
+	ankr_default "github.com/Ankr-network/dccn-common/protos"
+	common_proto "github.com/Ankr-network/dccn-common/protos/common"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+)
+
+// chartResourceRequests renders loadedChart with the prefixed custom values and sums the resources it requests
+func chartResourceRequests(loadedChart *chart.Chart, customValues []*common_proto.CustomValue) (ResourceRequests, error) {
+	manifests, _, err := renderChart(loadedChart, customValues, loadedChart.Metadata.Name, "default")
+	if err != nil {
+		return ResourceRequests{}, err
+	}
+	return computeResourceRequests(manifests)
+}
+
+// checkResourceFit rejects requests which do not fit in the namespace limits on top of its current usage
+func checkResourceFit(nsId string, limits, usage, requests ResourceRequests) error {
+	if uint64(usage.Cpu)+uint64(requests.Cpu) > uint64(limits.Cpu) {
+		return quotaExceeded(nsId, fmt.Sprintf("app requests %dm cpu, namespace %s has %dm of %dm cpu left",
+			requests.Cpu, nsId, remaining(limits.Cpu, usage.Cpu), limits.Cpu))
+	}
+	if uint64(usage.Mem)+uint64(requests.Mem) > uint64(limits.Mem) {
+		return quotaExceeded(nsId, fmt.Sprintf("app requests %dMi memory, namespace %s has %dMi of %dMi memory left",
+			requests.Mem, nsId, remaining(limits.Mem, usage.Mem), limits.Mem))
+	}
+	if uint64(usage.Storage)+uint64(requests.Storage) > uint64(limits.Storage) {
+		return quotaExceeded(nsId, fmt.Sprintf("app requests %dMi storage, namespace %s has %dMi of %dMi storage left",
+			requests.Storage, nsId, remaining(limits.Storage, usage.Storage), limits.Storage))
+	}
+	return nil
+}
+
+// quotaExceeded is a ResourceExhausted error telling which namespace limit the app does not fit in
+func quotaExceeded(nsId, description string) error {
+	return withDetails(codes.ResourceExhausted, errors.New(ankr_default.LogicError+description), &errdetails.QuotaFailure{
+		Violations: []*errdetails.QuotaFailure_Violation{{Subject: "namespace/" + nsId, Description: description}},
+	})
+}
+
+// extraRequests returns how much more the updated requests ask for than the current ones, per resource
+func extraRequests(current, updated ResourceRequests) ResourceRequests {
+	return ResourceRequests{
+		Cpu:     remaining(updated.Cpu, current.Cpu),
+		Mem:     remaining(updated.Mem, current.Mem),
+		Storage: remaining(updated.Storage, current.Storage),
+	}
+}
+
+func remaining(total, used uint32) uint32 {
+	if used > total {
+		return 0
+	}
+	return total - used
+}
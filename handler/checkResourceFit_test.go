@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"testing"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCheckResourceFit(t *testing.T) {
+	limits := ResourceRequests{Cpu: 2000, Mem: 4096, Storage: 10240}
+	usage := ResourceRequests{Cpu: 1500, Mem: 1024, Storage: 10000}
+
+	cases := []struct {
+		name     string
+		requests ResourceRequests
+		fits     bool
+	}{
+		{"nothing", ResourceRequests{}, true},
+		{"exactly what is left", ResourceRequests{Cpu: 500, Mem: 3072, Storage: 240}, true},
+		{"cpu over", ResourceRequests{Cpu: 501}, false},
+		{"memory over", ResourceRequests{Mem: 3073}, false},
+		{"storage over", ResourceRequests{Storage: 241}, false},
+		{"past uint32", ResourceRequests{Cpu: ^uint32(0)}, false},
+	}
+	for _, c := range cases {
+		err := checkResourceFit("ns-1", limits, usage, c.requests)
+		if c.fits {
+			if err != nil {
+				t.Errorf("%s: %v", c.name, err)
+			}
+			continue
+		}
+
+		// the StatusInterceptor keeps the code and details
+		s, _ := status.FromError(toStatus(err))
+		if s.Code() != codes.ResourceExhausted {
+			t.Errorf("%s: got %v, want ResourceExhausted", c.name, err)
+			continue
+		}
+		if len(s.Details()) != 1 {
+			t.Errorf("%s: got details %v, want a quota failure", c.name, s.Details())
+			continue
+		}
+		quota, ok := s.Details()[0].(*errdetails.QuotaFailure)
+		if !ok || len(quota.Violations) != 1 || quota.Violations[0].Subject != "namespace/ns-1" {
+			t.Errorf("%s: got details %v, want a quota failure of namespace/ns-1", c.name, s.Details())
+		}
+	}
+}
+
+func TestExtraRequests(t *testing.T) {
+	current := ResourceRequests{Cpu: 500, Mem: 512, Storage: 1024}
+
+	grown := extraRequests(current, ResourceRequests{Cpu: 750, Mem: 512, Storage: 2048})
+	if grown != (ResourceRequests{Cpu: 250, Storage: 1024}) {
+		t.Errorf("grown update asks for %+v more", grown)
+	}
+	if shrunk := extraRequests(current, ResourceRequests{Cpu: 100}); shrunk != (ResourceRequests{}) {
+		t.Errorf("shrunk update asks for %+v more", shrunk)
+	}
+
+	// an update of an app already counted in the usage only needs room for what it adds
+	limits := ResourceRequests{Cpu: 1000, Mem: 1024, Storage: 4096}
+	usage := ResourceRequests{Cpu: 700, Mem: 512, Storage: 1024}
+	if err := checkResourceFit("ns-1", limits, usage, grown); err != nil {
+		t.Errorf("update adding %+v to %+v of %+v: %v", grown, usage, limits, err)
+	}
+	if err := checkResourceFit("ns-1", limits, usage, extraRequests(current, ResourceRequests{Cpu: 801})); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("update adding 301m cpu to 700m of 1000m gives %v, want ResourceExhausted", err)
+	}
+}
@@ -0,0 +1,243 @@
+package handler
+
+import (
+	"context"
+	"time"
+
+	db "github.com/Ankr-network/dccn-appmgr/db_service"
+	"github.com/Ankr-network/dccn-appmgr/logger"
+	"github.com/Ankr-network/dccn-appmgr/protos/appmgrext"
+	"github.com/Ankr-network/dccn-appmgr/tracing"
+	common_proto "github.com/Ankr-network/dccn-common/protos/common"
+	common_util "github.com/Ankr-network/dccn-common/util"
+	"github.com/Masterminds/semver"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"go.opentelemetry.io/otel/api/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// upgradeAvailableKey is the response header listing the apps which have a newer chart version,
+// one "<app id> <patch|minor|major> <version>" value per app
+const upgradeAvailableKey = "upgrade-available"
+
+// RunUpgradeCheck checks running apps for newer chart versions every interval, until ctx is done
+func (p *AppMgrHandler) RunUpgradeCheck(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		p.CheckUpgrades()
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// CheckUpgrades compares the chart version of each running app with the latest stable version
+// of its repo, flags the available upgrade on the app record, and upgrades the apps whose policy
+// allows it when inside the maintenance window
+func (p *AppMgrHandler) CheckUpgrades() {
+	ctx, span := tracing.Start(context.Background(), "CheckUpgrades", trace.SpanKindInternal)
+	defer span.End()
+	p = p.tracedBy(ctx)
+	apps, err := p.db.GetAllRunningApps()
+	if err != nil {
+		p.logger.Error("cannot get running apps to check upgrades", logger.Fields{"error": err})
+		return
+	}
+
+	for _, app := range apps {
+		log := p.logger.With(logger.Fields{"app_id": app.ID})
+		upgrade, err := p.availableUpgrade(ctx, app)
+		if err != nil {
+			log.Warn("cannot check upgrade of app", logger.Fields{"error": err})
+			continue
+		}
+
+		// the record is only written when the upgrade found changes
+		if upgrade.Kind != app.Upgrade.Kind || upgrade.Version != app.Upgrade.Version {
+			log.Info("upgrade available", logger.Fields{"chart": app.ChartDetail.ChartName,
+				"chart_ver": app.ChartDetail.ChartVer, "kind": upgrade.Kind, "version": upgrade.Version})
+			if err := p.db.Update("app", app.ID, bson.M{"$set": bson.M{"upgrade": upgrade}}); err != nil {
+				log.Error("cannot flag upgrade of app", logger.Fields{"error": err})
+			}
+		}
+
+		if len(app.AutoUpgrade.Policy) > 0 && len(upgrade.Kind) > 0 && p.window.contains(time.Now()) {
+			p.autoUpgrade(ctx, app)
+		}
+	}
+}
+
+// autoUpgrade updates the app to the highest version its policy allows, the subscriber rolls it back if it fails
+func (p *AppMgrHandler) autoUpgrade(ctx context.Context, app db.AppRecord) {
+	current, err := semver.NewVersion(app.ChartDetail.ChartVer)
+	if err != nil {
+		return
+	}
+	log := p.logger.With(logger.Fields{"app_id": app.ID})
+	charts, err := p.chartIndex(ctx, app.TeamID, app.ChartDetail.ChartRepo)
+	if err != nil {
+		log.Warn("cannot get charts to upgrade app", logger.Fields{"error": err})
+		return
+	}
+
+	target := upgradeTarget(current, sortChartVersions(charts[app.ChartDetail.ChartName], false), app.AutoUpgrade.Policy)
+	if len(target) == 0 || target == app.AutoUpgrade.FailedVersion {
+		return
+	}
+
+	appMessage := p.convertToAppMessage(ctx, app)
+	clusterConnection, err := p.db.GetClusterConnection(appMessage.AppDeployment.Namespace.ClusterId)
+	if err != nil || clusterConnection.Status != common_proto.DCStatus_AVAILABLE {
+		log.Warn("cluster connection not available, app can not be upgraded")
+		return
+	}
+
+	if err := p.db.Update("app", app.ID, bson.M{"$set": bson.M{"autoupgrade.upgrading": true}}); err != nil {
+		log.Error("cannot mark app upgrading", logger.Fields{"error": err})
+		return
+	}
+
+	// no call leads to the upgrade, it gets a correlation id of its own for the feedback of dcmgr
+	ctx = logger.WithCorrelationID(ctx, logger.NewCorrelationID())
+	log = log.WithContext(ctx)
+	log.Info("upgrade app", logger.Fields{"chart": app.ChartDetail.ChartName, "chart_ver": app.ChartDetail.ChartVer,
+		"target": target, "policy": app.AutoUpgrade.Policy})
+	if err := p.updateAppChart(ctx, app.TeamID, appMessage.AppDeployment, target); err != nil {
+		log.Error("cannot upgrade app", logger.Fields{"error": err})
+		if err := p.db.Update("app", app.ID, bson.M{"$set": bson.M{"autoupgrade.upgrading": false}}); err != nil {
+			log.Error("cannot unmark app upgrading", logger.Fields{"error": err})
+		}
+	}
+}
+
+// upgradeTarget returns the highest of the versions, sorted newest first, that policy allows upgrading current to
+func upgradeTarget(current *semver.Version, versions []Chart, policy string) string {
+	for _, v := range versions {
+		version, err := semver.NewVersion(v.Version)
+		if err != nil {
+			continue
+		}
+		switch upgradeKind(current, version) {
+		case db.UpgradePatch:
+			return v.Version
+		case db.UpgradeMinor:
+			if policy == db.UpgradeMinor {
+				return v.Version
+			}
+		case "":
+			return ""
+		}
+	}
+	return ""
+}
+
+// availableUpgrade finds the latest stable version of the app's chart, an empty kind if it is not newer
+func (p *AppMgrHandler) availableUpgrade(ctx context.Context, app db.AppRecord) (db.AppUpgrade, error) {
+	upgrade := db.AppUpgrade{CheckedDate: &timestamp.Timestamp{Seconds: time.Now().Unix()}}
+
+	current, err := semver.NewVersion(app.ChartDetail.ChartVer)
+	if err != nil {
+		return upgrade, err
+	}
+
+	charts, err := p.chartIndex(ctx, app.TeamID, app.ChartDetail.ChartRepo)
+	if err != nil {
+		return upgrade, err
+	}
+
+	latest, ok := latestChartVersion(charts[app.ChartDetail.ChartName], false)
+	if !ok {
+		return upgrade, nil
+	}
+	latestVersion, err := semver.NewVersion(latest.Version)
+	if err != nil {
+		return upgrade, nil
+	}
+
+	if kind := upgradeKind(current, latestVersion); len(kind) > 0 {
+		upgrade.Kind = kind
+		upgrade.Version = latest.Version
+	}
+	return upgrade, nil
+}
+
+// upgradeKind tells whether latest is a major, minor or patch upgrade of current, empty if it is not newer
+func upgradeKind(current, latest *semver.Version) string {
+	switch {
+	case !latest.GreaterThan(current):
+		return ""
+	case latest.Major() != current.Major():
+		return db.UpgradeMajor
+	case latest.Minor() != current.Minor():
+		return db.UpgradeMinor
+	default:
+		return db.UpgradePatch
+	}
+}
+
+// upgradeAvailable tells whether the app is running a chart version older than the upgrade flagged on it
+func upgradeAvailable(app db.AppRecord) bool {
+	return !app.Hidden && app.Status == common_proto.AppStatus_APP_RUNNING &&
+		len(app.Upgrade.Kind) > 0 && app.Upgrade.Version != app.ChartDetail.ChartVer
+}
+
+// AppUpgrades lists the upgrades available for the app of the request, or for all apps of the team
+func (p *AppMgrHandler) AppUpgrades(ctx context.Context, req *appmgrext.AppUpgradesRequest) (*appmgrext.AppUpgradesResponse, error) {
+	_, teamId := common_util.GetUserIDAndTeamID(ctx)
+	log := p.logger.WithContext(ctx)
+	rsp := &appmgrext.AppUpgradesResponse{Upgrades: make([]*appmgrext.AppUpgrade, 0)}
+
+	var apps []db.AppRecord
+	if len(req.AppId) > 0 {
+		app, err := p.db.GetApp(req.AppId)
+		if err != nil {
+			log.Error("cannot get app", logger.Fields{"app_id": req.AppId, "error": err})
+			return rsp, err
+		}
+		apps = append(apps, app)
+	} else {
+		var err error
+		if apps, err = p.db.GetAllApps(teamId); err != nil {
+			log.Error("cannot get apps of team", logger.Fields{"team_id": teamId, "error": err})
+			return rsp, err
+		}
+	}
+
+	for _, app := range apps {
+		if !upgradeAvailable(app) {
+			continue
+		}
+		upgrade := &appmgrext.AppUpgrade{AppId: app.ID, Kind: app.Upgrade.Kind, Version: app.Upgrade.Version}
+		if app.Upgrade.CheckedDate != nil {
+			upgrade.FoundDate = app.Upgrade.CheckedDate.Seconds
+		}
+		rsp.Upgrades = append(rsp.Upgrades, upgrade)
+	}
+
+	return rsp, nil
+}
+
+// setUpgradeHeader sends the upgrades available for apps in the upgrade-available response header
+func (p *AppMgrHandler) setUpgradeHeader(ctx context.Context, apps ...db.AppRecord) {
+	upgrades := make([]string, 0)
+	for _, app := range apps {
+		if upgradeAvailable(app) {
+			upgrades = append(upgrades, app.ID+" "+app.Upgrade.Kind+" "+app.Upgrade.Version)
+		}
+	}
+	if len(upgrades) == 0 {
+		return
+	}
+
+	md := metadata.MD{}
+	md.Append(upgradeAvailableKey, upgrades...)
+	if err := grpc.SetHeader(ctx, md); err != nil {
+		p.logger.WithContext(ctx).Warn("cannot set upgrade-available header", logger.Fields{"error": err})
+	}
+}
@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"testing"
+
+	db "github.com/Ankr-network/dccn-appmgr/db_service"
+	common_proto "github.com/Ankr-network/dccn-common/protos/common"
+	"github.com/Masterminds/semver"
+	"gopkg.in/mgo.v2/bson"
+)
+
+func TestUpgradeKind(t *testing.T) {
+	cases := []struct {
+		current, latest, kind string
+	}{
+		{"1.2.3", "1.2.3", ""},
+		{"1.2.3", "1.2.2", ""},
+		{"1.2.3", "1.2.10", db.UpgradePatch},
+		{"1.2.3", "1.3.0", db.UpgradeMinor},
+		{"1.2.3", "2.0.0", db.UpgradeMajor},
+		{"1.2.3-rc.1", "1.2.3", db.UpgradePatch},
+	}
+
+	for _, c := range cases {
+		kind := upgradeKind(semver.MustParse(c.current), semver.MustParse(c.latest))
+		if kind != c.kind {
+			t.Errorf("upgrade from %s to %s is %q, want %q", c.current, c.latest, kind, c.kind)
+		}
+	}
+}
+
+func TestUpgradeTarget(t *testing.T) {
+	versions := []Chart{{Version: "2.0.0"}, {Version: "1.3.1"}, {Version: "1.3.0"}, {Version: "1.2.5"}, {Version: "1.2.3"}}
+
+	cases := []struct {
+		current, policy, target string
+	}{
+		{"1.2.3", db.UpgradePatch, "1.2.5"},
+		{"1.2.3", db.UpgradeMinor, "1.3.1"},
+		{"1.2.5", db.UpgradePatch, ""},
+		{"1.3.1", db.UpgradeMinor, ""},
+		{"2.0.0", db.UpgradeMinor, ""},
+	}
+
+	for _, c := range cases {
+		target := upgradeTarget(semver.MustParse(c.current), versions, c.policy)
+		if target != c.target {
+			t.Errorf("%s upgrade of %s is %q, want %q", c.policy, c.current, target, c.target)
+		}
+	}
+}
+
+// upgradesDB serves the running apps to the upgrade check and keeps the updates it writes
+type upgradesDB struct {
+	db.DBService
+	apps    []db.AppRecord
+	updates map[string]bson.M
+}
+
+func (d *upgradesDB) GetAllRunningApps() ([]db.AppRecord, error) {
+	return d.apps, nil
+}
+
+func (d *upgradesDB) Update(collection string, id string, update bson.M) error {
+	d.updates[collection+"/"+id] = update
+	return nil
+}
+
+func TestCheckUpgradesWritesChanges(t *testing.T) {
+	museum := &fakeChartmuseum{index: map[string][]Chart{
+		"redis": {{Name: "redis", Version: "1.2.5"}, {Name: "redis", Version: "1.2.3"}},
+	}}
+	defer serveChartmuseum(museum)()
+
+	app := func(id, kind, version string) db.AppRecord {
+		return db.AppRecord{
+			ID:          id,
+			ChartDetail: common_proto.ChartDetail{ChartName: "redis", ChartRepo: "stable", ChartVer: "1.2.3"},
+			Upgrade:     db.AppUpgrade{Kind: kind, Version: version},
+		}
+	}
+	fake := &upgradesDB{
+		apps: []db.AppRecord{
+			app("flagged", db.UpgradePatch, "1.2.5"),
+			app("unflagged", "", ""),
+			app("stale", db.UpgradePatch, "1.2.4"),
+		},
+		updates: map[string]bson.M{},
+	}
+	p := &AppMgrHandler{db: fake, charts: newChartCache(0, 0)}
+
+	p.CheckUpgrades()
+
+	if _, ok := fake.updates["app/flagged"]; ok {
+		t.Error("unchanged upgrade written again")
+	}
+	for _, id := range []string{"unflagged", "stale"} {
+		update, ok := fake.updates["app/"+id]
+		if !ok {
+			t.Errorf("upgrade of %s not written", id)
+			continue
+		}
+		upgrade, _ := update["$set"].(bson.M)["upgrade"].(db.AppUpgrade)
+		if upgrade.Kind != db.UpgradePatch || upgrade.Version != "1.2.5" {
+			t.Errorf("upgrade of %s written as %+v", id, upgrade)
+		}
+	}
+}
+
+func TestUpgradeAvailable(t *testing.T) {
+	running := db.AppRecord{
+		Status:      common_proto.AppStatus_APP_RUNNING,
+		ChartDetail: common_proto.ChartDetail{ChartVer: "1.2.3"},
+		Upgrade:     db.AppUpgrade{Kind: db.UpgradeMinor, Version: "1.3.0"},
+	}
+	if !upgradeAvailable(running) {
+		t.Error("flagged running app has no upgrade")
+	}
+
+	upgraded := running
+	upgraded.ChartDetail.ChartVer = "1.3.0"
+	hidden := running
+	hidden.Hidden = true
+	failed := running
+	failed.Status = common_proto.AppStatus_APP_FAILED
+	for _, app := range []db.AppRecord{upgraded, hidden, failed} {
+		if upgradeAvailable(app) {
+			t.Errorf("upgrade available for %+v", app)
+		}
+	}
+}
@@ -1,14 +1,15 @@
 package handler
 
 import (
-	"log"
+	"context"
 	"strings"
 
 	db "github.com/Ankr-network/dccn-appmgr/db_service"
+	"github.com/Ankr-network/dccn-appmgr/logger"
 	common_proto "github.com/Ankr-network/dccn-common/protos/common"
 )
 
-func convertToAppMessage(app db.AppRecord, pdb db.DBService) common_proto.AppReport {
+func (p *AppMgrHandler) convertToAppMessage(ctx context.Context, app db.AppRecord) common_proto.AppReport {
 	message := common_proto.AppDeployment{}
 	message.AppId = app.ID
 	message.AppName = app.Name
@@ -19,9 +20,10 @@ func convertToAppMessage(app db.AppRecord, pdb db.DBService) common_proto.AppRep
 	message.TeamId = app.TeamID
 	message.ChartDetail = &app.ChartDetail
 	message.CustomValues = app.CustomValues
-	namespaceRecord, err := pdb.GetNamespace(app.NamespaceID)
+	namespaceRecord, err := p.db.GetNamespace(app.NamespaceID)
 	if err != nil {
-		log.Printf("get namespace record failed, %s", err.Error())
+		p.logger.WithContext(ctx).Warn("cannot get namespace of app", logger.Fields{"app_id": app.ID,
+			"namespace_id": app.NamespaceID, "error": err})
 	}
 	namespaceReport := convertFromNamespaceRecord(namespaceRecord)
 	message.Namespace = namespaceReport.Namespace
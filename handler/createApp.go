@@ -2,25 +2,22 @@ package handler
 
 import (
 	"context"
-	"errors"
-	"log"
-	"net/http"
 
+	"github.com/Ankr-network/dccn-appmgr/logger"
 	ankr_default "github.com/Ankr-network/dccn-common/protos"
 	appmgr "github.com/Ankr-network/dccn-common/protos/appmgr/v1/grpc"
 	common_proto "github.com/Ankr-network/dccn-common/protos/common"
 	common_util "github.com/Ankr-network/dccn-common/util"
 	"github.com/google/uuid"
-	"k8s.io/helm/pkg/chartutil"
 )
 
 func (p *AppMgrHandler) CreateApp(ctx context.Context, req *appmgr.CreateAppRequest) (*appmgr.CreateAppResponse, error) {
 
 	creator, teamId := common_util.GetUserIDAndTeamID(ctx)
-	log.Printf(">>>>>>>>>Debug into CreateApp %+v \nctx: %+v , creator %s  team_id %s \n", req, ctx, creator, teamId)
+	log := p.logger.WithContext(ctx)
 
 	if req.App == nil {
-		log.Printf("invalid input: null app provided, %+v \n", req)
+		log.Warn("invalid input: null app provided")
 		return nil, ankr_default.ErrNoApp
 	}
 
@@ -29,35 +26,69 @@ func (p *AppMgrHandler) CreateApp(ctx context.Context, req *appmgr.CreateAppRequ
 	rsp := &appmgr.CreateAppResponse{
 		AppId: appDeployment.AppId,
 	}
+	log = log.With(logger.Fields{"app_id": appDeployment.AppId})
 	appDeployment.AppName = req.App.AppName
 	if len(appDeployment.AppName) == 0 {
-		log.Printf("invalid input: null app name provided, %+v \n", req.App.AppName)
+		log.Warn("invalid input: null app name provided")
 		return rsp, ankr_default.ErrNoAppname
 	}
 
 	if req.App.NamespaceData == nil {
-		log.Printf("invalid input: null namespace provided, %+v \n", req.App.NamespaceData)
+		log.Warn("invalid input: null namespace provided")
 		return rsp, ankr_default.ErrNsEmpty
 	}
 
+	if req.App.ChartDetail == nil {
+		log.Warn("invalid input: null chart detail provided")
+		return rsp, ankr_default.ErrChartDetailEmpty
+	}
+	appDeployment.ChartDetail = req.App.ChartDetail
+	loadedChart, err := p.getChartArchive(ctx, teamId, req.App.ChartDetail)
+	if err != nil {
+		return rsp, err
+	}
+
+	requests, err := chartResourceRequests(loadedChart, prefixCustomValues(req.App.CustomValues))
+	if err != nil {
+		log.Warn("cannot compute resource requests of chart", logger.Fields{"chart": req.App.ChartDetail.ChartName,
+			"error": err})
+		return rsp, ankr_default.ErrCannotLoadChart
+	}
+
 	switch req.App.NamespaceData.(type) {
 
 	case *common_proto.App_NsId:
 
 		namespaceRecord, err := p.db.GetNamespace(req.App.GetNsId())
 		if err != nil {
-			log.Printf("get namespace failed, %s", err.Error())
+			log.Error("cannot get namespace", logger.Fields{"namespace_id": req.App.GetNsId(), "error": err})
 			return rsp, err
 		}
 		if namespaceRecord.Status != common_proto.NamespaceStatus_NS_RUNNING {
-			log.Printf("namespace status not running")
-			return rsp, ankr_default.ErrStatusNotSupportOperation
+			log.Warn("namespace status not running", logger.Fields{"namespace_id": namespaceRecord.ID,
+				"status": namespaceRecord.Status.String()})
+			return rsp, statusBlocked("namespace/"+namespaceRecord.ID, namespaceRecord.Status.String(),
+				ankr_default.ErrStatusNotSupportOperation)
 		}
 
 		clusterConnection, err := p.db.GetClusterConnection(namespaceRecord.ClusterID)
-		if err != nil || clusterConnection.Status != common_proto.DCStatus_AVAILABLE {
-			log.Println("cluster connection not available, app can not be created")
-			return rsp, errors.New("cluster connection not available, app can not be created")
+		if err != nil || clusterConnection.Status != common_proto.DCStatus_AVAILABLE || clusterConnection.Draining {
+			log.Warn("cluster connection not available or draining, app can not be created",
+				logger.Fields{"cluster_id": namespaceRecord.ClusterID})
+			return rsp, clusterUnavailable(namespaceRecord.ClusterID, "app can not be created")
+		}
+
+		if err := checkResourceFit(namespaceRecord.ID, ResourceRequests{
+			Cpu:     namespaceRecord.CpuLimit,
+			Mem:     namespaceRecord.MemLimit,
+			Storage: namespaceRecord.StorageLimit,
+		}, ResourceRequests{
+			Cpu:     namespaceRecord.CpuUsage,
+			Mem:     namespaceRecord.MemUsage,
+			Storage: namespaceRecord.StorageUsage,
+		}, requests); err != nil {
+			log.Warn("chart does not fit the namespace", logger.Fields{"error": err})
+			return rsp, err
 		}
 
 		appDeployment.Namespace = &common_proto.Namespace{
@@ -76,72 +107,57 @@ func (p *AppMgrHandler) CreateApp(ctx context.Context, req *appmgr.CreateAppRequ
 		appDeployment.Namespace = req.App.GetNamespace()
 		if appDeployment.Namespace == nil || appDeployment.Namespace.NsCpuLimit == 0 ||
 			appDeployment.Namespace.NsMemLimit == 0 || appDeployment.Namespace.NsStorageLimit == 0 {
-			log.Printf("invalid input: empty namespace properties not accepted \n")
+			log.Warn("invalid input: empty namespace properties not accepted")
 			return rsp, ankr_default.ErrNsEmpty
 		}
 		if len(appDeployment.Namespace.ClusterId) > 0 {
 			clusterConnection, err := p.db.GetClusterConnection(appDeployment.Namespace.ClusterId)
-			if err != nil || clusterConnection.Status != common_proto.DCStatus_AVAILABLE {
-				log.Println("cluster connection not available, app can not be created")
-				return rsp, errors.New("cluster connection not available, app can not be created")
+			if err != nil || clusterConnection.Status != common_proto.DCStatus_AVAILABLE || clusterConnection.Draining {
+				log.Warn("cluster connection not available or draining, app can not be created",
+					logger.Fields{"cluster_id": appDeployment.Namespace.ClusterId})
+				return rsp, clusterUnavailable(appDeployment.Namespace.ClusterId, "app can not be created")
 			}
+		} else {
+			clusterId, err := p.placeCluster()
+			if err != nil {
+				log.Warn("cannot place namespace", logger.Fields{"error": err})
+				return rsp, err
+			}
+			appDeployment.Namespace.ClusterId = clusterId
 		}
 		appDeployment.Namespace.NsId = "ns-" + uuid.New().String()
+		if err := checkResourceFit(appDeployment.Namespace.NsId, ResourceRequests{
+			Cpu:     appDeployment.Namespace.NsCpuLimit,
+			Mem:     appDeployment.Namespace.NsMemLimit,
+			Storage: appDeployment.Namespace.NsStorageLimit,
+		}, ResourceRequests{}, requests); err != nil {
+			log.Warn("chart does not fit the namespace", logger.Fields{"error": err})
+			return rsp, err
+		}
 		if err := p.db.CreateNamespace(appDeployment.Namespace, teamId, creator); err != nil {
-			log.Println(err.Error())
+			log.Error("cannot create namespace", logger.Fields{"namespace_id": appDeployment.Namespace.NsId, "error": err})
 			return rsp, err
 		}
 	}
 
-	if req.App.ChartDetail == nil {
-		log.Printf("invalid input: null chart detail provided, %+v \n", req.App)
-		return rsp, ankr_default.ErrChartDetailEmpty
-	}
-	appDeployment.ChartDetail = req.App.ChartDetail
-	appChart, err := http.Get(getChartURL(chartmuseumURL, teamId,
-		req.App.ChartDetail.ChartRepo) + "/" + req.App.ChartDetail.ChartName + "-" + req.App.ChartDetail.ChartVer + ".tgz")
-	if err != nil {
-		log.Printf("cannot get app chart %s from chartmuseum\nerror: %s\n", req.App.ChartDetail.ChartName, err.Error())
-		return rsp, ankr_default.ErrChartMuseumGet
-	}
-	if appChart.StatusCode != 200 {
-		log.Printf("invalid input: app chart not exist \n")
-		return rsp, ankr_default.ErrChartNotExist
-	}
-
-	defer appChart.Body.Close()
-
-	loadedChart, err := chartutil.LoadArchive(appChart.Body)
-	if err != nil {
-		log.Printf("cannot load chart from the http get response from chartmuseum , %s \nerror: %s",
-			req.App.ChartDetail.ChartName, err.Error())
-		return rsp, ankr_default.ErrChartMuseumGet
-	}
-
 	appDeployment.ChartDetail.ChartAppVer = loadedChart.Metadata.AppVersion
 	appDeployment.ChartDetail.ChartIconUrl = loadedChart.Metadata.Icon
 	appDeployment.ChartDetail.ChartDescription = loadedChart.Metadata.Description
 
 	appDeployment.TeamId = teamId
-	if req.App.CustomValues != nil {
-		for _, customValue := range req.App.CustomValues {
-			appDeployment.CustomValues = append(appDeployment.CustomValues, &common_proto.CustomValue{Key: "ankrCustomValues." + customValue.Key, Value: customValue.Value})
-		}
-	}
+	appDeployment.CustomValues = prefixCustomValues(req.App.CustomValues)
 
 	event := common_proto.DCStream{
 		OpType:    common_proto.DCOperation_APP_CREATE,
 		OpPayload: &common_proto.DCStream_AppDeployment{AppDeployment: appDeployment},
 	}
 
-	if err := p.deployApp.Publish(&event); err != nil {
-		log.Println(ankr_default.ErrPublish)
+	if err := p.publish(ctx, &event); err != nil {
 		return rsp, ankr_default.ErrPublish
 	}
-	log.Println("app manager service send CreateApp MQ message to dc manager service (api)")
 
 	if err := p.db.CreateApp(appDeployment, teamId, creator); err != nil {
-		log.Println(err.Error())
+		log.Error("cannot create app", logger.Fields{"error": err})
 		return rsp, err
 	}
 
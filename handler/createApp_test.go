@@ -0,0 +1,36 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/Ankr-network/dccn-appmgr/logger"
+	appmgr "github.com/Ankr-network/dccn-common/protos/appmgr/v1/grpc"
+	common_proto "github.com/Ankr-network/dccn-common/protos/common"
+)
+
+func TestCreateAppLogsNoRequest(t *testing.T) {
+	out := &bytes.Buffer{}
+	p := &AppMgrHandler{logger: logger.New(out, logger.DebugLevel)}
+	ctx := logger.WithCorrelationID(context.Background(), "correlation-1")
+
+	// refused for the missing chart detail, after the custom values are known
+	_, err := p.CreateApp(ctx, &appmgr.CreateAppRequest{App: &common_proto.App{
+		AppName:       "app",
+		NamespaceData: &common_proto.App_NsId{NsId: "ns-1"},
+		CustomValues:  []*common_proto.CustomValue{{Key: "password", Value: "hunter2"}},
+	}})
+	if err == nil {
+		t.Fatal("app without chart detail created")
+	}
+
+	logged := out.String()
+	if strings.Contains(logged, "hunter2") {
+		t.Errorf("custom values logged: %s", logged)
+	}
+	if !strings.Contains(logged, `"correlation_id":"correlation-1"`) || !strings.Contains(logged, `"level":"warn"`) {
+		t.Errorf("refusal not logged as a correlated warning: %s", logged)
+	}
+}
@@ -1,14 +1,16 @@
 package handler
 
 import (
+	"context"
+	"github.com/Ankr-network/dccn-appmgr/logger"
 	ankr_default "github.com/Ankr-network/dccn-common/protos"
 	appmgr "github.com/Ankr-network/dccn-common/protos/appmgr/v1/grpc"
 	common_proto "github.com/Ankr-network/dccn-common/protos/common"
 	common_util "github.com/Ankr-network/dccn-common/util"
 	"github.com/google/uuid"
-	"context"
-	"log"
-	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // CreateNamespace will create a namespace on cluster which is desinated by dcmgr
@@ -17,23 +19,31 @@ func (p *AppMgrHandler) CreateNamespace(ctx context.Context,
 
 	rsp := &appmgr.CreateNamespaceResponse{}
 	creator, teamId := common_util.GetUserIDAndTeamID(ctx)
+	log := p.logger.WithContext(ctx)
 	if len(teamId) == 0 {
-		return rsp, errors.New("user id not found in context")
+		return rsp, status.Error(codes.Unauthenticated, "user id not found in context")
 	}
-	log.Printf(">>>>>>>>>Debug into CreateNamespace: %+v\nctx: %+v\n", req, ctx)
 
 	if req.Namespace == nil || req.Namespace.NsCpuLimit == 0 ||
 		req.Namespace.NsMemLimit == 0 || req.Namespace.NsStorageLimit == 0 {
-		log.Printf("invalid input: empty namespace properties not accepted \n")
+		log.Warn("invalid input: empty namespace properties not accepted")
 		return rsp, ankr_default.ErrNsEmpty
 	}
 
 	if len(req.Namespace.ClusterId) > 0 {
 		clusterConnection, err := p.db.GetClusterConnection(req.Namespace.ClusterId)
-		if err != nil || clusterConnection.Status != common_proto.DCStatus_AVAILABLE {
-			log.Println("cluster connection not available, namespace can not be created")
-			return rsp, errors.New("cluster connection not available, namespace can not be created")
+		if err != nil || clusterConnection.Status != common_proto.DCStatus_AVAILABLE || clusterConnection.Draining {
+			log.Warn("cluster connection not available or draining, namespace can not be created",
+				logger.Fields{"cluster_id": req.Namespace.ClusterId})
+			return rsp, clusterUnavailable(req.Namespace.ClusterId, "namespace can not be created")
 		}
+	} else {
+		clusterId, err := p.placeCluster()
+		if err != nil {
+			log.Warn("cannot place namespace", logger.Fields{"error": err})
+			return rsp, err
+		}
+		req.Namespace.ClusterId = clusterId
 	}
 
 	req.Namespace.NsId = "ns-" + uuid.New().String()
@@ -43,19 +53,16 @@ func (p *AppMgrHandler) CreateNamespace(ctx context.Context,
 		OpPayload: &common_proto.DCStream_Namespace{Namespace: req.Namespace},
 	}
 
-	if err := p.deployApp.Publish(&event); err != nil {
-		log.Println(ankr_default.ErrPublish)
+	if err := p.publish(ctx, &event); err != nil {
 		return rsp, ankr_default.ErrPublish
-	} else {
-		log.Println("app manager service send CreateNamespace MQ message to dc manager service (api)")
 	}
 
 	if err := p.db.CreateNamespace(req.Namespace, teamId, creator); err != nil {
-		log.Println(err.Error())
+		log.Error("cannot create namespace", logger.Fields{"namespace_id": req.Namespace.NsId, "error": err})
 		return rsp, err
 	}
 
 	rsp.NsId = req.Namespace.NsId
 
 	return rsp, nil
-}
\ No newline at end of file
+}
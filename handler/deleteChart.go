@@ -1,44 +1,84 @@
 package handler
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Ankr-network/dccn-appmgr/logger"
+	"github.com/Ankr-network/dccn-appmgr/protos/appmgrext"
 	ankr_default "github.com/Ankr-network/dccn-common/protos"
 	appmgr "github.com/Ankr-network/dccn-common/protos/appmgr/v1/grpc"
 	common_proto "github.com/Ankr-network/dccn-common/protos/common"
 	common_util "github.com/Ankr-network/dccn-common/util"
-	"context"
-	"log"
-	"net/http"
-	"errors"
 )
 
 // DeleteChart delete a specific chart version from the specific chartmuseum repo
 func (p *AppMgrHandler) DeleteChart(ctx context.Context,
 	req *appmgr.DeleteChartRequest) (*common_proto.Empty, error) {
+	return &common_proto.Empty{}, p.deleteChart(ctx, req.ChartRepo, req.ChartName, req.ChartVer, false)
+}
+
+// DeleteChartVersion is DeleteChart with the option to delete a chart version apps still use
+func (p *AppMgrHandler) DeleteChartVersion(ctx context.Context,
+	req *appmgrext.DeleteChartVersionRequest) (*common_proto.Empty, error) {
+	return &common_proto.Empty{}, p.deleteChart(ctx, req.ChartRepo, req.ChartName, req.ChartVer, req.Force)
+}
 
-	log.Printf(">>>>>>>>>Debug into DeleteChart...%+v\nctx: %+v\n", req, ctx)
+func (p *AppMgrHandler) deleteChart(ctx context.Context, repo, name, version string, force bool) error {
 
 	_, teamId := common_util.GetUserIDAndTeamID(ctx)
+	log := p.logger.WithContext(ctx).With(logger.Fields{"repo": repo, "chart": name + "-" + version})
 
-	query, err := http.Get(getChartURL(chartmuseumURL+"/api", teamId,
-		req.ChartRepo) + "/" + req.ChartName + "/" + req.ChartVer)
-	if query.StatusCode != 200 {
-		log.Printf("chart not exist, delete failed.\n")
-		return &common_proto.Empty{}, ankr_default.ErrChartNotExist
+	if _, err := p.getChartVersion(ctx, teamId, repo, name, version); err != nil {
+		log.Warn("chart not exist, delete failed", logger.Fields{"error": err})
+		return err
 	}
 
-	delReq, err := http.NewRequest("DELETE", getChartURL(chartmuseumURL+"/api",
-		teamId, req.ChartRepo)+"/"+req.ChartName+"/"+req.ChartVer, nil)
-	if err != nil {
-		log.Printf("cannot create delete chart request, %s \n", err.Error())
-		return &common_proto.Empty{}, ankr_default.ErrCreateRequest
+	if !force {
+		if err := p.checkChartNotInUse(teamId, repo, name, version); err != nil {
+			log.Warn("chart in use, delete refused", logger.Fields{"error": err})
+			return err
+		}
 	}
 
-	delRes, err := http.DefaultClient.Do(delReq)
+	if err := p.deleteChartVersion(ctx, teamId, repo, name, version); err != nil {
+		return err
+	}
+	p.charts.invalidate(teamId, repo, name, version)
+
+	return nil
+}
+
+// checkChartNotInUse refuses to delete a chart version which apps still run or update to.
+// Apps of other teams using a public chart are only counted.
+func (p *AppMgrHandler) checkChartNotInUse(teamId, repo, name, version string) error {
+	userTeamId := ""
+	if repo == "user" {
+		userTeamId = teamId
+	}
+	apps, err := p.db.GetLiveAppsByChart(userTeamId, repo, name, version)
 	if err != nil {
-		log.Printf("cannot delete chart file, %s \n", err.Error())
-		return &common_proto.Empty{}, errors.New(ankr_default.LogicError + "Cannot delete chart file" + err.Error())
+		return err
+	}
+	if len(apps) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(apps))
+	others := 0
+	for _, app := range apps {
+		if app.TeamID == teamId {
+			names = append(names, app.Name+" ("+app.ID+")")
+		} else {
+			others++
+		}
+	}
+	if others > 0 {
+		names = append(names, fmt.Sprintf("%d apps of other teams", others))
 	}
-	defer delRes.Body.Close()
 
-	return &common_proto.Empty{}, nil
+	return errors.New(ankr_default.LogicError + fmt.Sprintf("chart %s-%s is used by %s, delete with force to delete anyway",
+		name, version, strings.Join(names, ", ")))
 }
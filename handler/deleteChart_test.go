@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	db "github.com/Ankr-network/dccn-appmgr/db_service"
+	"github.com/Ankr-network/dccn-appmgr/protos/appmgrext"
+	"google.golang.org/grpc/metadata"
+)
+
+// chartUsersDB returns apps using a chart and keeps the team GetLiveAppsByChart was asked for
+type chartUsersDB struct {
+	db.DBService
+	apps   []db.AppRecord
+	teamId string
+}
+
+func (d *chartUsersDB) GetLiveAppsByChart(teamId, repo, name, version string) ([]db.AppRecord, error) {
+	d.teamId = teamId
+	return d.apps, nil
+}
+
+func TestCheckChartNotInUse(t *testing.T) {
+	own := db.AppRecord{ID: "app-1", TeamID: "team-1", Name: "cache"}
+	other := db.AppRecord{ID: "app-2", TeamID: "team-2", Name: "theirs"}
+
+	cases := []struct {
+		repo   string
+		apps   []db.AppRecord
+		teamId string
+		// used lists what the error names, nil if the chart is not in use
+		used []string
+	}{
+		{"stable", nil, "", nil},
+		{"stable", []db.AppRecord{own}, "", []string{"cache (app-1)"}},
+		{"stable", []db.AppRecord{own, other, other}, "", []string{"cache (app-1)", "2 apps of other teams"}},
+		{"user", []db.AppRecord{own}, "team-1", []string{"cache (app-1)"}},
+	}
+	for _, c := range cases {
+		fake := &chartUsersDB{apps: c.apps}
+		p := &AppMgrHandler{db: fake}
+		err := p.checkChartNotInUse("team-1", c.repo, "redis", "1.0.0")
+
+		if fake.teamId != c.teamId {
+			t.Errorf("%s repo: apps looked up in team %q, want %q", c.repo, fake.teamId, c.teamId)
+		}
+		if c.used == nil {
+			if err != nil {
+				t.Errorf("%s repo without apps: %v", c.repo, err)
+			}
+			continue
+		}
+		if err == nil {
+			t.Errorf("%s repo used by %v: chart deleted", c.repo, c.used)
+			continue
+		}
+		for _, used := range c.used {
+			if !strings.Contains(err.Error(), used) {
+				t.Errorf("%s repo: error %q does not name %s", c.repo, err.Error(), used)
+			}
+		}
+	}
+}
+
+func TestDeleteChartVersionForce(t *testing.T) {
+	for _, force := range []bool{false, true} {
+		museum := &fakeChartmuseum{index: map[string][]Chart{"redis": {{Name: "redis", Version: "1.0.0"}}}}
+		stop := serveChartmuseum(museum)
+
+		p := &AppMgrHandler{
+			db:     &chartUsersDB{apps: []db.AppRecord{{ID: "app-1", TeamID: "team-1"}}},
+			charts: newChartCache(time.Minute, 1<<20),
+		}
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("force", "true"))
+		_, err := p.DeleteChartVersion(ctx, &appmgrext.DeleteChartVersionRequest{
+			ChartRepo: "stable", ChartName: "redis", ChartVer: "1.0.0", Force: force,
+		})
+		stop()
+
+		// the force metadata of earlier versions is not honored, only the request field
+		if deleted := len(museum.deleted) > 0; deleted != force {
+			t.Errorf("force %v: chart in use deleted %v, error %v", force, deleted, err)
+		}
+		if (err == nil) != force {
+			t.Errorf("force %v: got error %v", force, err)
+		}
+	}
+}
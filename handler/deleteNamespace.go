@@ -3,13 +3,13 @@ package handler
 import (
 	"context"
 	"errors"
+	"github.com/Ankr-network/dccn-appmgr/logger"
 	"github.com/Ankr-network/dccn-common/protos"
 	"github.com/Ankr-network/dccn-common/protos/appmgr/v1/grpc"
 	"github.com/Ankr-network/dccn-common/protos/common"
 	commonutil "github.com/Ankr-network/dccn-common/util"
 	"github.com/golang/protobuf/ptypes/timestamp"
 	"gopkg.in/mgo.v2/bson"
-	"log"
 	"time"
 )
 
@@ -18,22 +18,23 @@ func (p *AppMgrHandler) DeleteNamespace(ctx context.Context,
 	req *appmgr.DeleteNamespaceRequest) (*common_proto.Empty, error) {
 
 	_, teamId := commonutil.GetUserIDAndTeamID(ctx)
-	log.Printf(">>>>>>>>>Debug into DeleteNamespace %+v", req)
+	log := p.logger.WithContext(ctx).With(logger.Fields{"namespace_id": req.NsId})
 
 	namespaceRecord, err := p.db.GetNamespace(req.NsId)
 	if err != nil {
-		log.Printf("GetNamespace error %v", err)
+		log.Error("cannot get namespace", logger.Fields{"error": err})
 		return &common_proto.Empty{}, err
 	}
 
 	if err := checkNsId(teamId, namespaceRecord.TeamID); err != nil {
-		log.Printf("checkNsId error %v", err)
+		log.Warn("namespace not owned by team", logger.Fields{"error": err})
 		return &common_proto.Empty{}, err
 	}
 
 	if namespaceRecord.Status == common_proto.NamespaceStatus_NS_CANCELED {
-		log.Printf("ns %s already canceled", namespaceRecord.ID)
-		return &common_proto.Empty{}, ankr_default.ErrCanceledTwice
+		log.Warn("namespace already canceled")
+		return &common_proto.Empty{}, statusBlocked("namespace/"+namespaceRecord.ID, namespaceRecord.Status.String(),
+			ankr_default.ErrCanceledTwice)
 	}
 
 	if namespaceRecord.Status == common_proto.NamespaceStatus_NS_FAILED || namespaceRecord.Status == common_proto.NamespaceStatus_NS_UNAVAILABLE {
@@ -42,7 +43,7 @@ func (p *AppMgrHandler) DeleteNamespace(ctx context.Context,
 			"hidden":           true,
 			"lastmodifieddate": &timestamp.Timestamp{Seconds: time.Now().Unix()},
 		}}); err != nil {
-			log.Printf("mark ns %s to canceled error: %v", req.NsId, err)
+			log.Error("cannot mark namespace canceled", logger.Fields{"error": err})
 			return &common_proto.Empty{}, err
 		} else {
 			if changeInfo, err := p.db.UpdateMany("app", bson.M{
@@ -51,10 +52,10 @@ func (p *AppMgrHandler) DeleteNamespace(ctx context.Context,
 			}, bson.M{
 				"$set": bson.M{"hidden": true},
 			}); err != nil {
-
+				log.Error("cannot hide apps of namespace", logger.Fields{"error": err})
 				return &common_proto.Empty{}, err
 			} else {
-				log.Printf("hide app of namespace %s, changeInfo:%+v", req.NsId, changeInfo)
+				log.Info("hid apps of canceled namespace", logger.Fields{"apps": changeInfo.Updated})
 				return &common_proto.Empty{}, nil
 			}
 		}
@@ -62,23 +63,23 @@ func (p *AppMgrHandler) DeleteNamespace(ctx context.Context,
 
 	apps, err := p.db.GetAllAppsByNamespaceId(req.NsId)
 	if err != nil {
-		log.Printf("GetAllAppsByNamespaceId error: %v", err)
+		log.Error("cannot get apps of namespace", logger.Fields{"error": err})
 		return &common_proto.Empty{}, err
 	}
 	for _, app := range apps {
 		if app.Status == common_proto.AppStatus_APP_UNAVAILABLE {
-			log.Printf("cancel unavailable app %s", app.ID)
+			log.Info("cancel unavailable app", logger.Fields{"app_id": app.ID})
 			if err := p.db.Update("app", app.ID, bson.M{
 				"status":           common_proto.AppStatus_APP_CANCELED,
 				"lastmodifieddate": &timestamp.Timestamp{Seconds: time.Now().Unix()},
 			}); err != nil {
-				log.Printf("Update app %s status to canceled error: %v", app.ID, err)
+				log.Error("cannot mark app canceled", logger.Fields{"app_id": app.ID, "error": err})
 				return &common_proto.Empty{}, err
 			}
 		} else if app.Status != common_proto.AppStatus_APP_CANCELED &&
 			app.Status != common_proto.AppStatus_APP_CANCELING &&
 			app.Status != common_proto.AppStatus_APP_FAILED {
-			log.Printf("app %s is running, cannot delete namespace", app.ID)
+			log.Warn("app is running, cannot delete namespace", logger.Fields{"app_id": app.ID})
 			return &common_proto.Empty{}, errors.New(ankr_default.LogicError + "namespace still got running app, can not delete")
 		}
 	}
@@ -90,8 +91,7 @@ func (p *AppMgrHandler) DeleteNamespace(ctx context.Context,
 		OpPayload: &common_proto.DCStream_Namespace{Namespace: namespaceReport.Namespace},
 	}
 
-	if err := p.deployApp.Publish(&event); err != nil {
-		log.Printf("publish namespace cancel message error: %v", err)
+	if err := p.publish(ctx, &event); err != nil {
 		return &common_proto.Empty{}, errors.New(ankr_default.PublishError + err.Error())
 	}
 
@@ -99,7 +99,7 @@ func (p *AppMgrHandler) DeleteNamespace(ctx context.Context,
 		"status":           common_proto.NamespaceStatus_NS_CANCELING,
 		"lastmodifieddate": &timestamp.Timestamp{Seconds: time.Now().Unix()},
 	}}); err != nil {
-		log.Printf("Update namespace status to canceling error: %v", err)
+		log.Error("cannot mark namespace canceling", logger.Fields{"error": err})
 		return &common_proto.Empty{}, err
 	}
 
@@ -3,13 +3,11 @@ package handler
 import (
 	"bytes"
 	"context"
-	"errors"
-	"io/ioutil"
-	"log"
-	"net/http"
 
+	"github.com/Ankr-network/dccn-appmgr/logger"
 	ankr_default "github.com/Ankr-network/dccn-common/protos"
 	appmgr "github.com/Ankr-network/dccn-common/protos/appmgr/v1/grpc"
+	common_proto "github.com/Ankr-network/dccn-common/protos/common"
 	common_util "github.com/Ankr-network/dccn-common/util"
 	chartutil "k8s.io/helm/pkg/chartutil"
 )
@@ -18,40 +16,27 @@ import (
 func (p *AppMgrHandler) DownloadChart(ctx context.Context,
 	req *appmgr.DownloadChartRequest) (*appmgr.DownloadChartResponse, error) {
 
-	log.Printf(">>>>>>>>>Debug into DownloadChart...%+v\nctx: %+v\n", req, ctx)
-
 	_, teamId := common_util.GetUserIDAndTeamID(ctx)
+	log := p.logger.WithContext(ctx).With(logger.Fields{"repo": req.ChartRepo, "chart": req.ChartName + "-" + req.ChartVer})
 	rsp := &appmgr.DownloadChartResponse{}
 	if len(req.ChartName) == 0 || len(req.ChartRepo) == 0 || len(req.ChartVer) == 0 {
-		log.Printf("invalid input: null chart detail provided, %+v \n", req)
+		log.Warn("invalid input: null chart detail provided")
 		return rsp, ankr_default.ErrChartDetailEmpty
 	}
 
-	tarballReq, err := http.NewRequest("GET", getChartURL(chartmuseumURL,
-		teamId, req.ChartRepo)+"/"+req.ChartName+"-"+req.ChartVer+".tgz", nil)
-	if err != nil {
-		log.Printf("cannot create download tarball request, %s \n", err.Error())
-		return rsp, ankr_default.ErrCreateRequest
-	}
-
-	tarballRes, err := http.DefaultClient.Do(tarballReq)
-	if err != nil {
-		log.Printf("cannot download chart tarball, %s \n", err.Error())
-		return rsp, errors.New(ankr_default.DialError + "Cannot download chart tarball" + err.Error())
-	}
-	defer tarballRes.Body.Close()
-
-	chartFile, err := ioutil.ReadAll(tarballRes.Body)
+	chartFile, err := p.downloadChartArchive(ctx, teamId, &common_proto.ChartDetail{
+		ChartName: req.ChartName,
+		ChartRepo: req.ChartRepo,
+		ChartVer:  req.ChartVer,
+	})
 	if err != nil {
-		log.Printf("cannot read chart tarball, %s \n", err.Error())
-		return rsp, ankr_default.ErrCannotReadDownload
+		return rsp, err
 	}
 
 	chartFileReader := bytes.NewReader(chartFile)
 
 	if _, err = chartutil.LoadArchive(chartFileReader); err != nil {
-		log.Printf("cannot load chart from the http get response from chartmuseum , %s \nerror: %s",
-			req.ChartName, err.Error())
+		log.Error("cannot load chart from chartmuseum", logger.Fields{"error": err})
 		return rsp, ankr_default.ErrChartMuseumGet
 	}
 
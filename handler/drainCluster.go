@@ -0,0 +1,217 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	db "github.com/Ankr-network/dccn-appmgr/db_service"
+	"github.com/Ankr-network/dccn-appmgr/logger"
+	"github.com/Ankr-network/dccn-appmgr/migration"
+	"github.com/Ankr-network/dccn-appmgr/protos/appmgrext"
+	ankr_default "github.com/Ankr-network/dccn-common/protos"
+	common_proto "github.com/Ankr-network/dccn-common/protos/common"
+	"github.com/google/uuid"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// DrainCluster marks a cluster draining so nothing new is placed on it, and recreates its running namespaces
+// on another cluster. The subscriber recreates the running apps of a namespace once its replacement reports
+// RUNNING, and cancels each original once its replacement does. Calling it again only moves what was not
+// moved yet, the apps of a replacement already running are recreated right away.
+func (p *AppMgrHandler) DrainCluster(ctx context.Context,
+	req *appmgrext.DrainClusterRequest) (*appmgrext.DrainClusterResponse, error) {
+	rsp := &appmgrext.DrainClusterResponse{}
+
+	if len(req.ClusterId) == 0 {
+		return rsp, invalidField("ClusterId", errors.New("invalid input: empty cluster id"))
+	}
+	log := p.logger.WithContext(ctx).With(logger.Fields{"cluster_id": req.ClusterId})
+	if _, err := p.db.GetClusterConnection(req.ClusterId); err != nil {
+		log.Error("cannot get cluster connection", logger.Fields{"error": err})
+		return rsp, err
+	}
+
+	if req.Resume {
+		if err := p.audit(ctx, "DrainCluster", "cluster/"+req.ClusterId, "resume"); err != nil {
+			log.Error("cannot audit drain", logger.Fields{"error": err})
+			return rsp, err
+		}
+		return rsp, p.db.SetClusterDraining(req.ClusterId, false)
+	}
+
+	target, err := p.drainTarget(req.ClusterId, req.TargetClusterId)
+	if err != nil {
+		log.Warn("no cluster to drain into", logger.Fields{"error": err})
+		return rsp, err
+	}
+	rsp.TargetClusterId = target
+
+	log = log.With(logger.Fields{"target_cluster_id": target})
+	if err := p.audit(ctx, "DrainCluster", "cluster/"+req.ClusterId, "into cluster "+target); err != nil {
+		log.Error("cannot audit drain", logger.Fields{"error": err})
+		return rsp, err
+	}
+
+	if err := p.db.SetClusterDraining(req.ClusterId, true); err != nil {
+		log.Error("cannot mark cluster draining", logger.Fields{"error": err})
+		return rsp, err
+	}
+
+	nss, err := p.db.GetRunningNamespacesByClusterId(req.ClusterId)
+	if err != nil {
+		log.Error("cannot get running namespaces of cluster", logger.Fields{"error": err})
+		return rsp, err
+	}
+
+	for _, ns := range nss {
+		replacement, created, err := p.replacementNamespace(ctx, ns, target)
+		if err != nil {
+			log.Error("cannot migrate namespace", logger.Fields{"namespace_id": ns.ID, "error": err})
+			return rsp, err
+		}
+		if created {
+			rsp.Namespaces = append(rsp.Namespaces, replacement.ID)
+		}
+		if replacement.Status != common_proto.NamespaceStatus_NS_RUNNING {
+			continue
+		}
+		appIds, err := migration.Apps(ctx, p.db, p.publish, ns.ID, migration.Namespace(replacement))
+		rsp.Apps = append(rsp.Apps, appIds...)
+		if err != nil {
+			log.Error("cannot migrate apps of namespace", logger.Fields{"namespace_id": ns.ID,
+				"replacement_id": replacement.ID, "error": err})
+			return rsp, err
+		}
+		log.Info("apps of namespace recreated", logger.Fields{"namespace_id": ns.ID, "replacement_id": replacement.ID,
+			"apps": appIds})
+	}
+
+	return rsp, nil
+}
+
+// drainTarget checks the cluster to drain into, or picks the available one with the fewest running namespaces
+func (p *AppMgrHandler) drainTarget(clusterId, targetId string) (string, error) {
+	if targetId == clusterId && len(targetId) > 0 {
+		return "", invalidField("TargetClusterId", errors.New("invalid input: a cluster can not drain into itself"))
+	}
+
+	connections, err := p.db.GetAvailableClusterConnections()
+	if err != nil {
+		return "", err
+	}
+
+	if len(targetId) > 0 {
+		for _, connection := range connections {
+			if connection.ID == targetId && !connection.Draining {
+				return targetId, nil
+			}
+		}
+		return "", clusterUnavailable(targetId, "namespaces can not be moved there")
+	}
+
+	target, err := p.leastLoadedCluster(connections, clusterId)
+	if err != nil {
+		return "", err
+	}
+	if len(target) == 0 {
+		return "", errors.New(ankr_default.LogicError + "no other available cluster to drain into")
+	}
+	return target, nil
+}
+
+// placeCluster picks the cluster of a namespace created without one. dcmgr does not know which clusters are
+// draining, so it only chooses while none is; otherwise the available cluster not draining with the fewest
+// running namespaces is picked here.
+func (p *AppMgrHandler) placeCluster() (string, error) {
+	connections, err := p.db.GetAvailableClusterConnections()
+	if err != nil {
+		return "", err
+	}
+	draining := false
+	for _, connection := range connections {
+		draining = draining || connection.Draining
+	}
+	if !draining {
+		return "", nil
+	}
+
+	target, err := p.leastLoadedCluster(connections, "")
+	if err != nil {
+		return "", err
+	}
+	if len(target) == 0 {
+		return "", clusterUnavailable("", "all available clusters are draining")
+	}
+	return target, nil
+}
+
+// leastLoadedCluster returns the cluster of connections not draining with the fewest running namespaces,
+// other than except, or an empty id if there is none
+func (p *AppMgrHandler) leastLoadedCluster(connections []db.ClusterConnectionRecord, except string) (string, error) {
+	target, fewest := "", -1
+	for _, connection := range connections {
+		if connection.ID == except || connection.Draining {
+			continue
+		}
+		count, err := p.db.CountRunningNamespacesByClusterID(connection.ID)
+		if err != nil {
+			return "", err
+		}
+		if fewest < 0 || count < fewest {
+			target, fewest = connection.ID, count
+		}
+	}
+	return target, nil
+}
+
+// replacementNamespace recreates a namespace with the same limits on the target cluster and links the original
+// to it, or returns the replacement an earlier drain created unless it failed. The replacement is recorded and
+// linked before it is published, so the feedback of dcmgr always finds it.
+func (p *AppMgrHandler) replacementNamespace(ctx context.Context, ns db.NamespaceRecord,
+	target string) (db.NamespaceRecord, bool, error) {
+	log := p.logger.WithContext(ctx).With(logger.Fields{"namespace_id": ns.ID})
+	if len(ns.MigratedTo) > 0 {
+		replacement, err := p.db.GetNamespace(ns.MigratedTo)
+		if err != nil || replacement.Status != common_proto.NamespaceStatus_NS_FAILED {
+			return replacement, false, err
+		}
+		log.Info("replacement of namespace failed, recreating it", logger.Fields{"replacement_id": replacement.ID})
+		migration.UnlinkNamespace(p.db, ns.ID, replacement.ID)
+	}
+
+	namespace := &common_proto.Namespace{
+		NsId:           "ns-" + uuid.New().String(),
+		NsName:         ns.Name,
+		ClusterId:      target,
+		NsCpuLimit:     ns.CpuLimit,
+		NsMemLimit:     ns.MemLimit,
+		NsStorageLimit: ns.StorageLimit,
+	}
+
+	if err := p.db.CreateNamespace(namespace, ns.TeamID, ns.Creator); err != nil {
+		return db.NamespaceRecord{}, false, err
+	}
+	if err := p.db.Update("namespace", namespace.NsId, bson.M{"$set": bson.M{
+		"clusterid":    target,
+		"migratedfrom": ns.ID,
+	}}); err != nil {
+		return db.NamespaceRecord{}, false, err
+	}
+	if err := p.db.Update("namespace", ns.ID, bson.M{"$set": bson.M{"migratedto": namespace.NsId}}); err != nil {
+		return db.NamespaceRecord{}, false, err
+	}
+
+	event := common_proto.DCStream{
+		OpType:    common_proto.DCOperation_NS_CREATE,
+		OpPayload: &common_proto.DCStream_Namespace{Namespace: namespace},
+	}
+	if err := p.publish(ctx, &event); err != nil {
+		migration.UnlinkNamespace(p.db, ns.ID, namespace.NsId)
+		return db.NamespaceRecord{}, false, ankr_default.ErrPublish
+	}
+	log.Info("namespace recreated", logger.Fields{"cluster_id": ns.ClusterID, "replacement_id": namespace.NsId,
+		"target_cluster_id": target})
+
+	replacement, err := p.db.GetNamespace(namespace.NsId)
+	return replacement, true, err
+}
@@ -0,0 +1,190 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"testing"
+
+	db "github.com/Ankr-network/dccn-appmgr/db_service"
+	"github.com/Ankr-network/dccn-appmgr/logger"
+	"github.com/Ankr-network/dccn-appmgr/protos/appmgrext"
+	common_proto "github.com/Ankr-network/dccn-common/protos/common"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// drainDB has the available clusters and how many namespaces run on each, and keeps the writes of a drain in
+// the order they are made along with the events published
+type drainDB struct {
+	db.DBService
+	connections []db.ClusterConnectionRecord
+	namespaces  map[string]int
+	records     map[string]db.NamespaceRecord
+	apps        []db.AppRecord
+	ops         []string
+}
+
+func (d *drainDB) GetAvailableClusterConnections() ([]db.ClusterConnectionRecord, error) {
+	return d.connections, nil
+}
+
+func (d *drainDB) CountRunningNamespacesByClusterID(clusterID string) (int, error) {
+	return d.namespaces[clusterID], nil
+}
+
+func (d *drainDB) GetClusterConnection(clusterID string) (db.ClusterConnectionRecord, error) {
+	return db.ClusterConnectionRecord{ID: clusterID}, nil
+}
+
+func (d *drainDB) CreateAudit(audit db.AuditRecord) error {
+	return nil
+}
+
+func (d *drainDB) SetClusterDraining(clusterID string, draining bool) error {
+	d.ops = append(d.ops, fmt.Sprint("draining ", clusterID, " ", draining))
+	return nil
+}
+
+func (d *drainDB) GetRunningNamespacesByClusterId(clusterId string) ([]db.NamespaceRecord, error) {
+	var nss []db.NamespaceRecord
+	for _, ns := range d.records {
+		if ns.ClusterID == clusterId {
+			nss = append(nss, ns)
+		}
+	}
+	return nss, nil
+}
+
+func (d *drainDB) GetNamespace(id string) (db.NamespaceRecord, error) {
+	return d.records[id], nil
+}
+
+func (d *drainDB) CreateNamespace(namespace *common_proto.Namespace, teamId string, creator string) error {
+	d.ops = append(d.ops, "create namespace")
+	d.records[namespace.NsId] = db.NamespaceRecord{ID: namespace.NsId, Status: common_proto.NamespaceStatus_NS_DISPATCHING}
+	return nil
+}
+
+func (d *drainDB) GetRunningAppsByNamespaceId(namespaceId string) ([]db.AppRecord, error) {
+	return d.apps, nil
+}
+
+func (d *drainDB) CreateApp(appDeployment *common_proto.AppDeployment, teamId string, creator string) error {
+	d.ops = append(d.ops, "create app")
+	return nil
+}
+
+func (d *drainDB) Update(collection string, id string, update bson.M) error {
+	if _, ok := update["$unset"]; ok {
+		d.ops = append(d.ops, "unlink "+collection)
+	} else if id == "ns-1" || id == "app-1" {
+		d.ops = append(d.ops, "link "+collection)
+	}
+	return nil
+}
+
+func (d *drainDB) SetStreamCorrelation(stream *common_proto.DCStream, correlation db.CorrelationRecord) error {
+	return nil
+}
+
+func (d *drainDB) Publish(m interface{}) error {
+	d.ops = append(d.ops, "publish "+m.(*common_proto.DCStream).OpType.String())
+	return nil
+}
+
+func TestDrainCluster(t *testing.T) {
+	createNs, createApp := common_proto.DCOperation_NS_CREATE.String(), common_proto.DCOperation_APP_CREATE.String()
+	cases := []struct {
+		name        string
+		replacement *db.NamespaceRecord
+		ops         []string
+	}{
+		// the apps wait for the subscriber to recreate them once the new namespace runs
+		{"first drain", nil, []string{"draining dc-1 true", "create namespace", "link namespace", "publish " + createNs}},
+		{"replacement dispatching", &db.NamespaceRecord{ID: "ns-2", Status: common_proto.NamespaceStatus_NS_DISPATCHING},
+			[]string{"draining dc-1 true"}},
+		{"replacement running", &db.NamespaceRecord{ID: "ns-2", Status: common_proto.NamespaceStatus_NS_RUNNING},
+			[]string{"draining dc-1 true", "create app", "link app", "publish " + createApp}},
+		// draining again after the replacement failed to launch moves the namespace anew
+		{"replacement failed", &db.NamespaceRecord{ID: "ns-2", Status: common_proto.NamespaceStatus_NS_FAILED},
+			[]string{"draining dc-1 true", "unlink namespace", "create namespace", "link namespace", "publish " + createNs}},
+	}
+	for _, c := range cases {
+		fake := &drainDB{
+			connections: []db.ClusterConnectionRecord{{ID: "dc-1"}, {ID: "dc-2"}},
+			records:     map[string]db.NamespaceRecord{"ns-1": {ID: "ns-1", ClusterID: "dc-1"}},
+			apps:        []db.AppRecord{{ID: "app-1", NamespaceID: "ns-1"}},
+		}
+		if c.replacement != nil {
+			fake.records["ns-1"] = db.NamespaceRecord{ID: "ns-1", ClusterID: "dc-1", MigratedTo: c.replacement.ID}
+			fake.records[c.replacement.ID] = *c.replacement
+		}
+		p := &AppMgrHandler{db: fake, deployApp: fake, logger: logger.New(ioutil.Discard, logger.ErrorLevel)}
+		rsp, err := p.DrainCluster(context.Background(), &appmgrext.DrainClusterRequest{ClusterId: "dc-1"})
+		if err != nil || rsp.TargetClusterId != "dc-2" {
+			t.Errorf("%s: drained into %q, %v", c.name, rsp.TargetClusterId, err)
+			continue
+		}
+		if fmt.Sprint(fake.ops) != fmt.Sprint(c.ops) {
+			t.Errorf("%s: got %q, want %q", c.name, fake.ops, c.ops)
+		}
+	}
+}
+
+func TestPlaceCluster(t *testing.T) {
+	p := &AppMgrHandler{db: &drainDB{
+		connections: []db.ClusterConnectionRecord{{ID: "dc-1"}, {ID: "dc-2"}},
+		namespaces:  map[string]int{"dc-1": 1, "dc-2": 5},
+	}}
+	if cluster, err := p.placeCluster(); err != nil || cluster != "" {
+		t.Errorf("no cluster draining places on %q, %v, want dcmgr to choose", cluster, err)
+	}
+
+	p.db = &drainDB{
+		connections: []db.ClusterConnectionRecord{{ID: "dc-1", Draining: true}, {ID: "dc-2"}, {ID: "dc-3"}},
+		namespaces:  map[string]int{"dc-1": 0, "dc-2": 5, "dc-3": 3},
+	}
+	if cluster, err := p.placeCluster(); err != nil || cluster != "dc-3" {
+		t.Errorf("dc-1 draining places on %q, %v, want dc-3", cluster, err)
+	}
+
+	p.db = &drainDB{connections: []db.ClusterConnectionRecord{{ID: "dc-1", Draining: true}}}
+	if _, err := p.placeCluster(); status.Code(err) != codes.Unavailable {
+		t.Errorf("all clusters draining gives %v, want Unavailable", err)
+	}
+}
+
+func TestDrainTarget(t *testing.T) {
+	p := &AppMgrHandler{db: &drainDB{
+		connections: []db.ClusterConnectionRecord{
+			{ID: "dc-1", Status: common_proto.DCStatus_AVAILABLE},
+			{ID: "dc-2", Status: common_proto.DCStatus_AVAILABLE},
+			{ID: "dc-3", Status: common_proto.DCStatus_AVAILABLE, Draining: true},
+			{ID: "dc-4", Status: common_proto.DCStatus_AVAILABLE},
+		},
+		namespaces: map[string]int{"dc-1": 1, "dc-2": 5, "dc-3": 0, "dc-4": 3},
+	}}
+
+	if target, err := p.drainTarget("dc-1", ""); err != nil || target != "dc-4" {
+		t.Errorf("drain dc-1 into %q, %v, want dc-4", target, err)
+	}
+	if target, err := p.drainTarget("dc-4", ""); err != nil || target != "dc-1" {
+		t.Errorf("drain dc-4 into %q, %v, want dc-1", target, err)
+	}
+	if target, err := p.drainTarget("dc-1", "dc-2"); err != nil || target != "dc-2" {
+		t.Errorf("drain dc-1 into dc-2 gives %q, %v", target, err)
+	}
+	if _, err := p.drainTarget("dc-1", "dc-3"); status.Code(err) != codes.Unavailable {
+		t.Errorf("drain into draining cluster gives %v, want Unavailable", err)
+	}
+	if _, err := p.drainTarget("dc-1", "dc-1"); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("drain into itself gives %v, want InvalidArgument", err)
+	}
+
+	p.db = &drainDB{connections: []db.ClusterConnectionRecord{{ID: "dc-1"}}}
+	if _, err := p.drainTarget("dc-1", ""); err == nil {
+		t.Error("drain without another cluster accepted")
+	}
+}
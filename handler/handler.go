@@ -1,15 +1,33 @@
 package handler
 
 import (
+	"errors"
+	"github.com/Ankr-network/dccn-appmgr/config"
 	db "github.com/Ankr-network/dccn-appmgr/db_service"
+	"github.com/Ankr-network/dccn-appmgr/logger"
+	"github.com/Ankr-network/dccn-appmgr/metrics"
+	"github.com/Ankr-network/dccn-appmgr/tracing"
 	"github.com/Ankr-network/dccn-common/broker"
+	ankr_default "github.com/Ankr-network/dccn-common/protos"
 	common_proto "github.com/Ankr-network/dccn-common/protos/common"
-	"sort"
+	"go.opentelemetry.io/otel/plugin/othttp"
+	"k8s.io/helm/pkg/provenance"
+	"net/http"
+	"time"
 )
 
 type AppMgrHandler struct {
 	db        db.DBService
 	deployApp broker.Publisher
+	signatory *provenance.Signatory
+	charts    *chartCache
+	repos     []string
+	window    *maintenanceWindow
+	// defaultRole is the role of team members who have none set
+	defaultRole string
+	// operators are the user ids allowed to call the operator rpcs on records of all teams
+	operators map[string]bool
+	logger    *logger.Logger
 }
 
 type Token struct {
@@ -18,15 +36,52 @@ type Token struct {
 	Iss string
 }
 
-func New(db db.DBService, deployApp broker.Publisher) *AppMgrHandler {
-	return &AppMgrHandler{
+func New(db db.DBService, deployApp broker.Publisher, conf config.Config, appLogger *logger.Logger) (*AppMgrHandler, error) {
+	handler := &AppMgrHandler{
 		db:        db,
 		deployApp: deployApp,
+		charts:    newChartCache(time.Duration(conf.ChartCacheTTL)*time.Second, int64(conf.ChartCacheSize)<<20),
+		repos:     conf.ChartRepos,
+		logger:    appLogger,
 	}
+
+	if _, ok := roleRanks[conf.DefaultRole]; !ok {
+		return nil, errors.New(ankr_default.ArgumentError + "unknown default role " + conf.DefaultRole)
+	}
+	handler.defaultRole = conf.DefaultRole
+
+	handler.operators = make(map[string]bool, len(conf.Operators))
+	for _, userId := range conf.Operators {
+		handler.operators[userId] = true
+	}
+
+	if len(conf.UpgradeWindow) > 0 {
+		window, err := parseMaintenanceWindow(conf.UpgradeWindow)
+		if err != nil {
+			return nil, err
+		}
+		handler.window = window
+	}
+
+	if len(conf.ChartKeyring) > 0 {
+		signatory, err := provenance.NewFromKeyring(conf.ChartKeyring, "")
+		if err != nil {
+			return nil, err
+		}
+		handler.signatory = signatory
+	}
+
+	return handler, nil
 }
 
 var chartmuseumURL string
 
+// chartmuseumClient makes all requests to chartmuseum, observing their latency and tracing each as a span of the
+// call of its request context
+var chartmuseumClient = &http.Client{Transport: metrics.Transport(othttp.NewTransport(http.DefaultTransport,
+	othttp.WithTracer(tracing.Tracer()),
+	othttp.WithSpanNameFormatter(func(_ string, r *http.Request) string { return "chartmuseum " + r.Method })))}
+
 type chartList []*common_proto.Chart
 
 // Maintainer is a struct representing a maintainer inside a chart
@@ -59,6 +114,5 @@ func (c chartList) Swap(i, j int) {
 }
 
 func (c chartList) Less(i, j int) bool {
-	data := []string{c[i].ChartName, c[j].ChartName}
-	return sort.StringsAreSorted(data)
+	return c[i].ChartName < c[j].ChartName
 }
@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// maintenanceWindow is a daily UTC time range, it wraps around midnight when end is before start
+type maintenanceWindow struct {
+	start time.Duration
+	end   time.Duration
+}
+
+// parseMaintenanceWindow parses "HH:MM-HH:MM"
+func parseMaintenanceWindow(window string) (*maintenanceWindow, error) {
+	bounds := strings.Split(window, "-")
+	if len(bounds) != 2 {
+		return nil, errors.New("maintenance window must be HH:MM-HH:MM, got " + window)
+	}
+
+	start, err := time.Parse("15:04", strings.TrimSpace(bounds[0]))
+	if err != nil {
+		return nil, err
+	}
+	end, err := time.Parse("15:04", strings.TrimSpace(bounds[1]))
+	if err != nil {
+		return nil, err
+	}
+
+	return &maintenanceWindow{
+		start: time.Duration(start.Hour())*time.Hour + time.Duration(start.Minute())*time.Minute,
+		end:   time.Duration(end.Hour())*time.Hour + time.Duration(end.Minute())*time.Minute,
+	}, nil
+}
+
+// contains tells whether t falls in the window, a nil window is always open
+func (w *maintenanceWindow) contains(t time.Time) bool {
+	if w == nil {
+		return true
+	}
+
+	t = t.UTC()
+	now := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+	if w.start <= w.end {
+		return now >= w.start && now < w.end
+	}
+	return now >= w.start || now < w.end
+}
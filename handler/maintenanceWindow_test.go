@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMaintenanceWindow(t *testing.T) {
+	cases := []struct {
+		window string
+		at     string
+		open   bool
+	}{
+		{"02:00-04:00", "03:30", true},
+		{"02:00-04:00", "04:00", false},
+		{"02:00-04:00", "01:59", false},
+		{"23:00-01:00", "23:30", true},
+		{"23:00-01:00", "00:30", true},
+		{"23:00-01:00", "12:00", false},
+	}
+
+	for _, c := range cases {
+		w, err := parseMaintenanceWindow(c.window)
+		if err != nil {
+			t.Fatal(err)
+		}
+		at, _ := time.Parse("15:04", c.at)
+		if open := w.contains(at); open != c.open {
+			t.Errorf("window %s at %s open %v, want %v", c.window, c.at, open, c.open)
+		}
+	}
+
+	if _, err := parseMaintenanceWindow("02:00"); err == nil {
+		t.Error("window without end parsed")
+	}
+
+	var always *maintenanceWindow
+	if !always.contains(time.Now()) {
+		t.Error("no window should always be open")
+	}
+}
@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Ankr-network/dccn-appmgr/logger"
+	"github.com/Ankr-network/dccn-appmgr/protos/appmgrext"
+	common_proto "github.com/Ankr-network/dccn-common/protos/common"
+)
+
+// OperatorListApps lists the apps of all teams, for operators
+func (p *AppMgrHandler) OperatorListApps(ctx context.Context, req *appmgrext.OperatorListRequest) (*appmgrext.OperatorListAppsResponse, error) {
+	rsp := &appmgrext.OperatorListAppsResponse{}
+
+	statuses := make([]common_proto.AppStatus, 0, len(req.Status))
+	for _, name := range req.Status {
+		value, ok := common_proto.AppStatus_value[name]
+		if !ok {
+			return rsp, invalidField("Status", fmt.Errorf("invalid input: unknown app status %s", name))
+		}
+		statuses = append(statuses, common_proto.AppStatus(value))
+	}
+
+	log := p.logger.WithContext(ctx)
+	if err := p.audit(ctx, "OperatorListApps", "", fmt.Sprintf("cluster %q status %v", req.ClusterId, req.Status)); err != nil {
+		log.Error("cannot audit operator list", logger.Fields{"error": err})
+		return rsp, err
+	}
+
+	apps, err := p.db.GetAppsByClusterAndStatus(req.ClusterId, statuses)
+	if err != nil {
+		log.Error("cannot get apps", logger.Fields{"error": err})
+		return rsp, err
+	}
+
+	for _, app := range apps {
+		appMessage := p.convertToAppMessage(ctx, app)
+		rsp.AppReports = append(rsp.AppReports, &appMessage)
+	}
+
+	return rsp, nil
+}
+
+// OperatorListNamespaces lists the namespaces of all teams, for operators
+func (p *AppMgrHandler) OperatorListNamespaces(ctx context.Context,
+	req *appmgrext.OperatorListRequest) (*appmgrext.OperatorListNamespacesResponse, error) {
+	rsp := &appmgrext.OperatorListNamespacesResponse{}
+
+	statuses := make([]common_proto.NamespaceStatus, 0, len(req.Status))
+	for _, name := range req.Status {
+		value, ok := common_proto.NamespaceStatus_value[name]
+		if !ok {
+			return rsp, invalidField("Status", fmt.Errorf("invalid input: unknown namespace status %s", name))
+		}
+		statuses = append(statuses, common_proto.NamespaceStatus(value))
+	}
+
+	log := p.logger.WithContext(ctx)
+	if err := p.audit(ctx, "OperatorListNamespaces", "", fmt.Sprintf("cluster %q status %v", req.ClusterId, req.Status)); err != nil {
+		log.Error("cannot audit operator list", logger.Fields{"error": err})
+		return rsp, err
+	}
+
+	nss, err := p.db.GetNamespacesByClusterAndStatus(req.ClusterId, statuses)
+	if err != nil {
+		log.Error("cannot get namespaces", logger.Fields{"error": err})
+		return rsp, err
+	}
+
+	for _, ns := range nss {
+		namespaceReport := convertFromNamespaceRecord(ns)
+		rsp.NamespaceReports = append(rsp.NamespaceReports, &namespaceReport)
+	}
+
+	return rsp, nil
+}
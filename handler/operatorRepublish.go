@@ -0,0 +1,155 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	db "github.com/Ankr-network/dccn-appmgr/db_service"
+	"github.com/Ankr-network/dccn-appmgr/logger"
+	"github.com/Ankr-network/dccn-appmgr/protos/appmgrext"
+	ankr_default "github.com/Ankr-network/dccn-common/protos"
+	common_proto "github.com/Ankr-network/dccn-common/protos/common"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// OperatorRepublish publishes the last DCStream of a record of any team again, for data center
+// managers which lost it. The event is rebuilt from the status the record is left in, and a record
+// the event failed for is moved back to the status it waits for the feedback in, so the feedback is
+// taken as it would be the first time; if the publish fails the record gets its status back.
+func (p *AppMgrHandler) OperatorRepublish(ctx context.Context, req *appmgrext.OperatorRepublishRequest) (*common_proto.Empty, error) {
+	log := p.logger.WithContext(ctx).With(logger.Fields{"target": req.Kind + "/" + req.Id})
+
+	var event *common_proto.DCStream
+	var current, pending string
+	var currentStatus, pendingStatus interface{}
+	switch req.Kind {
+	case kindApp:
+		appRecord, err := p.db.GetApp(req.Id)
+		if err != nil {
+			log.Error("cannot get app", logger.Fields{"error": err})
+			return &common_proto.Empty{}, err
+		}
+		appMessage := p.convertToAppMessage(ctx, appRecord)
+		var status common_proto.AppStatus
+		if event, status = lastAppEvent(appRecord, appMessage.AppDeployment); event == nil {
+			return &common_proto.Empty{}, statusBlocked("app/"+req.Id, appRecord.Status.String(),
+				errors.New(ankr_default.LogicError+"app has no pending event to publish again"))
+		}
+		current, pending = appRecord.Status.String(), status.String()
+		currentStatus, pendingStatus = appRecord.Status, status
+	case kindNamespace:
+		namespaceRecord, err := p.db.GetNamespace(req.Id)
+		if err != nil {
+			log.Error("cannot get namespace", logger.Fields{"error": err})
+			return &common_proto.Empty{}, err
+		}
+		var status common_proto.NamespaceStatus
+		if event, status = lastNamespaceEvent(namespaceRecord); event == nil {
+			return &common_proto.Empty{}, statusBlocked("namespace/"+req.Id, namespaceRecord.Status.String(),
+				errors.New(ankr_default.LogicError+"namespace has no pending event to publish again"))
+		}
+		current, pending = namespaceRecord.Status.String(), status.String()
+		currentStatus, pendingStatus = namespaceRecord.Status, status
+	default:
+		return &common_proto.Empty{}, invalidField("Kind", errors.New("invalid input: kind must be app or namespace"))
+	}
+
+	detail := event.OpType.String()
+	if pending != current {
+		detail += ", status " + current + " to " + pending
+	}
+	if err := p.audit(ctx, "OperatorRepublish", req.Kind+"/"+req.Id, detail); err != nil {
+		log.Error("cannot audit republish", logger.Fields{"error": err})
+		return &common_proto.Empty{}, err
+	}
+
+	if pending != current {
+		if err := p.setRecordStatus(req.Kind, req.Id, pendingStatus); err != nil {
+			log.Error("cannot set pending status", logger.Fields{"status": pending, "error": err})
+			return &common_proto.Empty{}, err
+		}
+	}
+
+	if err := p.publish(ctx, event); err != nil {
+		if pending != current {
+			// no feedback will come for an event which did not go out
+			if err := p.setRecordStatus(req.Kind, req.Id, currentStatus); err != nil {
+				log.Error("cannot restore status", logger.Fields{"status": current, "error": err})
+			}
+		}
+		return &common_proto.Empty{}, ankr_default.ErrPublish
+	}
+
+	return &common_proto.Empty{}, nil
+}
+
+// setRecordStatus sets the status of an app or namespace record
+func (p *AppMgrHandler) setRecordStatus(kind, id string, status interface{}) error {
+	return p.db.Update(kind, id, bson.M{"$set": bson.M{"status": status,
+		"lastmodifieddate": &timestamp.Timestamp{Seconds: time.Now().Unix()}}})
+}
+
+// lastAppEvent rebuilds the event which left the app in its status, and the status the app waits for its
+// feedback in, nil for statuses no event leads to. Canceled apps are done with, their cancel is not published again.
+func lastAppEvent(app db.AppRecord, appDeployment *common_proto.AppDeployment) (*common_proto.DCStream, common_proto.AppStatus) {
+	var opType common_proto.DCOperation
+	pending := app.Status
+	switch app.Status {
+	case common_proto.AppStatus_APP_DISPATCHING, common_proto.AppStatus_APP_LAUNCHING, common_proto.AppStatus_APP_FAILED:
+		opType = common_proto.DCOperation_APP_CREATE
+		if app.Status == common_proto.AppStatus_APP_FAILED {
+			pending = common_proto.AppStatus_APP_DISPATCHING
+		}
+	case common_proto.AppStatus_APP_UPDATING, common_proto.AppStatus_APP_UPDATE_FAILED:
+		opType = common_proto.DCOperation_APP_UPDATE
+		pending = common_proto.AppStatus_APP_UPDATING
+		updating := *appDeployment
+		updating.ChartDetail = &app.ChartUpdating
+		updating.CustomValues = app.CustomValuesUpdating
+		appDeployment = &updating
+	case common_proto.AppStatus_APP_CANCELING:
+		opType = common_proto.DCOperation_APP_CANCEL
+	default:
+		return nil, app.Status
+	}
+
+	return &common_proto.DCStream{
+		OpType:    opType,
+		OpPayload: &common_proto.DCStream_AppDeployment{AppDeployment: appDeployment},
+	}, pending
+}
+
+// lastNamespaceEvent rebuilds the event which left the namespace in its status, and the status the namespace
+// waits for its feedback in, nil for statuses no event leads to. Canceled namespaces are done with as canceled
+// apps are.
+func lastNamespaceEvent(namespace db.NamespaceRecord) (*common_proto.DCStream, common_proto.NamespaceStatus) {
+	namespaceReport := convertFromNamespaceRecord(namespace)
+
+	var opType common_proto.DCOperation
+	pending := namespace.Status
+	switch namespace.Status {
+	case common_proto.NamespaceStatus_NS_DISPATCHING, common_proto.NamespaceStatus_NS_LAUNCHING,
+		common_proto.NamespaceStatus_NS_FAILED:
+		opType = common_proto.DCOperation_NS_CREATE
+		if namespace.Status == common_proto.NamespaceStatus_NS_FAILED {
+			pending = common_proto.NamespaceStatus_NS_DISPATCHING
+		}
+	case common_proto.NamespaceStatus_NS_UPDATING, common_proto.NamespaceStatus_NS_UPDATE_FAILED:
+		opType = common_proto.DCOperation_NS_UPDATE
+		pending = common_proto.NamespaceStatus_NS_UPDATING
+		namespaceReport.Namespace.NsCpuLimit = namespace.CpuLimitUpdating
+		namespaceReport.Namespace.NsMemLimit = namespace.MemLimitUpdating
+		namespaceReport.Namespace.NsStorageLimit = namespace.StorageLimitUpdating
+	case common_proto.NamespaceStatus_NS_CANCELING:
+		opType = common_proto.DCOperation_NS_CANCEL
+	default:
+		return nil, namespace.Status
+	}
+
+	return &common_proto.DCStream{
+		OpType:    opType,
+		OpPayload: &common_proto.DCStream_Namespace{Namespace: namespaceReport.Namespace},
+	}, pending
+}
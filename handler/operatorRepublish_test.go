@@ -0,0 +1,206 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"testing"
+
+	db "github.com/Ankr-network/dccn-appmgr/db_service"
+	"github.com/Ankr-network/dccn-appmgr/logger"
+	"github.com/Ankr-network/dccn-appmgr/protos/appmgrext"
+	common_proto "github.com/Ankr-network/dccn-common/protos/common"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gopkg.in/mgo.v2/bson"
+)
+
+func TestLastAppEvent(t *testing.T) {
+	app := db.AppRecord{
+		ID:            "app-1",
+		ChartDetail:   common_proto.ChartDetail{ChartName: "redis", ChartVer: "1.0.0"},
+		ChartUpdating: common_proto.ChartDetail{ChartName: "redis", ChartVer: "1.1.0"},
+	}
+	deployment := &common_proto.AppDeployment{AppId: "app-1", ChartDetail: &app.ChartDetail}
+
+	cases := []struct {
+		status  common_proto.AppStatus
+		opType  common_proto.DCOperation
+		version string
+		pending common_proto.AppStatus
+	}{
+		{common_proto.AppStatus_APP_DISPATCHING, common_proto.DCOperation_APP_CREATE, "1.0.0", common_proto.AppStatus_APP_DISPATCHING},
+		{common_proto.AppStatus_APP_LAUNCHING, common_proto.DCOperation_APP_CREATE, "1.0.0", common_proto.AppStatus_APP_LAUNCHING},
+		{common_proto.AppStatus_APP_FAILED, common_proto.DCOperation_APP_CREATE, "1.0.0", common_proto.AppStatus_APP_DISPATCHING},
+		{common_proto.AppStatus_APP_UPDATING, common_proto.DCOperation_APP_UPDATE, "1.1.0", common_proto.AppStatus_APP_UPDATING},
+		{common_proto.AppStatus_APP_UPDATE_FAILED, common_proto.DCOperation_APP_UPDATE, "1.1.0", common_proto.AppStatus_APP_UPDATING},
+		{common_proto.AppStatus_APP_CANCELING, common_proto.DCOperation_APP_CANCEL, "1.0.0", common_proto.AppStatus_APP_CANCELING},
+	}
+	for _, c := range cases {
+		app.Status = c.status
+		event, pending := lastAppEvent(app, deployment)
+		if event == nil || event.OpType != c.opType {
+			t.Errorf("status %d gives %+v, want op %d", c.status, event, c.opType)
+			continue
+		}
+		payload := event.OpPayload.(*common_proto.DCStream_AppDeployment).AppDeployment
+		if payload.ChartDetail.ChartVer != c.version {
+			t.Errorf("status %d publishes version %s, want %s", c.status, payload.ChartDetail.ChartVer, c.version)
+		}
+		if pending != c.pending {
+			t.Errorf("status %d waits for the feedback in %d, want %d", c.status, pending, c.pending)
+		}
+	}
+	if deployment.ChartDetail.ChartVer != "1.0.0" {
+		t.Error("update event changed the running deployment")
+	}
+
+	for _, s := range []common_proto.AppStatus{common_proto.AppStatus_APP_RUNNING, common_proto.AppStatus_APP_UNAVAILABLE,
+		common_proto.AppStatus_APP_CANCELED} {
+		app.Status = s
+		if event, _ := lastAppEvent(app, deployment); event != nil {
+			t.Errorf("status %d gives %+v, want no event", s, event)
+		}
+	}
+}
+
+func TestLastNamespaceEvent(t *testing.T) {
+	ns := db.NamespaceRecord{ID: "ns-1", CpuLimit: 1000, CpuLimitUpdating: 2000, MemLimitUpdating: 4096, StorageLimitUpdating: 10}
+
+	ns.Status = common_proto.NamespaceStatus_NS_UPDATE_FAILED
+	event, pending := lastNamespaceEvent(ns)
+	if event == nil || event.OpType != common_proto.DCOperation_NS_UPDATE {
+		t.Fatalf("update failed namespace gives %+v", event)
+	}
+	if limit := event.OpPayload.(*common_proto.DCStream_Namespace).Namespace.NsCpuLimit; limit != 2000 {
+		t.Errorf("update event cpu limit %d, want 2000", limit)
+	}
+	if pending != common_proto.NamespaceStatus_NS_UPDATING {
+		t.Errorf("update failed namespace waits in %d, want NS_UPDATING", pending)
+	}
+
+	ns.Status = common_proto.NamespaceStatus_NS_FAILED
+	if event, pending := lastNamespaceEvent(ns); event == nil || event.OpType != common_proto.DCOperation_NS_CREATE ||
+		pending != common_proto.NamespaceStatus_NS_DISPATCHING {
+		t.Errorf("failed namespace gives %+v waiting in %d", event, pending)
+	}
+	ns.Status = common_proto.NamespaceStatus_NS_CANCELING
+	if event, pending := lastNamespaceEvent(ns); event == nil || pending != common_proto.NamespaceStatus_NS_CANCELING {
+		t.Errorf("canceling namespace gives %+v waiting in %d", event, pending)
+	}
+	ns.Status = common_proto.NamespaceStatus_NS_CANCELED
+	if event, _ := lastNamespaceEvent(ns); event != nil {
+		t.Errorf("canceled namespace gives %+v, want no event", event)
+	}
+	ns.Status = common_proto.NamespaceStatus_NS_LAUNCHING
+	if event, pending := lastNamespaceEvent(ns); event == nil || event.OpType != common_proto.DCOperation_NS_CREATE ||
+		pending != common_proto.NamespaceStatus_NS_LAUNCHING {
+		t.Errorf("launching namespace gives %+v waiting in %d", event, pending)
+	}
+	ns.Status = common_proto.NamespaceStatus_NS_RUNNING
+	if event, _ := lastNamespaceEvent(ns); event != nil {
+		t.Errorf("running namespace gives %+v, want no event", event)
+	}
+}
+
+// opsDB keeps the writes of a call in the order they are made, along with the events published
+type opsDB struct {
+	db.DBService
+	apps       map[string]db.AppRecord
+	ops        []string
+	publishErr error
+}
+
+func (d *opsDB) GetApp(id string) (db.AppRecord, error) {
+	return d.apps[id], nil
+}
+
+func (d *opsDB) GetNamespace(id string) (db.NamespaceRecord, error) {
+	return db.NamespaceRecord{ID: id}, nil
+}
+
+func (d *opsDB) CreateAudit(audit db.AuditRecord) error {
+	d.ops = append(d.ops, "audit "+audit.Detail)
+	return nil
+}
+
+func (d *opsDB) Update(collection string, id string, update bson.M) error {
+	d.ops = append(d.ops, fmt.Sprint("update ", collection, "/", id, " ", update["$set"].(bson.M)["status"]))
+	return nil
+}
+
+func (d *opsDB) SetStreamCorrelation(stream *common_proto.DCStream, correlation db.CorrelationRecord) error {
+	return nil
+}
+
+func (d *opsDB) Publish(m interface{}) error {
+	if d.publishErr != nil {
+		return d.publishErr
+	}
+	d.ops = append(d.ops, "publish "+m.(*common_proto.DCStream).OpType.String())
+	return nil
+}
+
+func TestOperatorRepublish(t *testing.T) {
+	apps := map[string]db.AppRecord{
+		"app-failed":      {ID: "app-failed", Status: common_proto.AppStatus_APP_FAILED},
+		"app-dispatching": {ID: "app-dispatching", Status: common_proto.AppStatus_APP_DISPATCHING},
+	}
+	create := common_proto.DCOperation_APP_CREATE.String()
+	failed, dispatching := common_proto.AppStatus_APP_FAILED.String(), common_proto.AppStatus_APP_DISPATCHING.String()
+	cases := []struct {
+		id  string
+		ops []string
+	}{
+		// the status is reset before the event goes out, so its feedback is not taken for a stale one
+		{"app-failed", []string{"audit " + create + ", status " + failed + " to " + dispatching,
+			"update app/app-failed " + dispatching, "publish " + create}},
+		{"app-dispatching", []string{"audit " + create, "publish " + create}},
+	}
+	for _, c := range cases {
+		fake := &opsDB{apps: apps}
+		p := &AppMgrHandler{db: fake, deployApp: fake, logger: logger.New(ioutil.Discard, logger.ErrorLevel)}
+		if _, err := p.OperatorRepublish(context.Background(), &appmgrext.OperatorRepublishRequest{Kind: kindApp, Id: c.id}); err != nil {
+			t.Errorf("%s: %v", c.id, err)
+			continue
+		}
+		if len(fake.ops) != len(c.ops) {
+			t.Errorf("%s: got %q, want %q", c.id, fake.ops, c.ops)
+			continue
+		}
+		for i := range c.ops {
+			if fake.ops[i] != c.ops[i] {
+				t.Errorf("%s: op %d is %q, want %q", c.id, i, fake.ops[i], c.ops[i])
+			}
+		}
+	}
+}
+
+func TestOperatorRepublishFailed(t *testing.T) {
+	fake := &opsDB{
+		apps: map[string]db.AppRecord{
+			"app-failed":   {ID: "app-failed", Status: common_proto.AppStatus_APP_FAILED},
+			"app-canceled": {ID: "app-canceled", Status: common_proto.AppStatus_APP_CANCELED},
+		},
+		publishErr: errors.New("broker down"),
+	}
+	p := &AppMgrHandler{db: fake, deployApp: fake, logger: logger.New(ioutil.Discard, logger.ErrorLevel)}
+
+	// the record gets its status back when the event does not go out
+	if _, err := p.OperatorRepublish(context.Background(), &appmgrext.OperatorRepublishRequest{Kind: kindApp, Id: "app-failed"}); err == nil {
+		t.Error("failed publish gives no error")
+	}
+	failed, dispatching := common_proto.AppStatus_APP_FAILED.String(), common_proto.AppStatus_APP_DISPATCHING.String()
+	want := []string{"audit " + common_proto.DCOperation_APP_CREATE.String() + ", status " + failed + " to " + dispatching,
+		"update app/app-failed " + dispatching, "update app/app-failed " + failed}
+	if fmt.Sprint(fake.ops) != fmt.Sprint(want) {
+		t.Errorf("got %q, want %q", fake.ops, want)
+	}
+
+	fake.ops = nil
+	_, err := p.OperatorRepublish(context.Background(), &appmgrext.OperatorRepublishRequest{Kind: kindApp, Id: "app-canceled"})
+	if status.Code(toStatus(err)) != codes.FailedPrecondition || len(fake.ops) != 0 {
+		t.Errorf("republishing a canceled app gives %v after %q, want FailedPrecondition", err, fake.ops)
+	}
+}
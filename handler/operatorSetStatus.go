@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Ankr-network/dccn-appmgr/logger"
+	"github.com/Ankr-network/dccn-appmgr/protos/appmgrext"
+	common_proto "github.com/Ankr-network/dccn-common/protos/common"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// record kinds operators act on, the names of their collections
+const (
+	kindApp       = "app"
+	kindNamespace = "namespace"
+)
+
+// OperatorSetStatus force sets the status of a record of any team stuck in a status no feedback will end
+func (p *AppMgrHandler) OperatorSetStatus(ctx context.Context, req *appmgrext.OperatorSetStatusRequest) (*common_proto.Empty, error) {
+
+	if len(req.Id) == 0 {
+		return &common_proto.Empty{}, invalidField("Id", errors.New("invalid input: empty id"))
+	}
+	if len(req.Reason) == 0 {
+		return &common_proto.Empty{}, invalidField("Reason", errors.New("invalid input: the reason is needed for the audit trail"))
+	}
+
+	log := p.logger.WithContext(ctx).With(logger.Fields{"target": req.Kind + "/" + req.Id})
+	update := bson.M{"lastmodifieddate": &timestamp.Timestamp{Seconds: time.Now().Unix()}}
+	switch req.Kind {
+	case kindApp:
+		value, ok := common_proto.AppStatus_value[req.Status]
+		if !ok {
+			return &common_proto.Empty{}, invalidField("Status", fmt.Errorf("invalid input: unknown app status %s", req.Status))
+		}
+		if _, err := p.db.GetApp(req.Id); err != nil {
+			log.Error("cannot get app", logger.Fields{"error": err})
+			return &common_proto.Empty{}, err
+		}
+		update["status"] = common_proto.AppStatus(value)
+		// an update ended by hand is not one the scheduler rolls back
+		update["autoupgrade.upgrading"] = false
+	case kindNamespace:
+		value, ok := common_proto.NamespaceStatus_value[req.Status]
+		if !ok {
+			return &common_proto.Empty{}, invalidField("Status", fmt.Errorf("invalid input: unknown namespace status %s", req.Status))
+		}
+		if _, err := p.db.GetNamespace(req.Id); err != nil {
+			log.Error("cannot get namespace", logger.Fields{"error": err})
+			return &common_proto.Empty{}, err
+		}
+		update["status"] = common_proto.NamespaceStatus(value)
+	default:
+		return &common_proto.Empty{}, invalidField("Kind", errors.New("invalid input: kind must be app or namespace"))
+	}
+
+	if err := p.audit(ctx, "OperatorSetStatus", req.Kind+"/"+req.Id, req.Status+": "+req.Reason); err != nil {
+		log.Error("cannot audit set status", logger.Fields{"error": err})
+		return &common_proto.Empty{}, err
+	}
+
+	if err := p.db.Update(req.Kind, req.Id, bson.M{"$set": update}); err != nil {
+		log.Error("cannot set status", logger.Fields{"status": req.Status, "error": err})
+		return &common_proto.Empty{}, err
+	}
+
+	return &common_proto.Empty{}, nil
+}
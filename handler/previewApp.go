@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/Ankr-network/dccn-appmgr/logger"
+	"github.com/Ankr-network/dccn-appmgr/protos/appmgrext"
+	ankr_default "github.com/Ankr-network/dccn-common/protos"
+	common_util "github.com/Ankr-network/dccn-common/util"
+)
+
+// PreviewApp renders the chart of an app locally with its custom values, without deploying it
+func (p *AppMgrHandler) PreviewApp(ctx context.Context, req *appmgrext.PreviewAppRequest) (*appmgrext.PreviewAppResponse, error) {
+
+	_, teamId := common_util.GetUserIDAndTeamID(ctx)
+	log := p.logger.WithContext(ctx)
+
+	rsp := &appmgrext.PreviewAppResponse{}
+	if req.ChartDetail == nil || len(req.ChartDetail.ChartName) == 0 ||
+		len(req.ChartDetail.ChartRepo) == 0 || len(req.ChartDetail.ChartVer) == 0 {
+		log.Warn("invalid input: null chart detail provided", logger.Fields{"request": logger.Redact(req)})
+		return rsp, ankr_default.ErrChartDetailEmpty
+	}
+	log = log.With(logger.Fields{"chart": req.ChartDetail.ChartName + "-" + req.ChartDetail.ChartVer})
+
+	loadedChart, err := p.getChartArchive(ctx, teamId, req.ChartDetail)
+	if err != nil {
+		return rsp, err
+	}
+
+	releaseName := req.AppName
+	if len(releaseName) == 0 {
+		releaseName = req.ChartDetail.ChartName
+	}
+	namespace := req.NsName
+	if len(namespace) == 0 {
+		namespace = "default"
+	}
+
+	manifests, values, err := renderChart(loadedChart, prefixCustomValues(req.CustomValues), releaseName, namespace)
+	if err != nil {
+		log.Warn("cannot render chart", logger.Fields{"error": err})
+		return rsp, ankr_default.ErrCannotLoadChart
+	}
+	for _, manifest := range manifests {
+		rsp.Manifests = append(rsp.Manifests, &appmgrext.AppManifest{
+			Source:  manifest.Source,
+			Kind:    manifest.Kind,
+			Name:    manifest.Name,
+			Hook:    manifest.Hook,
+			Content: manifest.Content,
+		})
+	}
+
+	if rsp.ValuesYaml, err = values.YAML(); err != nil {
+		log.Error("cannot marshal merged values of chart", logger.Fields{"error": err})
+		return rsp, ankr_default.ErrCannotLoadChart
+	}
+
+	requests, err := computeResourceRequests(manifests)
+	if err != nil {
+		log.Warn("cannot compute resource requests of chart", logger.Fields{"error": err})
+		return rsp, ankr_default.ErrCannotLoadChart
+	}
+	rsp.Requests = &appmgrext.ResourceRequests{Cpu: requests.Cpu, Mem: requests.Mem, Storage: requests.Storage}
+
+	return rsp, nil
+}
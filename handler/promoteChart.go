@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"errors"
+
+	"github.com/Ankr-network/dccn-appmgr/logger"
+	"github.com/Ankr-network/dccn-appmgr/protos/appmgrext"
+	ankr_default "github.com/Ankr-network/dccn-common/protos"
+	common_proto "github.com/Ankr-network/dccn-common/protos/common"
+	common_util "github.com/Ankr-network/dccn-common/util"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// PromoteChart copies a chart version to another repo unchanged, with its provenance file if there is one,
+// so it keeps its metadata and digest
+func (p *AppMgrHandler) PromoteChart(ctx context.Context, req *appmgrext.PromoteChartRequest) (*common_proto.Empty, error) {
+
+	_, teamId := common_util.GetUserIDAndTeamID(ctx)
+	log := p.logger.WithContext(ctx).With(logger.Fields{"repo": req.ChartRepo, "target_repo": req.TargetRepo,
+		"chart": req.ChartName + "-" + req.ChartVer})
+
+	if len(req.ChartName) == 0 || len(req.ChartVer) == 0 || len(req.ChartRepo) == 0 || len(req.TargetRepo) == 0 {
+		log.Warn("invalid input: empty chart properties not accepted")
+		return &common_proto.Empty{}, ankr_default.ErrEmptyChartProperties
+	}
+	if req.ChartRepo == req.TargetRepo {
+		log.Warn("invalid input: chart promoted to its own repo")
+		return &common_proto.Empty{}, invalidField("TargetRepo", errors.New("invalid input: target repo is the chart repo"))
+	}
+
+	if err := p.checkChartNotExist(ctx, teamId, req.TargetRepo, req.ChartName, req.ChartVer, ankr_default.ErrSaveChartAlreadyExist); err != nil {
+		log.Warn("chart promotion refused", logger.Fields{"error": err})
+		return &common_proto.Empty{}, err
+	}
+
+	source, err := p.getChartVersion(ctx, teamId, req.ChartRepo, req.ChartName, req.ChartVer)
+	if err != nil {
+		if isNotFound(err) {
+			return &common_proto.Empty{}, status.Error(codes.NotFound, ankr_default.ErrOriginalChartNotExist.Error())
+		}
+		return &common_proto.Empty{}, err
+	}
+
+	chartFile, err := p.downloadChartArchive(ctx, teamId, &common_proto.ChartDetail{
+		ChartName: req.ChartName,
+		ChartRepo: req.ChartRepo,
+		ChartVer:  req.ChartVer,
+	})
+	if err != nil {
+		return &common_proto.Empty{}, err
+	}
+
+	// only a missing provenance file means the chart is not signed
+	tarballName := req.ChartName + "-" + req.ChartVer + ".tgz"
+	provFile, err := p.getChartFile(ctx, teamId, req.ChartRepo, tarballName+".prov")
+	if err != nil && !isNotFound(err) {
+		log.Error("cannot get provenance of chart", logger.Fields{"error": err})
+		return &common_proto.Empty{}, err
+	}
+
+	if err := p.pushChartFile(ctx, teamId, req.TargetRepo, "charts", bytes.NewReader(chartFile)); err != nil {
+		return &common_proto.Empty{}, err
+	}
+	p.charts.invalidate(teamId, req.TargetRepo, req.ChartName, req.ChartVer)
+
+	if err := p.checkPromoted(ctx, teamId, req, source, provFile); err != nil {
+		// the target repo must not keep a chart without its provenance or with another digest
+		if err := p.deleteChartVersion(ctx, teamId, req.TargetRepo, req.ChartName, req.ChartVer); err != nil {
+			log.Error("cannot delete promoted chart", logger.Fields{"error": err})
+		}
+		p.charts.invalidate(teamId, req.TargetRepo, req.ChartName, req.ChartVer)
+		return &common_proto.Empty{}, err
+	}
+
+	return &common_proto.Empty{}, nil
+}
+
+// checkPromoted pushes the provenance file of the promoted chart if it has one, and checks the digest
+// of the chart in the target repo is the digest of the source
+func (p *AppMgrHandler) checkPromoted(ctx context.Context, teamId string, req *appmgrext.PromoteChartRequest,
+	source *Chart, provFile []byte) error {
+	if provFile != nil {
+		if err := p.pushChartFile(ctx, teamId, req.TargetRepo, "prov", bytes.NewReader(provFile)); err != nil {
+			return err
+		}
+	}
+
+	promoted, err := p.getChartVersion(ctx, teamId, req.TargetRepo, req.ChartName, req.ChartVer)
+	if err != nil {
+		return err
+	}
+	if promoted.Digest != source.Digest {
+		p.logger.WithContext(ctx).Warn("promoted chart digest differs from source", logger.Fields{
+			"chart": req.ChartName + "-" + req.ChartVer, "digest": promoted.Digest, "source_digest": source.Digest})
+		return errChartDigestMismatch
+	}
+	return nil
+}
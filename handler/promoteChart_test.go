@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/Ankr-network/dccn-appmgr/protos/appmgrext"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestPromoteChart(t *testing.T) {
+	dir, err := ioutil.TempDir("", "promote-chart-test-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	chartFile, err := ioutil.ReadFile(testChart(t, dir, "test", "1.0.0"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const provPath = "/public/stable/charts/test-1.0.0.tgz.prov"
+	cases := []struct {
+		name string
+		prov bool
+		// fail answers a request to the source or the target repo with a status code
+		sourceFail, targetFail map[string]int
+		tamper                 bool
+		code                   codes.Code
+		err                    error
+		// deleted tells the failed promote deleted the chart it pushed
+		promoted, provPushed, deleted bool
+	}{
+		{name: "without provenance", promoted: true},
+		{name: "with provenance", prov: true, promoted: true, provPushed: true},
+		{name: "provenance unreachable", sourceFail: map[string]int{"GET " + provPath: http.StatusInternalServerError},
+			code: codes.Internal},
+		{name: "provenance push failed", prov: true,
+			targetFail: map[string]int{"POST /api/public/incubator/prov": http.StatusInternalServerError}, code: codes.Internal,
+			deleted: true},
+		{name: "digest changed", tamper: true, err: errChartDigestMismatch, deleted: true},
+	}
+
+	for _, c := range cases {
+		source := &fakeChartmuseum{
+			index: map[string][]Chart{"test": {{Name: "test", Version: "1.0.0", Digest: sha256Digest(chartFile)}}},
+			files: map[string][]byte{"test-1.0.0.tgz": chartFile},
+			fail:  c.sourceFail,
+		}
+		if c.prov {
+			source.files["test-1.0.0.tgz.prov"] = []byte("provenance")
+		}
+		target := &fakeChartmuseum{repo: "incubator", fail: c.targetFail, tamper: c.tamper}
+		mux := http.NewServeMux()
+		mux.Handle("/api/public/stable/", source)
+		mux.Handle("/public/stable/", source)
+		mux.Handle("/api/public/incubator/", target)
+		mux.Handle("/public/incubator/", target)
+		stop := serveChartmuseum(mux)
+
+		p := &AppMgrHandler{charts: newChartCache(time.Minute, 1<<20)}
+		_, err := p.PromoteChart(context.Background(), &appmgrext.PromoteChartRequest{
+			ChartName: "test", ChartVer: "1.0.0", ChartRepo: "stable", TargetRepo: "incubator",
+		})
+		stop()
+
+		switch {
+		case c.err != nil && err != c.err:
+			t.Errorf("%s: got error %v, want %v", c.name, err, c.err)
+		case c.err == nil && status.Code(err) != c.code:
+			t.Errorf("%s: got error %v, want code %v", c.name, err, c.code)
+		}
+
+		_, promoted := target.files["test-1.0.0.tgz"]
+		if promoted != c.promoted {
+			t.Errorf("%s: chart in target repo %v, want %v", c.name, promoted, c.promoted)
+		}
+		_, provPushed := target.files["pushed.prov"]
+		if provPushed != c.provPushed {
+			t.Errorf("%s: provenance pushed %v, want %v", c.name, provPushed, c.provPushed)
+		}
+		if deleted := len(target.deleted) > 0; deleted != c.deleted {
+			t.Errorf("%s: deleted %v from the target repo, want deleted %v", c.name, target.deleted, c.deleted)
+		}
+	}
+}
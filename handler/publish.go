@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"context"
+
+	db "github.com/Ankr-network/dccn-appmgr/db_service"
+	"github.com/Ankr-network/dccn-appmgr/logger"
+	"github.com/Ankr-network/dccn-appmgr/tracing"
+	common_proto "github.com/Ankr-network/dccn-common/protos/common"
+	"go.opentelemetry.io/otel/api/kv"
+	"go.opentelemetry.io/otel/api/trace"
+)
+
+// publish sends a DCStream to dcmgr and keeps the correlation id and trace context of the call for the op type and
+// the app or namespace it names, so the feedback of dcmgr is logged under the same id and traced under the publish
+// span. DCStreams have no headers to carry them in. They are kept before the DCStream goes out, feedback can
+// arrive before Publish returns.
+func (p *AppMgrHandler) publish(ctx context.Context, event *common_proto.DCStream) error {
+	target := db.StreamTarget(event)
+	ctx, span := tracing.Start(ctx, "publish "+event.OpType.String(), trace.SpanKindProducer,
+		kv.String("messaging.system", "rabbitmq"), kv.String("appmgr.target", target))
+	log := p.logger.WithContext(ctx).With(logger.Fields{"op_type": event.OpType.String(), "target": target})
+
+	correlation := db.CorrelationRecord{CorrelationID: logger.CorrelationID(ctx), TraceContext: tracing.Inject(ctx)}
+	if len(correlation.CorrelationID) > 0 || len(correlation.TraceContext) > 0 {
+		if err := p.db.SetStreamCorrelation(event, correlation); err != nil {
+			log.Warn("cannot keep the correlation of DCStream", logger.Fields{"error": err})
+		}
+	}
+
+	if err := p.deployApp.Publish(event); err != nil {
+		log.Error("cannot publish DCStream", logger.Fields{"error": err})
+		tracing.End(span, err)
+		return err
+	}
+	log.Info("published DCStream")
+	span.End()
+	return nil
+}
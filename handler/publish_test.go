@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"testing"
+
+	db "github.com/Ankr-network/dccn-appmgr/db_service"
+	"github.com/Ankr-network/dccn-appmgr/logger"
+	common_proto "github.com/Ankr-network/dccn-common/protos/common"
+)
+
+// correlationDB keeps the correlations set and the events published, in order
+type correlationDB struct {
+	db.DBService
+	correlations []db.CorrelationRecord
+	ops          []string
+	err          error
+}
+
+func (d *correlationDB) SetStreamCorrelation(stream *common_proto.DCStream, correlation db.CorrelationRecord) error {
+	d.correlations = append(d.correlations, correlation)
+	d.ops = append(d.ops, "correlation "+db.StreamTarget(stream))
+	return nil
+}
+
+func (d *correlationDB) Publish(m interface{}) error {
+	d.ops = append(d.ops, "publish "+db.StreamTarget(m.(*common_proto.DCStream)))
+	return d.err
+}
+
+func TestPublishKeepsCorrelationFirst(t *testing.T) {
+	fake := &correlationDB{}
+	p := &AppMgrHandler{db: fake, deployApp: fake, logger: logger.New(ioutil.Discard, logger.ErrorLevel)}
+	ctx := logger.WithCorrelationID(context.Background(), "correlation-1")
+	event := &common_proto.DCStream{
+		OpType:    common_proto.DCOperation_APP_CREATE,
+		OpPayload: &common_proto.DCStream_AppDeployment{AppDeployment: &common_proto.AppDeployment{AppId: "app-1"}},
+	}
+
+	if err := p.publish(ctx, event); err != nil {
+		t.Fatal(err)
+	}
+	// feedback can arrive before Publish returns, so the correlation is kept first
+	if len(fake.ops) != 2 || fake.ops[0] != "correlation app/app-1" || fake.ops[1] != "publish app/app-1" {
+		t.Errorf("got %q, want the correlation kept before the publish", fake.ops)
+	}
+	if fake.correlations[0].CorrelationID != "correlation-1" {
+		t.Errorf("kept correlation id %q, want correlation-1", fake.correlations[0].CorrelationID)
+	}
+
+	fake.err = errors.New("broker down")
+	if err := p.publish(ctx, event); err != fake.err {
+		t.Errorf("publish failure gives %v, want %v", err, fake.err)
+	}
+
+	fake.ops = nil
+	if err := p.publish(context.Background(), event); err == nil || len(fake.ops) != 1 {
+		t.Errorf("call without correlation id gives %v, %q, want only the publish", err, fake.ops)
+	}
+}
@@ -1,39 +1,39 @@
 package handler
 
 import (
+	"context"
+	"github.com/Ankr-network/dccn-appmgr/logger"
 	ankr_default "github.com/Ankr-network/dccn-common/protos"
 	appmgr "github.com/Ankr-network/dccn-common/protos/appmgr/v1/grpc"
 	common_proto "github.com/Ankr-network/dccn-common/protos/common"
 	"gopkg.in/mgo.v2/bson"
-	"log"
-	"context"
 )
 
 func (p *AppMgrHandler) PurgeApp(ctx context.Context, req *appmgr.AppID) (*common_proto.Empty, error) {
-	log.Printf(">>>>>>>>>Debug into PurgeApp, ctx: %+v\n", ctx)
+	log := p.logger.WithContext(ctx).With(logger.Fields{"app_id": req.AppId})
 	appRecord, err := p.db.GetApp(req.AppId)
 
 	if err != nil {
-		log.Printf(" PurgeApp for app id %s not found \n", req.AppId)
+		log.Warn("app to purge not found", logger.Fields{"error": err})
 		return &common_proto.Empty{}, ankr_default.ErrAppNotExist
 	}
 
 	if appRecord.Hidden {
-		log.Printf(" app id %s already purged \n", req.AppId)
-		return &common_proto.Empty{}, ankr_default.ErrAlreadyPurged
+		log.Warn("app already purged")
+		return &common_proto.Empty{}, statusBlocked("app/"+req.AppId, "purged", ankr_default.ErrAlreadyPurged)
 	}
 
 	if appRecord.Status != common_proto.AppStatus_APP_CANCELED {
 
 		if _, err := p.CancelApp(ctx, req); err != nil {
-			log.Printf(err.Error())
+			log.Warn("cannot cancel app to purge", logger.Fields{"error": err})
 			return &common_proto.Empty{}, err
 		}
 	}
 
-	log.Printf(" PurgeApp  %s \n", req.AppId)
+	log.Info("purge app")
 	if err := p.db.Update("app", req.AppId, bson.M{"$set": bson.M{"hidden": true}}); err != nil {
-		log.Printf(err.Error())
+		log.Error("cannot purge app", logger.Fields{"error": err})
 		return &common_proto.Empty{}, err
 	}
 
@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	common_proto "github.com/Ankr-network/dccn-common/protos/common"
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/hooks"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	"k8s.io/helm/pkg/releaseutil"
+	"k8s.io/helm/pkg/renderutil"
+	"k8s.io/helm/pkg/strvals"
+)
+
+// AppManifest is a single kubernetes object rendered from a chart template
+type AppManifest struct {
+	Source  string
+	Kind    string
+	Name    string
+	Hook    string
+	Content string
+}
+
+// prefixCustomValues moves user supplied custom values under the ankrCustomValues table of values.yaml
+func prefixCustomValues(customValues []*common_proto.CustomValue) []*common_proto.CustomValue {
+	var prefixed []*common_proto.CustomValue
+	for _, customValue := range customValues {
+		prefixed = append(prefixed, &common_proto.CustomValue{Key: "ankrCustomValues." + customValue.Key, Value: customValue.Value})
+	}
+	return prefixed
+}
+
+// customValuesConfig converts already prefixed custom values into a values config, the same way as helm --set
+func customValuesConfig(customValues []*common_proto.CustomValue) (*chart.Config, error) {
+	values := map[string]interface{}{}
+	for _, customValue := range customValues {
+		if err := strvals.ParseInto(customValue.Key+"="+customValue.Value, values); err != nil {
+			return nil, err
+		}
+	}
+
+	raw, err := chartutil.Values(values).YAML()
+	if err != nil {
+		return nil, err
+	}
+
+	return &chart.Config{Raw: raw}, nil
+}
+
+// renderChart renders loadedChart locally with customValues merged over its values.yaml,
+// returns the rendered manifests ordered by template and the merged values
+func renderChart(loadedChart *chart.Chart, customValues []*common_proto.CustomValue,
+	releaseName, namespace string) ([]*AppManifest, chartutil.Values, error) {
+
+	config, err := customValuesConfig(customValues)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	values, err := chartutil.CoalesceValues(loadedChart, config)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	templates, err := renderutil.Render(loadedChart, config, renderutil.Options{
+		ReleaseOptions: chartutil.ReleaseOptions{
+			Name:      releaseName,
+			Namespace: namespace,
+			IsInstall: true,
+		},
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sources := make([]string, 0, len(templates))
+	for source := range templates {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+
+	manifests := make([]*AppManifest, 0)
+	for _, source := range sources {
+		// partials and notes are not kubernetes objects
+		if strings.HasPrefix(path.Base(source), "_") || strings.HasSuffix(source, "NOTES.txt") {
+			continue
+		}
+
+		docs := releaseutil.SplitManifests(templates[source])
+		for i := 0; i < len(docs); i++ {
+			content := strings.TrimSpace(docs[fmt.Sprintf("manifest-%d", i)])
+			if len(content) == 0 {
+				continue
+			}
+			head, err := parseManifestHead(content)
+			if err != nil {
+				return nil, nil, err
+			}
+			manifests = append(manifests, &AppManifest{
+				Source:  source,
+				Kind:    head.Kind,
+				Name:    head.Metadata.Name,
+				Hook:    head.Metadata.Annotations[hooks.HookAnno],
+				Content: content,
+			})
+		}
+	}
+
+	return manifests, values, nil
+}
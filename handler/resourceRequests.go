@@ -0,0 +1,192 @@
+package handler
+
+import (
+	"github.com/ghodss/yaml"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// ResourceRequests is the total cpu (millicores), memory (MiB) and storage (MiB) requested by rendered manifests,
+// in the same units as the namespace limits
+type ResourceRequests struct {
+	Cpu     uint32
+	Mem     uint32
+	Storage uint32
+}
+
+type manifestHead struct {
+	Kind     string `json:"kind"`
+	Metadata struct {
+		Name        string            `json:"name"`
+		Annotations map[string]string `json:"annotations"`
+	} `json:"metadata"`
+}
+
+type resourceRequirements struct {
+	Limits   map[string]string `json:"limits"`
+	Requests map[string]string `json:"requests"`
+}
+
+type container struct {
+	Resources resourceRequirements `json:"resources"`
+}
+
+type podSpec struct {
+	Containers     []container `json:"containers"`
+	InitContainers []container `json:"initContainers"`
+}
+
+type podTemplate struct {
+	Spec podSpec `json:"spec"`
+}
+
+type pvcSpec struct {
+	Spec struct {
+		Resources resourceRequirements `json:"resources"`
+	} `json:"spec"`
+}
+
+// workload covers the fields of the pod controllers, pods and claims that matter for resource requests
+type workload struct {
+	Kind string `json:"kind"`
+	Spec struct {
+		podSpec
+		Replicas             *int32      `json:"replicas"`
+		Template             podTemplate `json:"template"`
+		VolumeClaimTemplates []pvcSpec   `json:"volumeClaimTemplates"`
+		JobTemplate          struct {
+			Spec struct {
+				Template podTemplate `json:"template"`
+			} `json:"spec"`
+		} `json:"jobTemplate"`
+		Resources resourceRequirements `json:"resources"`
+	} `json:"spec"`
+}
+
+func parseManifestHead(content string) (manifestHead, error) {
+	head := manifestHead{}
+	err := yaml.Unmarshal([]byte(content), &head)
+	return head, err
+}
+
+// computeResourceRequests sums the resources requested by the pods and persistent volume claims in manifests.
+// Daemonsets are counted once since the number of nodes is unknown before scheduling,
+// hooks are skipped since they only run for a short while around install, upgrade or test.
+func computeResourceRequests(manifests []*AppManifest) (ResourceRequests, error) {
+	total := ResourceRequests{}
+	for _, manifest := range manifests {
+		if len(manifest.Hook) > 0 {
+			continue
+		}
+
+		w := workload{}
+		if err := yaml.Unmarshal([]byte(manifest.Content), &w); err != nil {
+			return total, err
+		}
+
+		replicas := uint32(1)
+		if w.Spec.Replicas != nil {
+			replicas = uint32(*w.Spec.Replicas)
+		}
+
+		var pod *podSpec
+		switch w.Kind {
+		case "Pod":
+			pod = &w.Spec.podSpec
+		case "Deployment", "ReplicaSet", "ReplicationController", "StatefulSet", "Job":
+			pod = &w.Spec.Template.Spec
+		case "DaemonSet":
+			pod = &w.Spec.Template.Spec
+			replicas = 1
+		case "CronJob":
+			pod = &w.Spec.JobTemplate.Spec.Template.Spec
+			replicas = 1
+		case "PersistentVolumeClaim":
+			storage, err := requestedQuantity(w.Spec.Resources, "storage", 1<<20)
+			if err != nil {
+				return total, err
+			}
+			total.Storage += storage
+		}
+
+		if pod != nil {
+			requests, err := podRequests(pod)
+			if err != nil {
+				return total, err
+			}
+			total.Cpu += requests.Cpu * replicas
+			total.Mem += requests.Mem * replicas
+		}
+
+		for _, claim := range w.Spec.VolumeClaimTemplates {
+			storage, err := requestedQuantity(claim.Spec.Resources, "storage", 1<<20)
+			if err != nil {
+				return total, err
+			}
+			total.Storage += storage * replicas
+		}
+	}
+
+	return total, nil
+}
+
+// podRequests follows the kubernetes rule: the larger of the sum of containers and the biggest init container
+func podRequests(pod *podSpec) (ResourceRequests, error) {
+	requests := ResourceRequests{}
+	for _, c := range pod.Containers {
+		cpu, mem, err := containerRequests(c)
+		if err != nil {
+			return requests, err
+		}
+		requests.Cpu += cpu
+		requests.Mem += mem
+	}
+
+	for _, c := range pod.InitContainers {
+		cpu, mem, err := containerRequests(c)
+		if err != nil {
+			return requests, err
+		}
+		if cpu > requests.Cpu {
+			requests.Cpu = cpu
+		}
+		if mem > requests.Mem {
+			requests.Mem = mem
+		}
+	}
+
+	return requests, nil
+}
+
+func containerRequests(c container) (uint32, uint32, error) {
+	cpu, err := requestedQuantity(c.Resources, "cpu", 0)
+	if err != nil {
+		return 0, 0, err
+	}
+	mem, err := requestedQuantity(c.Resources, "memory", 1<<20)
+	if err != nil {
+		return 0, 0, err
+	}
+	return cpu, mem, nil
+}
+
+// requestedQuantity returns the request of name divided by unit, or the limit when no request is given
+// as kubernetes does. A zero unit means millis, used for cpu.
+func requestedQuantity(resources resourceRequirements, name string, unit int64) (uint32, error) {
+	value, ok := resources.Requests[name]
+	if !ok {
+		value, ok = resources.Limits[name]
+	}
+	if !ok || len(value) == 0 {
+		return 0, nil
+	}
+
+	quantity, err := resource.ParseQuantity(value)
+	if err != nil {
+		return 0, err
+	}
+
+	if unit == 0 {
+		return uint32(quantity.MilliValue()), nil
+	}
+	return uint32((quantity.Value() + unit - 1) / unit), nil
+}
@@ -0,0 +1,105 @@
+package handler
+
+import "testing"
+
+func TestComputeResourceRequests(t *testing.T) {
+	manifests := []*AppManifest{
+		{Content: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 2
+  template:
+    spec:
+      containers:
+      - name: web
+        resources:
+          requests:
+            cpu: 250m
+            memory: 128Mi
+      - name: sidecar
+        resources:
+          limits:
+            cpu: "0.1"
+            memory: 64Mi
+`},
+		{Content: `
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: db
+spec:
+  replicas: 3
+  template:
+    spec:
+      initContainers:
+      - name: init
+        resources:
+          requests:
+            cpu: "1"
+      containers:
+      - name: db
+        resources:
+          requests:
+            cpu: 500m
+            memory: 1Gi
+  volumeClaimTemplates:
+  - spec:
+      resources:
+        requests:
+          storage: 10Gi
+`},
+		{Content: `
+apiVersion: v1
+kind: PersistentVolumeClaim
+metadata:
+  name: data
+spec:
+  resources:
+    requests:
+      storage: 512Mi
+`},
+		{Content: `
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+spec:
+  ports:
+  - port: 80
+`},
+	}
+
+	requests, err := computeResourceRequests(manifests)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// web: 2 * (250m + 100m), db: 3 * max(500m, 1000m)
+	if requests.Cpu != 2*350+3*1000 {
+		t.Errorf("cpu: got %d", requests.Cpu)
+	}
+	if requests.Mem != 2*(128+64)+3*1024 {
+		t.Errorf("memory: got %d", requests.Mem)
+	}
+	if requests.Storage != 3*10*1024+512 {
+		t.Errorf("storage: got %d", requests.Storage)
+	}
+}
+
+func TestComputeResourceRequestsInvalidQuantity(t *testing.T) {
+	manifests := []*AppManifest{{Content: `
+kind: Pod
+spec:
+  containers:
+  - resources:
+      requests:
+        cpu: lots
+`}}
+
+	if _, err := computeResourceRequests(manifests); err == nil {
+		t.Error("expected error for invalid quantity")
+	}
+}
@@ -1,68 +1,56 @@
 package handler
 
 import (
+	"bytes"
+	"context"
+
+	"github.com/Ankr-network/dccn-appmgr/logger"
 	ankr_default "github.com/Ankr-network/dccn-common/protos"
 	appmgr "github.com/Ankr-network/dccn-common/protos/appmgr/v1/grpc"
 	common_proto "github.com/Ankr-network/dccn-common/protos/common"
 	common_util "github.com/Ankr-network/dccn-common/util"
 	"github.com/Masterminds/semver"
-	"io/ioutil"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"k8s.io/helm/pkg/chartutil"
 	"k8s.io/helm/pkg/proto/hapi/chart"
-	"log"
-	"net/http"
-	"os"
-	"context"
-	"errors"
 )
 
 // SaveAsChart will upload new version chart to the chartmuseum with new values.yaml
 func (p *AppMgrHandler) SaveAsChart(ctx context.Context, req *appmgr.SaveAsChartRequest) (*common_proto.Empty, error) {
 
-	log.Printf(">>>>>>>>>Debug into SaveAsChart...%+v\n ctx: %+v\n", req, ctx)
-
 	_, teamId := common_util.GetUserIDAndTeamID(ctx)
+	log := p.logger.WithContext(ctx).With(logger.Fields{"repo": req.ChartRepo, "chart": req.ChartName + "-" + req.ChartVer,
+		"save_repo": req.SaveRepo, "save_chart": req.SaveName + "-" + req.SaveVer})
 
 	if len(req.ChartName) == 0 || len(req.ChartRepo) == 0 || len(req.ChartVer) == 0 ||
 		len(req.SaveName) == 0 || len(req.SaveRepo) == 0 || len(req.SaveVer) == 0 {
-		log.Printf("invalid input: empty chart properties not accepted \n")
+		log.Warn("invalid input: empty chart properties not accepted")
 		return &common_proto.Empty{}, ankr_default.ErrEmptyChartProperties
 	}
 
 	_, err := semver.NewVersion(req.SaveVer)
 	if err != nil {
-		return &common_proto.Empty{}, errors.New("chart version is not a valid Semantic Version")
+		return &common_proto.Empty{}, invalidField("SaveVer", errInvalidChartVersion)
 	}
 
-	querySaveChart, err := http.Get(getChartURL(chartmuseumURL+"/api", teamId,
-		req.SaveRepo) + "/" + req.SaveName + "/" + req.SaveVer)
-
-	if err != nil {
-		log.Printf("cannot get chart %s from chartmuseum\nerror: %s\n", req.SaveName, err.Error())
-		return &common_proto.Empty{}, ankr_default.ErrChartMuseumGet
-	}
-	if querySaveChart.StatusCode == 200 {
-		log.Printf("invalid input: save chart already exist \n")
-		return &common_proto.Empty{}, ankr_default.ErrSaveChartAlreadyExist
+	if err := p.checkChartNotExist(ctx, teamId, req.SaveRepo, req.SaveName, req.SaveVer, ankr_default.ErrSaveChartAlreadyExist); err != nil {
+		log.Warn("chart save refused", logger.Fields{"error": err})
+		return &common_proto.Empty{}, err
 	}
 
-	queryOriginalChart, err := http.Get(getChartURL(chartmuseumURL, teamId,
-		req.ChartRepo) + "/" + req.ChartName + "-" + req.ChartVer + ".tgz")
+	chartFile, err := p.getChartFile(ctx, teamId, req.ChartRepo, req.ChartName+"-"+req.ChartVer+".tgz")
 	if err != nil {
-		log.Printf("cannot get chart %s from chartmuseum\nerror: %s\n", req.ChartName, err.Error())
-		return &common_proto.Empty{}, ankr_default.ErrChartMuseumGet
+		if isNotFound(err) {
+			log.Warn("invalid input: original chart not exist")
+			return &common_proto.Empty{}, status.Error(codes.NotFound, ankr_default.ErrOriginalChartNotExist.Error())
+		}
+		return &common_proto.Empty{}, err
 	}
-	if queryOriginalChart.StatusCode != 200 {
-		log.Printf("invalid input: original chart not exist \n")
-		return &common_proto.Empty{}, ankr_default.ErrOriginalChartNotExist
-	}
-
-	defer queryOriginalChart.Body.Close()
 
-	loadedChart, err := chartutil.LoadArchive(queryOriginalChart.Body)
+	loadedChart, err := chartutil.LoadArchive(bytes.NewReader(chartFile))
 	if err != nil {
-		log.Printf("cannot load chart from the http get response from chartmuseum , %s \nerror: %s\n",
-			req.ChartName, err.Error())
+		log.Error("cannot load chart from chartmuseum", logger.Fields{"error": err})
 		return &common_proto.Empty{}, ankr_default.ErrChartMuseumGet
 	}
 
@@ -72,45 +60,10 @@ func (p *AppMgrHandler) SaveAsChart(ctx context.Context, req *appmgr.SaveAsChart
 		Raw: string(req.ValuesYaml),
 	}
 
-	dest, err := os.Getwd()
-	if err != nil {
-		log.Printf("cannot get chart outdir")
-		return &common_proto.Empty{}, ankr_default.ErrCannotGetChartOutdir
-	}
-	log.Printf("save to outdir: %s\n", dest)
-	tarballName, err := chartutil.Save(loadedChart, dest)
-	if err == nil {
-		log.Printf("Successfully packaged chart and saved it to: %s\n", tarballName)
-	} else {
-		log.Printf("Failed to save: %s", err)
-		return &common_proto.Empty{}, ankr_default.ErrCannotGetChartOutdir
-	}
-
-	tarball, err := os.Open(tarballName)
-	if err != nil {
-		log.Printf("cannot open chart tar file")
-		return &common_proto.Empty{}, ankr_default.ErrCannotGetChartTar
-	}
-
-	chartReq, err := http.NewRequest("POST", getChartURL(chartmuseumURL+"/api",
-		teamId, req.SaveRepo), tarball)
-	if err != nil {
-		log.Printf("cannot open chart tar file, %s \n", err.Error())
-		return &common_proto.Empty{}, ankr_default.ErrCannotGetChartTar
-	}
-
-	chartRes, err := http.DefaultClient.Do(chartReq)
-	if err != nil {
-		log.Printf("cannot upload chart tar file, %s \n", err.Error())
-		return &common_proto.Empty{}, ankr_default.ErrCannotUploadChartTar
+	if err := p.pushChart(ctx, teamId, req.SaveRepo, loadedChart); err != nil {
+		return &common_proto.Empty{}, err
 	}
+	p.charts.invalidate(teamId, req.SaveRepo, req.SaveName, req.SaveVer)
 
-	message, _ := ioutil.ReadAll(chartRes.Body)
-	defer chartRes.Body.Close()
-	log.Printf(string(message))
-
-	if err := os.Remove(tarballName); err != nil {
-		log.Printf("delete temp chart tarball failed, %s \n", err.Error())
-	}
 	return &common_proto.Empty{}, nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,133 @@
+package handler
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/Ankr-network/dccn-appmgr/logger"
+	"github.com/Ankr-network/dccn-appmgr/protos/appmgrext"
+	common_proto "github.com/Ankr-network/dccn-common/protos/common"
+	common_util "github.com/Ankr-network/dccn-common/util"
+)
+
+// scores of a query term matching the fields of a chart
+const (
+	scoreNameExact    = 100
+	scoreNamePrefix   = 50
+	scoreName         = 30
+	scoreKeywordExact = 20
+	scoreKeyword      = 10
+	scoreDescription  = 5
+	scoreMaintainer   = 5
+)
+
+// SearchCharts searches charts by name, keywords, description and maintainers across repos
+func (p *AppMgrHandler) SearchCharts(ctx context.Context, req *appmgrext.SearchChartsRequest) (*appmgrext.SearchChartsResponse, error) {
+
+	_, teamId := common_util.GetUserIDAndTeamID(ctx)
+	rsp := &appmgrext.SearchChartsResponse{Results: make([]*appmgrext.ChartSearchResult, 0)}
+
+	terms := strings.Fields(strings.ToLower(req.Query))
+	if len(terms) == 0 {
+		return rsp, nil
+	}
+
+	repos := req.Repos
+	if len(repos) == 0 {
+		repos = append([]string{}, p.repos...)
+		if len(teamId) > 0 {
+			repos = append(repos, "user")
+		}
+	}
+
+	for _, repo := range repos {
+		data, err := p.chartIndex(ctx, teamId, repo)
+		if err != nil {
+			// one unreachable repo should not hide the results of the others
+			p.logger.WithContext(ctx).Warn("cannot search chart repo", logger.Fields{"repo": repo, "error": err})
+			continue
+		}
+
+		for _, v := range data {
+			latest, ok := latestChartVersion(v, false)
+			if !ok {
+				continue
+			}
+			score := scoreChart(latest, terms)
+			if score == 0 {
+				continue
+			}
+			rsp.Results = append(rsp.Results, &appmgrext.ChartSearchResult{
+				Chart: &common_proto.Chart{
+					ChartName:             latest.Name,
+					ChartRepo:             repo,
+					ChartDescription:      latest.Description,
+					ChartIconUrl:          latest.Icon,
+					ChartLatestVersion:    latest.Version,
+					ChartLatestAppVersion: latest.AppVersion,
+				},
+				Score: int32(score),
+			})
+		}
+	}
+
+	sort.SliceStable(rsp.Results, func(i, j int) bool {
+		a, b := rsp.Results[i], rsp.Results[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if a.Chart.ChartName != b.Chart.ChartName {
+			return a.Chart.ChartName < b.Chart.ChartName
+		}
+		return a.Chart.ChartRepo < b.Chart.ChartRepo
+	})
+
+	return rsp, nil
+}
+
+// scoreChart sums the best score of each lowercase term against the chart, 0 if any term does not match
+func scoreChart(c Chart, terms []string) int {
+	name := strings.ToLower(c.Name)
+	description := strings.ToLower(c.Description)
+
+	total := 0
+	for _, term := range terms {
+		best := 0
+		switch {
+		case name == term:
+			best = scoreNameExact
+		case strings.HasPrefix(name, term):
+			best = scoreNamePrefix
+		case strings.Contains(name, term):
+			best = scoreName
+		}
+
+		for _, keyword := range c.Keywords {
+			keyword = strings.ToLower(keyword)
+			if keyword == term && best < scoreKeywordExact {
+				best = scoreKeywordExact
+			} else if strings.Contains(keyword, term) && best < scoreKeyword {
+				best = scoreKeyword
+			}
+		}
+
+		if best < scoreDescription && strings.Contains(description, term) {
+			best = scoreDescription
+		}
+
+		for _, m := range c.Maintainers {
+			if best < scoreMaintainer && (strings.Contains(strings.ToLower(m.Name), term) ||
+				strings.Contains(strings.ToLower(m.Email), term)) {
+				best = scoreMaintainer
+			}
+		}
+
+		if best == 0 {
+			return 0
+		}
+		total += best
+	}
+
+	return total
+}
@@ -0,0 +1,40 @@
+package handler
+
+import "testing"
+
+func TestScoreChart(t *testing.T) {
+	wordpress := Chart{
+		Name:        "wordpress",
+		Description: "Web publishing platform for building blogs and websites.",
+		Keywords:    []string{"application", "blog", "wordpress", "php"},
+		Maintainers: []Maintainer{{Name: "Bitnami", Email: "containers@bitnami.com"}},
+	}
+	ghost := Chart{
+		Name:        "ghost",
+		Description: "A simple, powerful publishing platform",
+		Keywords:    []string{"ghost", "blog", "cms"},
+	}
+
+	cases := []struct {
+		chart Chart
+		query []string
+		score int
+	}{
+		{wordpress, []string{"wordpress"}, scoreNameExact},
+		{wordpress, []string{"word"}, scoreNamePrefix},
+		{wordpress, []string{"press"}, scoreName},
+		{wordpress, []string{"blog"}, scoreKeywordExact},
+		{wordpress, []string{"websites"}, scoreDescription},
+		{wordpress, []string{"bitnami"}, scoreMaintainer},
+		{wordpress, []string{"blog", "php"}, 2 * scoreKeywordExact},
+		{wordpress, []string{"blog", "mysql"}, 0},
+		{ghost, []string{"publishing"}, scoreDescription},
+		{ghost, []string{"wordpress"}, 0},
+	}
+
+	for _, c := range cases {
+		if score := scoreChart(c.chart, c.query); score != c.score {
+			t.Errorf("score of %s for %v is %d, want %d", c.chart.Name, c.query, score, c.score)
+		}
+	}
+}
@@ -0,0 +1,390 @@
+package handler
+
+import (
+	"context"
+
+	db "github.com/Ankr-network/dccn-appmgr/db_service"
+	"github.com/Ankr-network/dccn-appmgr/protos/appmgrext"
+	appmgr "github.com/Ankr-network/dccn-common/protos/appmgr/v1/grpc"
+	common_proto "github.com/Ankr-network/dccn-common/protos/common"
+	"google.golang.org/grpc"
+)
+
+// appMgrService and appMgrExtService prefix the method names given to interceptors, as in
+// grpc.UnaryServerInfo.FullMethod
+const (
+	appMgrService    = "/appmgr.AppMgr/"
+	appMgrExtService = "/appmgrext.AppMgrExt/"
+)
+
+// server runs the rpcs of the handler through unary interceptors, the grpc server itself is created by
+// ankr-micro without a way to add them
+type server struct {
+	handler            *AppMgrHandler
+	service            string
+	interceptors       []grpc.UnaryServerInterceptor
+	streamInterceptors []grpc.StreamServerInterceptor
+}
+
+// NewServer wraps the handler as the AppMgrServer to register, interceptors run in the given order
+func NewServer(handler *AppMgrHandler, interceptors ...grpc.UnaryServerInterceptor) appmgr.AppMgrServer {
+	return &server{handler: handler, service: appMgrService, interceptors: interceptors}
+}
+
+// NewExtServer wraps the handler as the AppMgrExtServer to register, interceptors run in the given order around
+// the unary rpcs and streamInterceptors around the chart streams
+func NewExtServer(handler *AppMgrHandler, interceptors []grpc.UnaryServerInterceptor,
+	streamInterceptors []grpc.StreamServerInterceptor) appmgrext.AppMgrExtServer {
+	return &server{handler: handler, service: appMgrExtService, interceptors: interceptors,
+		streamInterceptors: streamInterceptors}
+}
+
+func (s *server) call(ctx context.Context, method string, req interface{}, handler grpc.UnaryHandler) (interface{}, error) {
+	info := &grpc.UnaryServerInfo{Server: s.handler, FullMethod: s.service + method}
+	for i := len(s.interceptors) - 1; i >= 0; i-- {
+		interceptor, next := s.interceptors[i], handler
+		handler = func(ctx context.Context, req interface{}) (interface{}, error) {
+			return interceptor(ctx, req, info, next)
+		}
+	}
+	return handler(ctx, req)
+}
+
+func (s *server) callStream(method string, info *grpc.StreamServerInfo, stream grpc.ServerStream,
+	handler grpc.StreamHandler) error {
+	info.FullMethod = s.service + method
+	for i := len(s.streamInterceptors) - 1; i >= 0; i-- {
+		interceptor, next := s.streamInterceptors[i], handler
+		handler = func(srv interface{}, stream grpc.ServerStream) error {
+			return interceptor(srv, stream, info, next)
+		}
+	}
+	return handler(s.handler, stream)
+}
+
+// tracedBy returns a copy of the handler whose db calls are spans of the call of ctx
+func (p *AppMgrHandler) tracedBy(ctx context.Context) *AppMgrHandler {
+	traced := *p
+	traced.db = db.Traced(ctx, p.db)
+	return &traced
+}
+
+func (s *server) CreateApp(ctx context.Context, req *appmgr.CreateAppRequest) (*appmgr.CreateAppResponse, error) {
+	rsp, err := s.call(ctx, "CreateApp", req, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return s.handler.tracedBy(ctx).CreateApp(ctx, req.(*appmgr.CreateAppRequest))
+	})
+	r, _ := rsp.(*appmgr.CreateAppResponse)
+	return r, err
+}
+
+func (s *server) UpdateApp(ctx context.Context, req *appmgr.UpdateAppRequest) (*common_proto.Empty, error) {
+	rsp, err := s.call(ctx, "UpdateApp", req, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return s.handler.tracedBy(ctx).UpdateApp(ctx, req.(*appmgr.UpdateAppRequest))
+	})
+	r, _ := rsp.(*common_proto.Empty)
+	return r, err
+}
+
+func (s *server) CancelApp(ctx context.Context, req *appmgr.AppID) (*common_proto.Empty, error) {
+	rsp, err := s.call(ctx, "CancelApp", req, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return s.handler.tracedBy(ctx).CancelApp(ctx, req.(*appmgr.AppID))
+	})
+	r, _ := rsp.(*common_proto.Empty)
+	return r, err
+}
+
+func (s *server) PurgeApp(ctx context.Context, req *appmgr.AppID) (*common_proto.Empty, error) {
+	rsp, err := s.call(ctx, "PurgeApp", req, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return s.handler.tracedBy(ctx).PurgeApp(ctx, req.(*appmgr.AppID))
+	})
+	r, _ := rsp.(*common_proto.Empty)
+	return r, err
+}
+
+func (s *server) AppList(ctx context.Context, req *common_proto.Empty) (*appmgr.AppListResponse, error) {
+	rsp, err := s.call(ctx, "AppList", req, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return s.handler.tracedBy(ctx).AppList(ctx, req.(*common_proto.Empty))
+	})
+	r, _ := rsp.(*appmgr.AppListResponse)
+	return r, err
+}
+
+func (s *server) AppDetail(ctx context.Context, req *appmgr.AppID) (*appmgr.AppDetailResponse, error) {
+	rsp, err := s.call(ctx, "AppDetail", req, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return s.handler.tracedBy(ctx).AppDetail(ctx, req.(*appmgr.AppID))
+	})
+	r, _ := rsp.(*appmgr.AppDetailResponse)
+	return r, err
+}
+
+func (s *server) AppCount(ctx context.Context, req *appmgr.AppCountRequest) (*appmgr.AppCountResponse, error) {
+	rsp, err := s.call(ctx, "AppCount", req, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return s.handler.tracedBy(ctx).AppCount(ctx, req.(*appmgr.AppCountRequest))
+	})
+	r, _ := rsp.(*appmgr.AppCountResponse)
+	return r, err
+}
+
+func (s *server) AppOverview(ctx context.Context, req *common_proto.Empty) (*appmgr.AppOverviewResponse, error) {
+	rsp, err := s.call(ctx, "AppOverview", req, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return s.handler.tracedBy(ctx).AppOverview(ctx, req.(*common_proto.Empty))
+	})
+	r, _ := rsp.(*appmgr.AppOverviewResponse)
+	return r, err
+}
+
+func (s *server) ChartList(ctx context.Context, req *appmgr.ChartListRequest) (*appmgr.ChartListResponse, error) {
+	rsp, err := s.call(ctx, "ChartList", req, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return s.handler.tracedBy(ctx).ChartList(ctx, req.(*appmgr.ChartListRequest))
+	})
+	r, _ := rsp.(*appmgr.ChartListResponse)
+	return r, err
+}
+
+func (s *server) ChartDetail(ctx context.Context, req *appmgr.ChartDetailRequest) (*appmgr.ChartDetailResponse, error) {
+	rsp, err := s.call(ctx, "ChartDetail", req, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return s.handler.tracedBy(ctx).ChartDetail(ctx, req.(*appmgr.ChartDetailRequest))
+	})
+	r, _ := rsp.(*appmgr.ChartDetailResponse)
+	return r, err
+}
+
+func (s *server) DownloadChart(ctx context.Context, req *appmgr.DownloadChartRequest) (*appmgr.DownloadChartResponse, error) {
+	rsp, err := s.call(ctx, "DownloadChart", req, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return s.handler.tracedBy(ctx).DownloadChart(ctx, req.(*appmgr.DownloadChartRequest))
+	})
+	r, _ := rsp.(*appmgr.DownloadChartResponse)
+	return r, err
+}
+
+func (s *server) UploadChart(ctx context.Context, req *appmgr.UploadChartRequest) (*common_proto.Empty, error) {
+	rsp, err := s.call(ctx, "UploadChart", req, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return s.handler.tracedBy(ctx).UploadChart(ctx, req.(*appmgr.UploadChartRequest))
+	})
+	r, _ := rsp.(*common_proto.Empty)
+	return r, err
+}
+
+func (s *server) SaveAsChart(ctx context.Context, req *appmgr.SaveAsChartRequest) (*common_proto.Empty, error) {
+	rsp, err := s.call(ctx, "SaveAsChart", req, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return s.handler.tracedBy(ctx).SaveAsChart(ctx, req.(*appmgr.SaveAsChartRequest))
+	})
+	r, _ := rsp.(*common_proto.Empty)
+	return r, err
+}
+
+func (s *server) DeleteChart(ctx context.Context, req *appmgr.DeleteChartRequest) (*common_proto.Empty, error) {
+	rsp, err := s.call(ctx, "DeleteChart", req, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return s.handler.tracedBy(ctx).DeleteChart(ctx, req.(*appmgr.DeleteChartRequest))
+	})
+	r, _ := rsp.(*common_proto.Empty)
+	return r, err
+}
+
+func (s *server) CreateNamespace(ctx context.Context, req *appmgr.CreateNamespaceRequest) (*appmgr.CreateNamespaceResponse, error) {
+	rsp, err := s.call(ctx, "CreateNamespace", req, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return s.handler.tracedBy(ctx).CreateNamespace(ctx, req.(*appmgr.CreateNamespaceRequest))
+	})
+	r, _ := rsp.(*appmgr.CreateNamespaceResponse)
+	return r, err
+}
+
+func (s *server) UpdateNamespace(ctx context.Context, req *appmgr.UpdateNamespaceRequest) (*common_proto.Empty, error) {
+	rsp, err := s.call(ctx, "UpdateNamespace", req, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return s.handler.tracedBy(ctx).UpdateNamespace(ctx, req.(*appmgr.UpdateNamespaceRequest))
+	})
+	r, _ := rsp.(*common_proto.Empty)
+	return r, err
+}
+
+func (s *server) DeleteNamespace(ctx context.Context, req *appmgr.DeleteNamespaceRequest) (*common_proto.Empty, error) {
+	rsp, err := s.call(ctx, "DeleteNamespace", req, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return s.handler.tracedBy(ctx).DeleteNamespace(ctx, req.(*appmgr.DeleteNamespaceRequest))
+	})
+	r, _ := rsp.(*common_proto.Empty)
+	return r, err
+}
+
+func (s *server) NamespaceList(ctx context.Context, req *common_proto.Empty) (*appmgr.NamespaceListResponse, error) {
+	rsp, err := s.call(ctx, "NamespaceList", req, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return s.handler.tracedBy(ctx).NamespaceList(ctx, req.(*common_proto.Empty))
+	})
+	r, _ := rsp.(*appmgr.NamespaceListResponse)
+	return r, err
+}
+
+func (s *server) NamespaceCount(ctx context.Context, req *appmgr.NamespaceCountRequest) (*appmgr.NamespaceCountResponse, error) {
+	rsp, err := s.call(ctx, "NamespaceCount", req, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return s.handler.tracedBy(ctx).NamespaceCount(ctx, req.(*appmgr.NamespaceCountRequest))
+	})
+	r, _ := rsp.(*appmgr.NamespaceCountResponse)
+	return r, err
+}
+
+func (s *server) PreviewApp(ctx context.Context, req *appmgrext.PreviewAppRequest) (*appmgrext.PreviewAppResponse, error) {
+	rsp, err := s.call(ctx, "PreviewApp", req, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return s.handler.tracedBy(ctx).PreviewApp(ctx, req.(*appmgrext.PreviewAppRequest))
+	})
+	r, _ := rsp.(*appmgrext.PreviewAppResponse)
+	return r, err
+}
+
+func (s *server) SearchCharts(ctx context.Context, req *appmgrext.SearchChartsRequest) (*appmgrext.SearchChartsResponse, error) {
+	rsp, err := s.call(ctx, "SearchCharts", req, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return s.handler.tracedBy(ctx).SearchCharts(ctx, req.(*appmgrext.SearchChartsRequest))
+	})
+	r, _ := rsp.(*appmgrext.SearchChartsResponse)
+	return r, err
+}
+
+func (s *server) ListCharts(ctx context.Context, req *appmgrext.ListChartsRequest) (*appmgrext.ListChartsResponse, error) {
+	rsp, err := s.call(ctx, "ListCharts", req, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return s.handler.tracedBy(ctx).ListCharts(ctx, req.(*appmgrext.ListChartsRequest))
+	})
+	r, _ := rsp.(*appmgrext.ListChartsResponse)
+	return r, err
+}
+
+func (s *server) GetChartDetail(ctx context.Context, req *appmgrext.GetChartDetailRequest) (*appmgrext.GetChartDetailResponse, error) {
+	rsp, err := s.call(ctx, "GetChartDetail", req, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return s.handler.tracedBy(ctx).GetChartDetail(ctx, req.(*appmgrext.GetChartDetailRequest))
+	})
+	r, _ := rsp.(*appmgrext.GetChartDetailResponse)
+	return r, err
+}
+
+func (s *server) AppUpgrades(ctx context.Context, req *appmgrext.AppUpgradesRequest) (*appmgrext.AppUpgradesResponse, error) {
+	rsp, err := s.call(ctx, "AppUpgrades", req, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return s.handler.tracedBy(ctx).AppUpgrades(ctx, req.(*appmgrext.AppUpgradesRequest))
+	})
+	r, _ := rsp.(*appmgrext.AppUpgradesResponse)
+	return r, err
+}
+
+func (s *server) SetUpgradePolicy(ctx context.Context, req *appmgrext.SetUpgradePolicyRequest) (*common_proto.Empty, error) {
+	rsp, err := s.call(ctx, "SetUpgradePolicy", req, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return s.handler.tracedBy(ctx).SetUpgradePolicy(ctx, req.(*appmgrext.SetUpgradePolicyRequest))
+	})
+	r, _ := rsp.(*common_proto.Empty)
+	return r, err
+}
+
+func (s *server) PromoteChart(ctx context.Context, req *appmgrext.PromoteChartRequest) (*common_proto.Empty, error) {
+	rsp, err := s.call(ctx, "PromoteChart", req, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return s.handler.tracedBy(ctx).PromoteChart(ctx, req.(*appmgrext.PromoteChartRequest))
+	})
+	r, _ := rsp.(*common_proto.Empty)
+	return r, err
+}
+
+func (s *server) DeleteChartVersion(ctx context.Context, req *appmgrext.DeleteChartVersionRequest) (*common_proto.Empty, error) {
+	rsp, err := s.call(ctx, "DeleteChartVersion", req, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return s.handler.tracedBy(ctx).DeleteChartVersion(ctx, req.(*appmgrext.DeleteChartVersionRequest))
+	})
+	r, _ := rsp.(*common_proto.Empty)
+	return r, err
+}
+
+func (s *server) SetMemberRole(ctx context.Context, req *appmgrext.SetMemberRoleRequest) (*common_proto.Empty, error) {
+	rsp, err := s.call(ctx, "SetMemberRole", req, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return s.handler.tracedBy(ctx).SetMemberRole(ctx, req.(*appmgrext.SetMemberRoleRequest))
+	})
+	r, _ := rsp.(*common_proto.Empty)
+	return r, err
+}
+
+func (s *server) OperatorListApps(ctx context.Context, req *appmgrext.OperatorListRequest) (*appmgrext.OperatorListAppsResponse, error) {
+	rsp, err := s.call(ctx, "OperatorListApps", req, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return s.handler.tracedBy(ctx).OperatorListApps(ctx, req.(*appmgrext.OperatorListRequest))
+	})
+	r, _ := rsp.(*appmgrext.OperatorListAppsResponse)
+	return r, err
+}
+
+func (s *server) OperatorListNamespaces(ctx context.Context, req *appmgrext.OperatorListRequest) (*appmgrext.OperatorListNamespacesResponse, error) {
+	rsp, err := s.call(ctx, "OperatorListNamespaces", req, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return s.handler.tracedBy(ctx).OperatorListNamespaces(ctx, req.(*appmgrext.OperatorListRequest))
+	})
+	r, _ := rsp.(*appmgrext.OperatorListNamespacesResponse)
+	return r, err
+}
+
+func (s *server) OperatorSetStatus(ctx context.Context, req *appmgrext.OperatorSetStatusRequest) (*common_proto.Empty, error) {
+	rsp, err := s.call(ctx, "OperatorSetStatus", req, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return s.handler.tracedBy(ctx).OperatorSetStatus(ctx, req.(*appmgrext.OperatorSetStatusRequest))
+	})
+	r, _ := rsp.(*common_proto.Empty)
+	return r, err
+}
+
+func (s *server) OperatorRepublish(ctx context.Context, req *appmgrext.OperatorRepublishRequest) (*common_proto.Empty, error) {
+	rsp, err := s.call(ctx, "OperatorRepublish", req, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return s.handler.tracedBy(ctx).OperatorRepublish(ctx, req.(*appmgrext.OperatorRepublishRequest))
+	})
+	r, _ := rsp.(*common_proto.Empty)
+	return r, err
+}
+
+func (s *server) DrainCluster(ctx context.Context, req *appmgrext.DrainClusterRequest) (*appmgrext.DrainClusterResponse, error) {
+	rsp, err := s.call(ctx, "DrainCluster", req, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return s.handler.tracedBy(ctx).DrainCluster(ctx, req.(*appmgrext.DrainClusterRequest))
+	})
+	r, _ := rsp.(*appmgrext.DrainClusterResponse)
+	return r, err
+}
+
+func (s *server) UsageHistory(ctx context.Context, req *appmgrext.UsageHistoryRequest) (*appmgrext.UsageHistoryResponse, error) {
+	rsp, err := s.call(ctx, "UsageHistory", req, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return s.handler.tracedBy(ctx).UsageHistory(ctx, req.(*appmgrext.UsageHistoryRequest))
+	})
+	r, _ := rsp.(*appmgrext.UsageHistoryResponse)
+	return r, err
+}
+
+func (s *server) ClusterUsageHistory(ctx context.Context, req *appmgrext.ClusterUsageHistoryRequest) (*appmgrext.UsageHistoryResponse, error) {
+	rsp, err := s.call(ctx, "ClusterUsageHistory", req, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return s.handler.tracedBy(ctx).ClusterUsageHistory(ctx, req.(*appmgrext.ClusterUsageHistoryRequest))
+	})
+	r, _ := rsp.(*appmgrext.UsageHistoryResponse)
+	return r, err
+}
+
+func (s *server) UploadChartStream(stream appmgrext.AppMgrExt_UploadChartStreamServer) error {
+	info := &grpc.StreamServerInfo{IsClientStream: true}
+	return s.callStream("UploadChartStream", info, stream, func(srv interface{}, stream grpc.ServerStream) error {
+		return s.handler.tracedBy(stream.Context()).UploadChartStream(uploadChartStream{stream})
+	})
+}
+
+func (s *server) DownloadChartStream(req *appmgrext.DownloadChartStreamRequest, stream appmgrext.AppMgrExt_DownloadChartStreamServer) error {
+	info := &grpc.StreamServerInfo{IsServerStream: true}
+	return s.callStream("DownloadChartStream", info, stream, func(srv interface{}, stream grpc.ServerStream) error {
+		return s.handler.tracedBy(stream.Context()).DownloadChartStream(req, downloadChartStream{stream})
+	})
+}
+
+// uploadChartStream and downloadChartStream give back the chart stream methods to the streams the interceptors
+// wrapped, as the generated ones do
+type uploadChartStream struct {
+	grpc.ServerStream
+}
+
+func (s uploadChartStream) SendAndClose(m *common_proto.Empty) error {
+	return s.SendMsg(m)
+}
+
+func (s uploadChartStream) Recv() (*appmgrext.ChartChunk, error) {
+	m := new(appmgrext.ChartChunk)
+	if err := s.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+type downloadChartStream struct {
+	grpc.ServerStream
+}
+
+func (s downloadChartStream) Send(m *appmgrext.ChartChunk) error {
+	return s.SendMsg(m)
+}
@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Ankr-network/dccn-appmgr/logger"
+	"github.com/Ankr-network/dccn-appmgr/protos/appmgrext"
+	ankr_default "github.com/Ankr-network/dccn-common/protos"
+	common_proto "github.com/Ankr-network/dccn-common/protos/common"
+	"gopkg.in/mgo.v2"
+)
+
+var errNoAdminLeft = errors.New(ankr_default.LogicError + "the team would have no admin left")
+
+// SetMemberRole sets which rpcs a member of the team may call. A team with roles set always keeps an admin: the
+// last one can not be demoted, and the member setting the first role of a team becomes its admin.
+func (p *AppMgrHandler) SetMemberRole(ctx context.Context, req *appmgrext.SetMemberRoleRequest) (*common_proto.Empty, error) {
+	userId, callerTeamId := identity(ctx)
+	teamId := callerTeamId
+	// authorized for operators only when it names another team
+	if len(req.TeamId) > 0 {
+		teamId = req.TeamId
+	}
+	log := p.logger.WithContext(ctx).With(logger.Fields{"team_id": teamId, "user_id": req.UserId, "role": req.Role})
+
+	if len(req.UserId) == 0 {
+		return &common_proto.Empty{}, invalidField("UserId", errors.New("invalid input: empty user id"))
+	}
+	if _, ok := roleRanks[req.Role]; !ok {
+		log.Warn("invalid input: unknown role")
+		return &common_proto.Empty{}, invalidField("Role", errors.New("invalid input: role must be viewer, deployer or admin"))
+	}
+
+	roles, err := p.db.CountMemberRoles(teamId)
+	if err != nil {
+		log.Error("cannot count member roles", logger.Fields{"error": err})
+		return &common_proto.Empty{}, err
+	}
+	current, err := p.db.GetMemberRole(teamId, req.UserId)
+	if err != nil && err != mgo.ErrNotFound {
+		log.Error("cannot get member role", logger.Fields{"error": err})
+		return &common_proto.Empty{}, err
+	}
+
+	admins := roles[RoleAdmin]
+	if current == RoleAdmin {
+		admins--
+	}
+	if req.Role == RoleAdmin {
+		admins++
+	}
+	// the caller was admin as a member of a team without roles, it stays admin once the team has some
+	bootstrap := len(roles) == 0 && teamId == callerTeamId && req.UserId != userId
+	if bootstrap {
+		admins++
+	}
+	if admins == 0 {
+		log.Warn("role refused", logger.Fields{"error": errNoAdminLeft})
+		return &common_proto.Empty{}, errNoAdminLeft
+	}
+
+	if bootstrap {
+		if err := p.db.SetMemberRole(teamId, userId, RoleAdmin); err != nil {
+			log.Error("cannot make caller admin", logger.Fields{"error": err})
+			return &common_proto.Empty{}, err
+		}
+	}
+	if err := p.db.SetMemberRole(teamId, req.UserId, req.Role); err != nil {
+		log.Error("cannot set member role", logger.Fields{"error": err})
+		return &common_proto.Empty{}, err
+	}
+
+	return &common_proto.Empty{}, nil
+}
@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Ankr-network/dccn-appmgr/protos/appmgrext"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// setRole authorizes and calls SetMemberRole as the interceptors do, and returns its grpc code
+func setRole(p *AppMgrHandler, ctx context.Context, req *appmgrext.SetMemberRoleRequest) codes.Code {
+	if err := p.authorize(ctx, "SetMemberRole", req); err != nil {
+		return status.Code(toStatus(err))
+	}
+	_, err := p.SetMemberRole(ctx, req)
+	return status.Code(toStatus(err))
+}
+
+func TestSetMemberRoleBootstrap(t *testing.T) {
+	defer useTestIdentity()()
+	p := newAuthHandler()
+	roles := p.db.(*authDB).roles
+
+	// members of a team without roles keep the rights they had before roles, and one of them sets the first
+	if err := p.authorize(withIdentity("alice", "team-new"), "CreateApp", nil); err != nil {
+		t.Errorf("CreateApp by member of a team without roles: %v", err)
+	}
+	if code := setRole(p, withIdentity("alice", "team-new"), &appmgrext.SetMemberRoleRequest{UserId: "bob", Role: RoleDeployer}); code != codes.OK {
+		t.Fatalf("first role of the team gives %s", code)
+	}
+	if roles["team-new/alice"] != RoleAdmin || roles["team-new/bob"] != RoleDeployer {
+		t.Errorf("got roles %v, want alice admin and bob deployer", roles)
+	}
+	// once roles are set, members without one get the default role
+	if err := p.authorize(withIdentity("carol", "team-new"), "CreateApp", nil); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("CreateApp by member without a role gives %v, want PermissionDenied", err)
+	}
+
+	// the last admin can not be demoted, not even by an operator
+	if code := setRole(p, withIdentity("alice", "team-new"), &appmgrext.SetMemberRoleRequest{UserId: "alice", Role: RoleViewer}); code != codes.FailedPrecondition {
+		t.Errorf("demoting the last admin gives %s, want FailedPrecondition", code)
+	}
+	if code := setRole(p, withIdentity("operator", "team-ops"), &appmgrext.SetMemberRoleRequest{UserId: "alice", Role: RoleViewer, TeamId: "team-new"}); code != codes.FailedPrecondition {
+		t.Errorf("demoting the last admin by an operator gives %s, want FailedPrecondition", code)
+	}
+	if code := setRole(p, withIdentity("alice", "team-new"), &appmgrext.SetMemberRoleRequest{UserId: "bob", Role: RoleAdmin}); code != codes.OK {
+		t.Fatalf("second admin gives %s", code)
+	}
+	if code := setRole(p, withIdentity("alice", "team-new"), &appmgrext.SetMemberRoleRequest{UserId: "alice", Role: RoleViewer}); code != codes.OK {
+		t.Errorf("demoting an admin with another one left gives %s", code)
+	}
+
+	// operators give a team its first admin, and only an admin
+	if code := setRole(p, withIdentity("operator", "team-ops"), &appmgrext.SetMemberRoleRequest{UserId: "dave", Role: RoleDeployer, TeamId: "team-3"}); code != codes.FailedPrecondition {
+		t.Errorf("first role of a team set to deployer by an operator gives %s, want FailedPrecondition", code)
+	}
+	if code := setRole(p, withIdentity("operator", "team-ops"), &appmgrext.SetMemberRoleRequest{UserId: "dave", Role: RoleAdmin, TeamId: "team-3"}); code != codes.OK {
+		t.Errorf("first admin set by an operator gives %s", code)
+	}
+	if roles["team-3/dave"] != RoleAdmin || roles["team-ops/operator"] != "" {
+		t.Errorf("got roles %v, want dave admin of team-3 and no role for the operator", roles)
+	}
+	// members only set roles in their team
+	if code := setRole(p, withIdentity("dave", "team-3"), &appmgrext.SetMemberRoleRequest{UserId: "eve", Role: RoleAdmin, TeamId: "team-new"}); code != codes.PermissionDenied {
+		t.Errorf("role set in another team gives %s, want PermissionDenied", code)
+	}
+}
@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	db "github.com/Ankr-network/dccn-appmgr/db_service"
+	"github.com/Ankr-network/dccn-appmgr/logger"
+	"github.com/Ankr-network/dccn-appmgr/protos/appmgrext"
+	common_proto "github.com/Ankr-network/dccn-common/protos/common"
+	common_util "github.com/Ankr-network/dccn-common/util"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// SetUpgradePolicy sets the automatic upgrade policy of an app
+func (p *AppMgrHandler) SetUpgradePolicy(ctx context.Context, req *appmgrext.SetUpgradePolicyRequest) (*common_proto.Empty, error) {
+	_, teamId := common_util.GetUserIDAndTeamID(ctx)
+	log := p.logger.WithContext(ctx).With(logger.Fields{"app_id": req.AppId})
+
+	if req.Policy != "" && req.Policy != db.UpgradePatch && req.Policy != db.UpgradeMinor {
+		log.Warn("invalid input: unknown upgrade policy", logger.Fields{"policy": req.Policy})
+		return &common_proto.Empty{}, invalidField("Policy", errors.New("invalid input: upgrade policy must be empty, patch or minor"))
+	}
+
+	if err := checkId(teamId, req.AppId); err != nil {
+		log.Warn("invalid input: bad app id", logger.Fields{"error": err})
+		return &common_proto.Empty{}, err
+	}
+
+	if _, err := p.checkOwner(ctx, teamId, req.AppId); err != nil {
+		log.Warn("cannot check owner of app", logger.Fields{"error": err})
+		return &common_proto.Empty{}, err
+	}
+
+	if err := p.db.Update("app", req.AppId, bson.M{"$set": bson.M{"autoupgrade.policy": req.Policy}}); err != nil {
+		log.Error("cannot set upgrade policy", logger.Fields{"error": err})
+		return &common_proto.Empty{}, err
+	}
+
+	return &common_proto.Empty{}, nil
+}
@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	ankr_default "github.com/Ankr-network/dccn-common/protos"
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gopkg.in/mgo.v2"
+)
+
+var errInvalidChartVersion = errors.New("chart version is not a valid Semantic Version")
+
+// errorCodes are the grpc codes of the ankr_default errors handlers return
+var errorCodes = map[error]codes.Code{
+	mgo.ErrNotFound: codes.NotFound,
+
+	ankr_default.ErrAppNotExist:           codes.NotFound,
+	ankr_default.ErrChartNotExist:         codes.NotFound,
+	ankr_default.ErrOriginalChartNotExist: codes.NotFound,
+	ankr_default.ErrNoChartReadme:         codes.NotFound,
+
+	ankr_default.ErrChartAlreadyExist:     codes.AlreadyExists,
+	ankr_default.ErrSaveChartAlreadyExist: codes.AlreadyExists,
+
+	ankr_default.ErrInvalidInput:         codes.InvalidArgument,
+	ankr_default.ErrNoApp:                codes.InvalidArgument,
+	ankr_default.ErrNoAppname:            codes.InvalidArgument,
+	ankr_default.ErrNsEmpty:              codes.InvalidArgument,
+	ankr_default.ErrChartDetailEmpty:     codes.InvalidArgument,
+	ankr_default.ErrEmptyChartProperties: codes.InvalidArgument,
+	ankr_default.ErrCannotLoadChart:      codes.InvalidArgument,
+
+	ankr_default.ErrUserNotExist: codes.Unauthenticated,
+	ankr_default.ErrUserNotOwn:   codes.PermissionDenied,
+
+	ankr_default.ErrStatusNotSupportOperation: codes.FailedPrecondition,
+	ankr_default.ErrNSStatusCanNotUpdate:      codes.FailedPrecondition,
+	ankr_default.ErrCanceledTwice:             codes.FailedPrecondition,
+	ankr_default.ErrAlreadyPurged:             codes.FailedPrecondition,
+
+	ankr_default.ErrPublish:            codes.Unavailable,
+	ankr_default.ErrChartMuseumGet:     codes.Unavailable,
+	ankr_default.ErrCannotGetChartList: codes.Unavailable,
+}
+
+// prefixCodes are the grpc codes of the errors built from the ankr_default prefixes
+var prefixCodes = []struct {
+	prefix string
+	code   codes.Code
+}{
+	{ankr_default.ArgumentError, codes.InvalidArgument},
+	{ankr_default.LogicError, codes.FailedPrecondition},
+	{ankr_default.DialError, codes.Unavailable},
+	{ankr_default.PublishError, codes.Unavailable},
+	{ankr_default.DbError + mgo.ErrNotFound.Error(), codes.NotFound},
+	{ankr_default.DbError, codes.Internal},
+}
+
+// toStatus converts a handler error to a grpc status error, errors already carrying a status are kept.
+// Errors of unknown kind are Internal.
+func toStatus(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	if code, ok := errorCodes[err]; ok {
+		return status.Error(code, err.Error())
+	}
+	for _, p := range prefixCodes {
+		if strings.HasPrefix(err.Error(), p.prefix) {
+			return status.Error(p.code, err.Error())
+		}
+	}
+	return status.Error(codes.Internal, err.Error())
+}
+
+// StatusInterceptor converts the errors of the handlers to grpc status errors clients can branch on
+func StatusInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler) (interface{}, error) {
+	rsp, err := handler(ctx, req)
+	return rsp, toStatus(err)
+}
+
+// StreamStatusInterceptor is StatusInterceptor for streaming rpcs
+func StreamStatusInterceptor(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler) error {
+	return toStatus(handler(srv, stream))
+}
+
+// withDetails returns an error with the code and message of err and the given details
+func withDetails(code codes.Code, err error, details ...proto.Message) error {
+	s := status.New(code, err.Error())
+	if detailed, e := s.WithDetails(details...); e == nil {
+		s = detailed
+	}
+	return s.Err()
+}
+
+// invalidField is an InvalidArgument error telling which request field failed validation
+func invalidField(field string, err error) error {
+	return withDetails(codes.InvalidArgument, err, &errdetails.BadRequest{
+		FieldViolations: []*errdetails.BadRequest_FieldViolation{{Field: field, Description: err.Error()}},
+	})
+}
+
+// statusBlocked is a FailedPrecondition error telling which status of subject, like "app/<id>", blocked the operation
+func statusBlocked(subject, state string, err error) error {
+	return withDetails(codes.FailedPrecondition, err, &errdetails.PreconditionFailure{
+		Violations: []*errdetails.PreconditionFailure_Violation{{Type: "STATUS", Subject: subject, Description: state}},
+	})
+}
+
+// clusterUnavailable is an Unavailable error telling which cluster cannot be reached
+func clusterUnavailable(clusterId, operation string) error {
+	err := errors.New("cluster connection not available, " + operation)
+	return withDetails(codes.Unavailable, err, &errdetails.ResourceInfo{
+		ResourceType: "cluster",
+		ResourceName: clusterId,
+		Description:  err.Error(),
+	})
+}
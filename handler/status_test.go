@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/Ankr-network/dccn-appmgr/protos/appmgrext"
+	ankr_default "github.com/Ankr-network/dccn-common/protos"
+	appmgr "github.com/Ankr-network/dccn-common/protos/appmgr/v1/grpc"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gopkg.in/mgo.v2"
+)
+
+func TestToStatus(t *testing.T) {
+	cases := []struct {
+		err  error
+		code codes.Code
+	}{
+		{ankr_default.ErrChartNotExist, codes.NotFound},
+		{ankr_default.ErrUserNotOwn, codes.PermissionDenied},
+		{ankr_default.ErrSaveChartAlreadyExist, codes.AlreadyExists},
+		{ankr_default.ErrStatusNotSupportOperation, codes.FailedPrecondition},
+		{ankr_default.ErrPublish, codes.Unavailable},
+		{mgo.ErrNotFound, codes.NotFound},
+		{errors.New(ankr_default.DbError + mgo.ErrNotFound.Error()), codes.NotFound},
+		{errors.New(ankr_default.DbError + "connection reset"), codes.Internal},
+		{errors.New(ankr_default.ArgumentError + "User does not own this namespace"), codes.InvalidArgument},
+		{errors.New(ankr_default.LogicError + "namespace still got running app, can not delete"), codes.FailedPrecondition},
+		{errors.New("something else"), codes.Internal},
+		{status.Error(codes.AlreadyExists, "kept"), codes.AlreadyExists},
+		{quotaExceeded("ns-1", "app requests too much"), codes.ResourceExhausted},
+	}
+
+	for _, c := range cases {
+		if code := status.Code(toStatus(c.err)); code != c.code {
+			t.Errorf("%q gives %s, want %s", c.err, code, c.code)
+		}
+	}
+	if toStatus(nil) != nil {
+		t.Error("nil error converted")
+	}
+}
+
+func TestStatusDetails(t *testing.T) {
+	s := status.Convert(invalidField("ChartVer", errInvalidChartVersion))
+	if s.Code() != codes.InvalidArgument || len(s.Details()) != 1 {
+		t.Fatalf("invalid field status %s with %d details", s.Code(), len(s.Details()))
+	}
+	if v := s.Details()[0].(*errdetails.BadRequest).FieldViolations[0]; v.Field != "ChartVer" {
+		t.Errorf("field violation of %s, want ChartVer", v.Field)
+	}
+
+	s = status.Convert(statusBlocked("app/app-1", "APP_CANCELED", ankr_default.ErrCanceledTwice))
+	if s.Code() != codes.FailedPrecondition || s.Message() != ankr_default.ErrCanceledTwice.Error() {
+		t.Fatalf("status blocked %s %q", s.Code(), s.Message())
+	}
+	if v := s.Details()[0].(*errdetails.PreconditionFailure).Violations[0]; v.Subject != "app/app-1" || v.Description != "APP_CANCELED" {
+		t.Errorf("precondition violation %+v", v)
+	}
+}
+
+func TestServerInterceptors(t *testing.T) {
+	order := make([]string, 0)
+	record := func(name string) grpc.UnaryServerInterceptor {
+		return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+			handler grpc.UnaryHandler) (interface{}, error) {
+			order = append(order, name+" "+info.FullMethod)
+			return handler(ctx, req)
+		}
+	}
+
+	s := NewServer(&AppMgrHandler{charts: newChartCache(0, 0)}, StatusInterceptor, record("first"), record("second"))
+	_, err := s.ChartDetail(context.Background(), &appmgr.ChartDetailRequest{})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("ChartDetail without chart gives %v, want NotFound", err)
+	}
+	if len(order) != 2 || order[0] != "first /appmgr.AppMgr/ChartDetail" || order[1] != "second /appmgr.AppMgr/ChartDetail" {
+		t.Errorf("interceptors ran as %v", order)
+	}
+}
+
+func TestServerStreamInterceptors(t *testing.T) {
+	order := make([]string, 0)
+	record := func(name string) grpc.StreamServerInterceptor {
+		return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			order = append(order, fmt.Sprint(name, " ", info.FullMethod, " ", info.IsServerStream))
+			return handler(srv, stream)
+		}
+	}
+
+	s := NewExtServer(&AppMgrHandler{charts: newChartCache(0, 0)}, nil,
+		[]grpc.StreamServerInterceptor{StreamStatusInterceptor, record("first"), record("second")})
+	err := s.DownloadChartStream(&appmgrext.DownloadChartStreamRequest{}, &fakeChartStream{})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("DownloadChartStream without chart gives %v, want InvalidArgument", err)
+	}
+	want := []string{"first /appmgrext.AppMgrExt/DownloadChartStream true", "second /appmgrext.AppMgrExt/DownloadChartStream true"}
+	if fmt.Sprint(order) != fmt.Sprint(want) {
+		t.Errorf("interceptors ran as %v", order)
+	}
+}
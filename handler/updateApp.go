@@ -3,105 +3,149 @@ package handler
 import (
 	"context"
 	"errors"
-	"log"
-	"net/http"
 
+	"github.com/Ankr-network/dccn-appmgr/logger"
 	ankr_default "github.com/Ankr-network/dccn-common/protos"
 	appmgr "github.com/Ankr-network/dccn-common/protos/appmgr/v1/grpc"
 	common_proto "github.com/Ankr-network/dccn-common/protos/common"
 	common_util "github.com/Ankr-network/dccn-common/util"
 	"gopkg.in/mgo.v2/bson"
-	chartutil "k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/proto/hapi/chart"
 )
 
 func (p *AppMgrHandler) UpdateApp(ctx context.Context,
 	req *appmgr.UpdateAppRequest) (*common_proto.Empty, error) {
-	log.Printf(">>>>>>>>>Debug into UpdateApp: %+v\nctx: %+v\n", req, ctx)
 	_, teamId := common_util.GetUserIDAndTeamID(ctx)
+	log := p.logger.WithContext(ctx)
 
 	if req.AppDeployment == nil || (req.AppDeployment.ChartDetail == nil ||
 		len(req.AppDeployment.ChartDetail.ChartVer) == 0) && len(req.AppDeployment.AppName) == 0 {
-		log.Printf("invalid input: no valid update app parameters, %+v \n", req.AppDeployment)
-		return &common_proto.Empty{}, errors.New("invalid input: no valid update app parameters")
+		log.Warn("invalid input: no valid update app parameters")
+		return &common_proto.Empty{}, invalidField("AppDeployment", errors.New("invalid input: no valid update app parameters"))
 	}
+	log = log.With(logger.Fields{"app_id": req.AppDeployment.AppId})
 
 	if err := checkId(teamId, req.AppDeployment.AppId); err != nil {
-		log.Println(err.Error())
+		log.Warn("invalid input: bad app id", logger.Fields{"error": err})
 		return &common_proto.Empty{}, err
 	}
 
-	appReport, err := p.checkOwner(teamId, req.AppDeployment.AppId)
+	appReport, err := p.checkOwner(ctx, teamId, req.AppDeployment.AppId)
 	if err != nil {
-		log.Println(err.Error())
+		log.Warn("cannot check owner of app", logger.Fields{"error": err})
 		return &common_proto.Empty{}, err
 	}
 
 	if appReport.AppStatus != common_proto.AppStatus_APP_RUNNING &&
 		appReport.AppStatus != common_proto.AppStatus_APP_UPDATE_FAILED {
-		log.Println("app status is not running, cannot update")
-		return &common_proto.Empty{}, ankr_default.ErrStatusNotSupportOperation
+		log.Warn("app status is not running, cannot update", logger.Fields{"status": appReport.AppStatus.String()})
+		return &common_proto.Empty{}, statusBlocked("app/"+req.AppDeployment.AppId, appReport.AppStatus.String(),
+			ankr_default.ErrStatusNotSupportOperation)
 	}
 
 	appDeployment := appReport.AppDeployment
 
 	clusterConnection, err := p.db.GetClusterConnection(appDeployment.Namespace.ClusterId)
 	if err != nil || clusterConnection.Status != common_proto.DCStatus_AVAILABLE {
-		log.Println("cluster connection not available, app can not be updated")
-		return &common_proto.Empty{}, errors.New("cluster connection not available, app can not be updated")
+		log.Warn("cluster connection not available, app can not be updated",
+			logger.Fields{"cluster_id": appDeployment.Namespace.ClusterId})
+		return &common_proto.Empty{}, clusterUnavailable(appDeployment.Namespace.ClusterId, "app can not be updated")
 	}
 
 	if len(req.AppDeployment.AppName) > 0 {
 		if err := p.db.Update("app", appDeployment.AppId,
 			bson.M{"$set": bson.M{"name": req.AppDeployment.AppName}}); err != nil {
-			log.Printf(err.Error())
+			log.Error("cannot rename app", logger.Fields{"error": err})
 			return &common_proto.Empty{}, err
 		}
 	}
 
 	if req.AppDeployment.ChartDetail != nil && len(req.AppDeployment.ChartDetail.ChartVer) > 0 &&
 		req.AppDeployment.ChartDetail.ChartVer != appDeployment.ChartDetail.ChartVer {
-		appChart, err := http.Get(getChartURL(chartmuseumURL, teamId,
-			appDeployment.ChartDetail.ChartRepo) + "/" + appDeployment.ChartDetail.ChartName + "-" + req.AppDeployment.ChartDetail.ChartVer + ".tgz")
-		if err != nil {
-			log.Printf("cannot get app chart %s from chartmuseum\nerror: %s\n", req.AppDeployment.ChartDetail.ChartName, err.Error())
-			return &common_proto.Empty{}, ankr_default.ErrChartMuseumGet
-		}
-		if appChart.StatusCode != 200 {
-			log.Printf("invalid input: app chart not exist \n")
-			return &common_proto.Empty{}, ankr_default.ErrChartNotExist
-		}
-		loadedChart, err := chartutil.LoadArchive(appChart.Body)
-		if err != nil {
-			log.Printf("cannot load chart from the http get response from chartmuseum , %s \nerror: %s",
-				req.AppDeployment.ChartDetail.ChartName, err.Error())
-			return &common_proto.Empty{}, ankr_default.ErrChartMuseumGet
-		}
-		appDeployment.ChartDetail.ChartDescription = loadedChart.Metadata.Description
-		appDeployment.ChartDetail.ChartVer = req.AppDeployment.ChartDetail.ChartVer
-
-		if appDeployment.CustomValues != nil {
-			var customValues []*common_proto.CustomValue
-			for _, customValue := range appDeployment.CustomValues {
-				customValues = append(customValues, &common_proto.CustomValue{Key: "ankrCustomValues." + customValue.Key, Value: customValue.Value})
-			}
+		if err := p.updateAppChart(ctx, teamId, appDeployment, req.AppDeployment.ChartDetail.ChartVer); err != nil {
+			return &common_proto.Empty{}, err
 		}
+	}
 
-		event := common_proto.DCStream{
-			OpType:    common_proto.DCOperation_APP_UPDATE,
-			OpPayload: &common_proto.DCStream_AppDeployment{AppDeployment: appDeployment},
-		}
+	return &common_proto.Empty{}, nil
+}
 
-		if err := p.deployApp.Publish(&event); err != nil {
-			log.Println(err.Error())
-			return &common_proto.Empty{}, ankr_default.ErrPublish
-		}
+// updateAppChart asks the data center to move the app to another version of its chart and marks it updating
+func (p *AppMgrHandler) updateAppChart(ctx context.Context, teamId string, appDeployment *common_proto.AppDeployment,
+	chartVer string) error {
+	log := p.logger.WithContext(ctx).With(logger.Fields{"app_id": appDeployment.AppId, "chart_ver": chartVer})
+	loadedChart, err := p.getChartArchive(ctx, teamId, &common_proto.ChartDetail{
+		ChartRepo: appDeployment.ChartDetail.ChartRepo,
+		ChartName: appDeployment.ChartDetail.ChartName,
+		ChartVer:  chartVer,
+	})
+	if err != nil {
+		return err
+	}
 
-		// TODO: wait deamon notify
-		if err := p.db.UpdateApp(appDeployment); err != nil {
-			log.Println(err.Error())
-			return &common_proto.Empty{}, err
-		}
+	if err := p.checkUpdateResourceFit(ctx, teamId, appDeployment, loadedChart); err != nil {
+		log.Warn("chart version does not fit the namespace", logger.Fields{"error": err})
+		return err
 	}
 
-	return &common_proto.Empty{}, nil
+	appDeployment.ChartDetail.ChartDescription = loadedChart.Metadata.Description
+	appDeployment.ChartDetail.ChartVer = chartVer
+
+	event := common_proto.DCStream{
+		OpType:    common_proto.DCOperation_APP_UPDATE,
+		OpPayload: &common_proto.DCStream_AppDeployment{AppDeployment: appDeployment},
+	}
+
+	if err := p.publish(ctx, &event); err != nil {
+		return ankr_default.ErrPublish
+	}
+
+	// TODO: wait deamon notify
+	if err := p.db.UpdateApp(appDeployment); err != nil {
+		log.Error("cannot update app", logger.Fields{"error": err})
+		return err
+	}
+
+	return nil
+}
+
+// checkUpdateResourceFit checks the namespace can hold what the new chart version requests beyond the running one
+func (p *AppMgrHandler) checkUpdateResourceFit(ctx context.Context, teamId string,
+	appDeployment *common_proto.AppDeployment, loadedChart *chart.Chart) error {
+
+	namespaceRecord, err := p.db.GetNamespace(appDeployment.Namespace.NsId)
+	if err != nil {
+		return err
+	}
+
+	// custom values of the app record are already prefixed, the running version is not counted twice
+	// unless it can no longer be fetched
+	log := p.logger.WithContext(ctx).With(logger.Fields{"app_id": appDeployment.AppId,
+		"chart": appDeployment.ChartDetail.ChartName})
+	current := ResourceRequests{}
+	if runningChart, err := p.getChartArchive(ctx, teamId, appDeployment.ChartDetail); err != nil {
+		log.Warn("cannot get running chart, check against full requests",
+			logger.Fields{"chart_ver": appDeployment.ChartDetail.ChartVer, "error": err})
+	} else if current, err = chartResourceRequests(runningChart, appDeployment.CustomValues); err != nil {
+		log.Warn("cannot compute resource requests of chart", logger.Fields{"chart_ver": appDeployment.ChartDetail.ChartVer,
+			"error": err})
+		return ankr_default.ErrCannotLoadChart
+	}
+
+	updated, err := chartResourceRequests(loadedChart, appDeployment.CustomValues)
+	if err != nil {
+		log.Warn("cannot compute resource requests of chart", logger.Fields{"chart_ver": loadedChart.Metadata.Version,
+			"error": err})
+		return ankr_default.ErrCannotLoadChart
+	}
+
+	return checkResourceFit(namespaceRecord.ID, ResourceRequests{
+		Cpu:     namespaceRecord.CpuLimit,
+		Mem:     namespaceRecord.MemLimit,
+		Storage: namespaceRecord.StorageLimit,
+	}, ResourceRequests{
+		Cpu:     namespaceRecord.CpuUsage,
+		Mem:     namespaceRecord.MemUsage,
+		Storage: namespaceRecord.StorageUsage,
+	}, extraRequests(current, updated))
 }
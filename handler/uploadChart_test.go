@@ -0,0 +1,194 @@
+package handler
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Ankr-network/dccn-appmgr/protos/appmgrext"
+	appmgr "github.com/Ankr-network/dccn-common/protos/appmgr/v1/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+)
+
+func TestUploadChartConcurrent(t *testing.T) {
+	var mu sync.Mutex
+	pushed := map[string]bool{}
+	museum := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		loaded, err := chartutil.LoadArchive(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		pushed[loaded.Metadata.Name+"-"+loaded.Metadata.Version] = true
+		mu.Unlock()
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer museum.Close()
+
+	defer func(url string) { chartmuseumURL = url }(chartmuseumURL)
+	chartmuseumURL = museum.URL
+
+	tarball := testChartTarball(t)
+	tmp, restore := useTempDir(t)
+	defer restore()
+
+	wd, _ := os.Getwd()
+	before, _ := filepath.Glob(filepath.Join(wd, "*.tgz"))
+
+	p := &AppMgrHandler{charts: newChartCache(0, 0)}
+	const uploads = 32
+	var wg sync.WaitGroup
+	errs := make(chan error, uploads)
+	for i := 0; i < uploads; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			// half of the uploads share a name and version to catch collisions between them
+			_, err := p.UploadChart(context.Background(), &appmgr.UploadChartRequest{
+				ChartName: "test",
+				ChartRepo: "stable",
+				ChartVer:  fmt.Sprintf("1.0.%d", i%(uploads/2)),
+				ChartFile: tarball,
+			})
+			errs <- err
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Error(err)
+		}
+	}
+	if len(pushed) != uploads/2 {
+		t.Errorf("pushed %d charts, want %d", len(pushed), uploads/2)
+	}
+
+	after, _ := filepath.Glob(filepath.Join(wd, "*.tgz"))
+	if len(after) != len(before) {
+		t.Errorf("chart tarballs left in working dir: %v", after)
+	}
+	checkTempDirEmpty(t, tmp)
+}
+
+func TestUploadChartPushFailed(t *testing.T) {
+	museum := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method != http.MethodPost:
+			w.WriteHeader(http.StatusNotFound)
+		case strings.Contains(r.URL.Path, "dropped"):
+			// the connection drops in the middle of the push
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			conn.Close()
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer museum.Close()
+
+	defer func(url string) { chartmuseumURL = url }(chartmuseumURL)
+	chartmuseumURL = museum.URL
+
+	tarball := testChartTarball(t)
+	tmp, restore := useTempDir(t)
+	defer restore()
+
+	p := &AppMgrHandler{charts: newChartCache(0, 0)}
+	// chartmuseum errors are Internal, failing to reach it Unavailable
+	for repo, code := range map[string]codes.Code{"failed": codes.Internal, "dropped": codes.Unavailable} {
+		_, err := p.UploadChart(context.Background(), &appmgr.UploadChartRequest{
+			ChartName: "test",
+			ChartRepo: repo,
+			ChartVer:  "1.0.0",
+			ChartFile: tarball,
+		})
+		if status.Code(toStatus(err)) != code {
+			t.Errorf("upload to %s repo gives %v, want %s", repo, err, code)
+		}
+
+		streamed := sha256.Sum256(tarball)
+		err = p.UploadChartStream(&fakeChartStream{ctx: context.Background(), chunks: []*appmgrext.ChartChunk{{
+			ChartName: "test",
+			ChartRepo: repo,
+			ChartVer:  "1.0.0",
+			Size:      int64(len(tarball)),
+			Sha256:    hex.EncodeToString(streamed[:]),
+			Data:      tarball,
+		}}})
+		if status.Code(toStatus(err)) != code {
+			t.Errorf("stream upload to %s repo gives %v, want %s", repo, err, code)
+		}
+		checkTempDirEmpty(t, tmp)
+	}
+}
+
+// testChartTarball packages a test chart
+func testChartTarball(t *testing.T) []byte {
+	dir, err := ioutil.TempDir("", "upload-chart-test-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	chartFile, err := chartutil.Save(&chart.Chart{
+		Metadata: &chart.Metadata{Name: "test", Version: "0.1.0", ApiVersion: "v1"},
+	}, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tarball, err := ioutil.ReadFile(chartFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tarball
+}
+
+// useTempDir points the temp dir at an empty dir of its own until restore is called, so what the uploads
+// leave behind can be seen
+func useTempDir(t *testing.T) (string, func()) {
+	tmp, err := ioutil.TempDir("", "upload-chart-tmp-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	previous, set := os.LookupEnv("TMPDIR")
+	os.Setenv("TMPDIR", tmp)
+	return tmp, func() {
+		if set {
+			os.Setenv("TMPDIR", previous)
+		} else {
+			os.Unsetenv("TMPDIR")
+		}
+		os.RemoveAll(tmp)
+	}
+}
+
+func checkTempDirEmpty(t *testing.T, tmp string) {
+	left, err := ioutil.ReadDir(tmp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range left {
+		t.Errorf("%s left in the temp dir", file.Name())
+	}
+}
@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	db "github.com/Ankr-network/dccn-appmgr/db_service"
+	"github.com/Ankr-network/dccn-appmgr/logger"
+	"github.com/Ankr-network/dccn-appmgr/protos/appmgrext"
+)
+
+// defaultUsageRange is the range of usage history returned when the request gives no start
+const defaultUsageRange = 24 * time.Hour
+
+// UsageHistory returns the usage of a namespace, or of all namespaces of the team, over time
+func (p *AppMgrHandler) UsageHistory(ctx context.Context,
+	req *appmgrext.UsageHistoryRequest) (*appmgrext.UsageHistoryResponse, error) {
+	_, teamId := identity(ctx)
+
+	if len(req.NsId) > 0 {
+		log := p.logger.WithContext(ctx).With(logger.Fields{"namespace_id": req.NsId})
+		namespaceRecord, err := p.db.GetNamespace(req.NsId)
+		if err != nil {
+			log.Error("cannot get namespace", logger.Fields{"error": err})
+			return &appmgrext.UsageHistoryResponse{}, err
+		}
+		if err := checkNsId(teamId, namespaceRecord.TeamID); err != nil {
+			log.Warn("namespace not owned by team", logger.Fields{"error": err})
+			return &appmgrext.UsageHistoryResponse{}, err
+		}
+		return p.usageHistory(ctx, db.UsageByNamespace, req.NsId, req.From, req.To)
+	}
+	return p.usageHistory(ctx, db.UsageByTeam, teamId, req.From, req.To)
+}
+
+// ClusterUsageHistory returns the usage of all namespaces of a cluster over time, for operators
+func (p *AppMgrHandler) ClusterUsageHistory(ctx context.Context,
+	req *appmgrext.ClusterUsageHistoryRequest) (*appmgrext.UsageHistoryResponse, error) {
+
+	if len(req.ClusterId) == 0 {
+		return &appmgrext.UsageHistoryResponse{}, invalidField("ClusterId", errors.New("invalid input: empty cluster id"))
+	}
+	return p.usageHistory(ctx, db.UsageByCluster, req.ClusterId, req.From, req.To)
+}
+
+// usageHistory returns the usage of scope between from and to in unix seconds, to defaults to now and from
+// to defaultUsageRange before to
+func (p *AppMgrHandler) usageHistory(ctx context.Context, scope, id string, from, to int64) (*appmgrext.UsageHistoryResponse, error) {
+	rsp := &appmgrext.UsageHistoryResponse{}
+
+	if to == 0 {
+		to = time.Now().Unix()
+	}
+	if from == 0 {
+		from = to - int64(defaultUsageRange/time.Second)
+	}
+	if from >= to {
+		return rsp, invalidField("From", errors.New("invalid input: the range must start before it ends"))
+	}
+
+	points, err := p.db.GetUsage(scope, id, from, to)
+	if err != nil {
+		p.logger.WithContext(ctx).Error("cannot get usage", logger.Fields{"scope": scope, "id": id, "error": err})
+		return rsp, err
+	}
+	for _, point := range points {
+		rsp.Points = append(rsp.Points, &appmgrext.UsagePoint{
+			Time:    point.Time,
+			Cpu:     point.Cpu,
+			Mem:     point.Mem,
+			Storage: point.Storage,
+		})
+	}
+
+	return rsp, nil
+}
@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"context"
+	"testing"
+
+	db "github.com/Ankr-network/dccn-appmgr/db_service"
+	"github.com/Ankr-network/dccn-appmgr/protos/appmgrext"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// usageDB has the namespaces of authDB and returns a point of usage for the scope and id it is asked for
+type usageDB struct {
+	*authDB
+	scope, id string
+}
+
+func (d *usageDB) GetUsage(scope, id string, from, to int64) ([]db.UsagePoint, error) {
+	d.scope, d.id = scope, id
+	return []db.UsagePoint{{Time: from, Cpu: 1, Mem: 2, Storage: 3}}, nil
+}
+
+func TestUsageHistory(t *testing.T) {
+	defer useTestIdentity()()
+	fake := &usageDB{authDB: newAuthHandler().db.(*authDB)}
+	p := &AppMgrHandler{db: fake}
+	ctx := withIdentity("viewer", "team-1")
+
+	rsp, err := p.UsageHistory(ctx, &appmgrext.UsageHistoryRequest{NsId: "ns-own", From: 100, To: 200})
+	if err != nil || len(rsp.Points) != 1 || rsp.Points[0].Time != 100 || rsp.Points[0].Storage != 3 {
+		t.Errorf("usage of own namespace gives %v, %v", rsp, err)
+	}
+	if fake.scope != db.UsageByNamespace || fake.id != "ns-own" {
+		t.Errorf("usage of %s/%s queried, want the namespace", fake.scope, fake.id)
+	}
+
+	if _, err := p.UsageHistory(ctx, &appmgrext.UsageHistoryRequest{NsId: "ns-other"}); err == nil {
+		t.Error("usage of a namespace of another team returned")
+	}
+
+	if _, err := p.UsageHistory(ctx, &appmgrext.UsageHistoryRequest{}); err != nil ||
+		fake.scope != db.UsageByTeam || fake.id != "team-1" {
+		t.Errorf("usage without namespace queried %s/%s, %v, want the team", fake.scope, fake.id, err)
+	}
+
+	if _, err := p.UsageHistory(ctx, &appmgrext.UsageHistoryRequest{From: 200, To: 100}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("range ending before it starts gives %v, want InvalidArgument", err)
+	}
+	if _, err := p.ClusterUsageHistory(context.Background(), &appmgrext.ClusterUsageHistoryRequest{}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("cluster usage without cluster gives %v, want InvalidArgument", err)
+	}
+}
@@ -0,0 +1,198 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// Service is the grpc service name the health service reports besides the whole server, named ""
+const Service = "appmgr.AppMgr"
+
+// Check tells why a dependency cannot be used, nil if it can
+type Check func(ctx context.Context) error
+
+// Checker runs the readiness checks of the dependencies of appmgr every interval and keeps their last results
+// for the kubernetes probes and the grpc health service, so probes do not hit mongo or chartmuseum themselves
+type Checker struct {
+	interval time.Duration
+	server   *grpchealth.Server
+	checks   map[string]Check
+
+	mu           sync.RWMutex
+	results      map[string]error
+	lastRound    time.Time
+	shuttingDown bool
+}
+
+// NewChecker returns a checker running its checks every interval, each given the interval to answer, and
+// setting the serving status of server by their results
+func NewChecker(interval time.Duration, server *grpchealth.Server) *Checker {
+	server.SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+	server.SetServingStatus(Service, healthpb.HealthCheckResponse_NOT_SERVING)
+	return &Checker{
+		interval:  interval,
+		server:    server,
+		checks:    map[string]Check{},
+		lastRound: time.Now(),
+	}
+}
+
+// Add adds a check of readiness, before the checker runs
+func (c *Checker) Add(name string, check Check) {
+	c.checks[name] = check
+}
+
+// Run checks every interval, forever
+func (c *Checker) Run() {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+	for {
+		c.CheckAll()
+		<-ticker.C
+	}
+}
+
+// CheckAll runs all checks at once and keeps their results, a check not done within the interval fails
+func (c *Checker) CheckAll() {
+	ctx, cancel := context.WithTimeout(context.Background(), c.interval)
+	defer cancel()
+
+	type result struct {
+		name string
+		err  error
+	}
+	done := make(chan result, len(c.checks))
+	results := make(map[string]error, len(c.checks))
+	for name, check := range c.checks {
+		results[name] = errors.New("no answer within " + c.interval.String())
+		go func(name string, check Check) {
+			done <- result{name, check(ctx)}
+		}(name, check)
+	}
+wait:
+	for range c.checks {
+		select {
+		case r := <-done:
+			results[r.name] = r.err
+		case <-ctx.Done():
+			break wait
+		}
+	}
+
+	c.mu.Lock()
+	c.results = results
+	c.lastRound = time.Now()
+	c.mu.Unlock()
+
+	status := healthpb.HealthCheckResponse_SERVING
+	if !ready(results) {
+		status = healthpb.HealthCheckResponse_NOT_SERVING
+	}
+	c.server.SetServingStatus("", status)
+	c.server.SetServingStatus(Service, status)
+}
+
+// Ready tells whether all checks passed in the last round, with the error of each check, nil if it passed.
+// Nothing is ready before the first round or once shutting down.
+func (c *Checker) Ready() (bool, map[string]error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return !c.shuttingDown && c.results != nil && ready(c.results), c.results
+}
+
+// Shutdown reports not ready from now on whatever the checks say, for the pod to be taken out of service
+// while it drains
+func (c *Checker) Shutdown() {
+	c.mu.Lock()
+	c.shuttingDown = true
+	c.mu.Unlock()
+	c.server.Shutdown()
+}
+
+// Alive tells whether the checks still run, a round missing for three intervals means the pod is wedged
+func (c *Checker) Alive() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return time.Since(c.lastRound) < 3*c.interval
+}
+
+func ready(results map[string]error) bool {
+	for _, err := range results {
+		if err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// Healthz answers the liveness probe, 503 if the checks stopped running
+func (c *Checker) Healthz(w http.ResponseWriter, r *http.Request) {
+	if !c.Alive() {
+		writeStatus(w, http.StatusServiceUnavailable, "wedged", nil)
+		return
+	}
+	writeStatus(w, http.StatusOK, "alive", nil)
+}
+
+// Readyz answers the readiness probe with the result of each check, 503 if one failed
+func (c *Checker) Readyz(w http.ResponseWriter, r *http.Request) {
+	ok, results := c.Ready()
+	checks := make(map[string]string, len(results))
+	for name, err := range results {
+		checks[name] = "ok"
+		if err != nil {
+			checks[name] = err.Error()
+		}
+	}
+	if !ok {
+		writeStatus(w, http.StatusServiceUnavailable, "not ready", checks)
+		return
+	}
+	writeStatus(w, http.StatusOK, "ready", checks)
+}
+
+func writeStatus(w http.ResponseWriter, code int, status string, checks map[string]string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(struct {
+		Status string            `json:"status"`
+		Checks map[string]string `json:"checks,omitempty"`
+	}{status, checks})
+}
+
+// Subscription is the state of a broker subscription as far as appmgr knows it: subscribed once Subscribe
+// succeeded, until it is marked closed
+type Subscription struct {
+	name       string
+	subscribed int32
+}
+
+// NewSubscription returns the state of the named subscription, not subscribed yet
+func NewSubscription(name string) *Subscription {
+	return &Subscription{name: name}
+}
+
+// Set marks the subscription subscribed or closed
+func (s *Subscription) Set(subscribed bool) {
+	var v int32
+	if subscribed {
+		v = 1
+	}
+	atomic.StoreInt32(&s.subscribed, v)
+}
+
+// Check fails while the subscription is not subscribed
+func (s *Subscription) Check(ctx context.Context) error {
+	if atomic.LoadInt32(&s.subscribed) == 0 {
+		return errors.New("not subscribed to " + s.name)
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// probe calls a probe handler and decodes its answer
+func probe(t *testing.T, handler http.HandlerFunc) (int, map[string]interface{}) {
+	rec := httptest.NewRecorder()
+	handler(rec, httptest.NewRequest("GET", "/", nil))
+	var body map[string]interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("probe answered %q, %s", rec.Body.String(), err)
+	}
+	return rec.Code, body
+}
+
+func servingStatus(t *testing.T, server *grpchealth.Server, service string) healthpb.HealthCheckResponse_ServingStatus {
+	rsp, err := server.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return rsp.Status
+}
+
+func TestChecker(t *testing.T) {
+	server := grpchealth.NewServer()
+	checker := NewChecker(50*time.Millisecond, server)
+	subscription := NewSubscription("appmgr.dcmgr")
+	checker.Add("db", func(ctx context.Context) error { return nil })
+	checker.Add("appmgr.dcmgr", subscription.Check)
+
+	if code, _ := probe(t, checker.Readyz); code != http.StatusServiceUnavailable {
+		t.Errorf("ready with %d before any check", code)
+	}
+	if code, _ := probe(t, checker.Healthz); code != http.StatusOK {
+		t.Errorf("not alive with %d before any check", code)
+	}
+
+	checker.CheckAll()
+	code, body := probe(t, checker.Readyz)
+	if checks := body["checks"].(map[string]interface{}); code != http.StatusServiceUnavailable ||
+		checks["db"] != "ok" || checks["appmgr.dcmgr"] != "not subscribed to appmgr.dcmgr" {
+		t.Errorf("readiness answered %d %v without the subscription", code, body)
+	}
+	if status := servingStatus(t, server, Service); status != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Errorf("grpc health is %s without the subscription", status)
+	}
+
+	subscription.Set(true)
+	checker.CheckAll()
+	if code, body := probe(t, checker.Readyz); code != http.StatusOK {
+		t.Errorf("readiness answered %d %v with all checks passing", code, body)
+	}
+	for _, service := range []string{"", Service} {
+		if status := servingStatus(t, server, service); status != healthpb.HealthCheckResponse_SERVING {
+			t.Errorf("grpc health of %q is %s with all checks passing", service, status)
+		}
+	}
+
+	checker.Shutdown()
+	checker.CheckAll()
+	if code, body := probe(t, checker.Readyz); code != http.StatusServiceUnavailable {
+		t.Errorf("readiness answered %d %v while shutting down", code, body)
+	}
+	if status := servingStatus(t, server, Service); status != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Errorf("grpc health is %s while shutting down", status)
+	}
+
+	checker.lastRound = time.Now().Add(-time.Second)
+	if code, _ := probe(t, checker.Healthz); code != http.StatusServiceUnavailable {
+		t.Errorf("alive with %d though no check ran for a second", code)
+	}
+}
+
+func TestCheckTimeout(t *testing.T) {
+	checker := NewChecker(20*time.Millisecond, grpchealth.NewServer())
+	stuck := make(chan struct{})
+	defer close(stuck)
+	checker.Add("chartmuseum", func(ctx context.Context) error {
+		<-stuck
+		return nil
+	})
+	checker.Add("db", func(ctx context.Context) error { return errors.New("no reachable servers") })
+
+	start := time.Now()
+	checker.CheckAll()
+	if time.Since(start) > time.Second {
+		t.Fatal("stuck check held the round")
+	}
+	ready, results := checker.Ready()
+	if ready || results["chartmuseum"] == nil || results["db"] == nil || results["db"].Error() != "no reachable servers" {
+		t.Errorf("got ready %v with results %v", ready, results)
+	}
+}
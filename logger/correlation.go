@@ -0,0 +1,106 @@
+package logger
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// CorrelationHeader is the grpc metadata key a client may set the correlation id of a call with, it is
+// sent back in the response header either way
+const CorrelationHeader = "x-correlation-id"
+
+// CorrelationField is the field lines carry the correlation id in
+const CorrelationField = "correlation_id"
+
+type correlationKey struct{}
+
+// WithCorrelationID returns a context carrying the correlation id
+func WithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationKey{}, id)
+}
+
+// CorrelationID is the correlation id ctx carries, empty if none
+func CorrelationID(ctx context.Context) string {
+	id, _ := ctx.Value(correlationKey{}).(string)
+	return id
+}
+
+// NewCorrelationID returns a new random correlation id
+func NewCorrelationID() string {
+	return uuid.New().String()
+}
+
+// IncomingCorrelationID is the correlation id the client sent in the metadata of ctx, or a new one
+func IncomingCorrelationID(ctx context.Context) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if ids := md.Get(CorrelationHeader); len(ids) > 0 && len(ids[0]) > 0 {
+			return ids[0]
+		}
+	}
+	return NewCorrelationID()
+}
+
+// UnaryServerInterceptor gives each rpc a correlation id, logs its redacted request at debug level and its
+// outcome at info level, or error level for internal and unknown errors
+func (l *Logger) UnaryServerInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler) (interface{}, error) {
+	id := IncomingCorrelationID(ctx)
+	ctx = WithCorrelationID(ctx, id)
+	_ = grpc.SetHeader(ctx, metadata.Pairs(CorrelationHeader, id))
+
+	method := info.FullMethod[strings.LastIndex(info.FullMethod, "/")+1:]
+	log := l.With(Fields{CorrelationField: id, "method": method})
+	log.Debug("rpc request", Fields{"request": Redact(req)})
+
+	start := time.Now()
+	rsp, err := handler(ctx, req)
+	logOutcome(log, start, err)
+	return rsp, err
+}
+
+// serverStream is a stream whose context carries the correlation id
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context { return s.ctx }
+
+// StreamServerInterceptor is UnaryServerInterceptor for streaming rpcs, the handler logs what it received since
+// the interceptor does not see the messages
+func (l *Logger) StreamServerInterceptor(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler) error {
+	id := IncomingCorrelationID(stream.Context())
+	ctx := WithCorrelationID(stream.Context(), id)
+	_ = stream.SetHeader(metadata.Pairs(CorrelationHeader, id))
+
+	method := info.FullMethod[strings.LastIndex(info.FullMethod, "/")+1:]
+	log := l.With(Fields{CorrelationField: id, "method": method})
+
+	start := time.Now()
+	err := handler(srv, &serverStream{ServerStream: stream, ctx: ctx})
+	logOutcome(log, start, err)
+	return err
+}
+
+// logOutcome logs how an rpc ended at info level, or error level for internal and unknown errors
+func logOutcome(log *Logger, start time.Time, err error) {
+	fields := Fields{"code": status.Code(err).String(), "duration_ms": time.Since(start).Seconds() * 1000}
+	switch status.Code(err) {
+	case codes.OK:
+		log.Info("rpc done", fields)
+	case codes.Internal, codes.Unknown:
+		fields["error"] = err
+		log.Error("rpc failed", fields)
+	default:
+		fields["error"] = err
+		log.Info("rpc rejected", fields)
+	}
+}
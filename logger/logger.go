@@ -0,0 +1,156 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Level is the severity of a log line, lines below the level of a logger are dropped
+type Level int
+
+const (
+	DebugLevel Level = iota
+	InfoLevel
+	WarnLevel
+	ErrorLevel
+)
+
+var levelNames = []string{"debug", "info", "warn", "error"}
+
+func (l Level) String() string {
+	if l < DebugLevel || l > ErrorLevel {
+		return fmt.Sprintf("level(%d)", int(l))
+	}
+	return levelNames[l]
+}
+
+// ParseLevel parses debug, info, warn or error
+func ParseLevel(s string) (Level, error) {
+	for i, name := range levelNames {
+		if strings.EqualFold(s, name) {
+			return Level(i), nil
+		}
+	}
+	return InfoLevel, errors.New("unknown log level " + s)
+}
+
+// Fields are the key values a line carries besides its message
+type Fields map[string]interface{}
+
+// Logger writes a JSON object per line with the time, level, message and fields. A nil *Logger drops
+// everything, so types built without one in tests still work.
+type Logger struct {
+	out    io.Writer
+	mu     *sync.Mutex
+	level  Level
+	fields Fields
+}
+
+// New returns a logger writing lines of level and above to out
+func New(out io.Writer, level Level) *Logger {
+	return &Logger{out: out, mu: &sync.Mutex{}, level: level}
+}
+
+// With returns a logger adding fields to every line, over the fields of l
+func (l *Logger) With(fields Fields) *Logger {
+	if l == nil {
+		return nil
+	}
+	merged := make(Fields, len(l.fields)+len(fields))
+	for k, v := range l.fields {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+	return &Logger{out: l.out, mu: l.mu, level: l.level, fields: merged}
+}
+
+// WithContext returns a logger adding the correlation id of ctx to every line
+func (l *Logger) WithContext(ctx context.Context) *Logger {
+	if id := CorrelationID(ctx); len(id) > 0 {
+		return l.With(Fields{CorrelationField: id})
+	}
+	return l
+}
+
+func (l *Logger) Debug(msg string, fields ...Fields) { l.log(DebugLevel, msg, fields) }
+func (l *Logger) Info(msg string, fields ...Fields)  { l.log(InfoLevel, msg, fields) }
+func (l *Logger) Warn(msg string, fields ...Fields)  { l.log(WarnLevel, msg, fields) }
+func (l *Logger) Error(msg string, fields ...Fields) { l.log(ErrorLevel, msg, fields) }
+
+func (l *Logger) log(level Level, msg string, fields []Fields) {
+	if l == nil || level < l.level {
+		return
+	}
+
+	all := make(Fields, len(l.fields))
+	for k, v := range l.fields {
+		all[k] = v
+	}
+	for _, f := range fields {
+		for k, v := range f {
+			all[k] = v
+		}
+	}
+	keys := make([]string, 0, len(all))
+	for k := range all {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var line bytes.Buffer
+	line.WriteString(`{"time":`)
+	writeValue(&line, time.Now().UTC().Format(time.RFC3339Nano))
+	line.WriteString(`,"level":`)
+	writeValue(&line, level.String())
+	line.WriteString(`,"msg":`)
+	writeValue(&line, msg)
+	for _, k := range keys {
+		line.WriteByte(',')
+		writeValue(&line, k)
+		line.WriteByte(':')
+		writeValue(&line, all[k])
+	}
+	line.WriteString("}\n")
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, _ = l.out.Write(line.Bytes())
+}
+
+// writeValue writes v as JSON, errors as their message and anything JSON cannot encode as formatted by fmt
+func writeValue(buf *bytes.Buffer, v interface{}) {
+	if err, ok := v.(error); ok {
+		v = err.Error()
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		b, _ = json.Marshal(fmt.Sprintf("%+v", v))
+	}
+	buf.Write(b)
+}
+
+// Writer returns a writer logging each write as a line of level, for the standard log package to go through
+// the logger
+func (l *Logger) Writer(level Level) io.Writer {
+	return &writer{logger: l, level: level}
+}
+
+type writer struct {
+	logger *Logger
+	level  Level
+}
+
+func (w *writer) Write(p []byte) (int, error) {
+	w.logger.log(w.level, strings.TrimRight(string(p), "\n"), nil)
+	return len(p), nil
+}
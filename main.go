@@ -1,7 +1,13 @@
 package main
 
 import (
+	"context"
 	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	micro2 "github.com/Ankr-network/dccn-common/ankr-micro"
 
@@ -10,26 +16,82 @@ import (
 	"github.com/Ankr-network/dccn-appmgr/config"
 	dbservice "github.com/Ankr-network/dccn-appmgr/db_service"
 	"github.com/Ankr-network/dccn-appmgr/handler"
+	"github.com/Ankr-network/dccn-appmgr/health"
+	"github.com/Ankr-network/dccn-appmgr/logger"
+	"github.com/Ankr-network/dccn-appmgr/metering"
+	"github.com/Ankr-network/dccn-appmgr/metrics"
+	"github.com/Ankr-network/dccn-appmgr/protos/appmgrext"
+	"github.com/Ankr-network/dccn-appmgr/shutdown"
 	"github.com/Ankr-network/dccn-appmgr/subscriber"
+	"github.com/Ankr-network/dccn-appmgr/tracing"
 
 	"github.com/Ankr-network/dccn-common/broker/rabbitmq"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/plugin/grpctrace"
+	"google.golang.org/grpc"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 )
 
 var (
-	conf config.Config
-	db   dbservice.DBService
-	err  error
+	conf      config.Config
+	db        dbservice.DBService
+	appLogger *logger.Logger
+	err       error
 )
 
 func main() {
 	Init()
 
-	if db, err = dbservice.New(conf.DB); err != nil {
+	if db, err = dbservice.New(conf.DB, appLogger); err != nil {
 		log.Fatal(err.Error())
 	}
-	defer db.Close()
 
+	stopTracing, err := tracing.Init(conf.TraceExporter, conf.TraceEndpoint)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+
+	// returns once drained. The db is closed first so the spans of its last calls are flushed with the rest.
 	startHandler(db)
+	db.Close()
+	stopTracing()
+}
+
+// unsubscriber is a broker which can stop a subscription, closer a broker or publisher which flushes what it
+// holds when closed. The broker of dccn-common is checked for them rather than required to have them.
+type unsubscriber interface {
+	Unsubscribe(name string) error
+}
+
+type closer interface {
+	Close() error
+}
+
+// unsubscribe stops the subscriptions so the broker keeps the deliveries the drain would refuse for another
+// instance, instead of redelivering them here until the drain is done
+func unsubscribe(broker interface{}, names ...string) {
+	u, ok := broker.(unsubscriber)
+	if !ok {
+		appLogger.Warn("broker can not unsubscribe, deliveries are refused until drained")
+		return
+	}
+	for _, name := range names {
+		if err := u.Unsubscribe(name); err != nil {
+			appLogger.Error("unsubscribe failed", logger.Fields{"subscription": name, "error": err})
+		}
+	}
+}
+
+// closeAll closes the publishers and brokers which can be closed, in order
+func closeAll(closers ...interface{}) {
+	for _, c := range closers {
+		if c, ok := c.(closer); ok {
+			if err := c.Close(); err != nil {
+				appLogger.Error("close broker connection failed", logger.Fields{"error": err})
+			}
+		}
+	}
 }
 
 // Init starts handler to listen.
@@ -39,36 +101,165 @@ func Init() {
 	if conf, err = config.Load(); err != nil {
 		log.Fatal(err.Error())
 	}
-	log.Printf("load config: %#v\n", conf)
+
+	level, err := logger.ParseLevel(conf.LogLevel)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+	appLogger = logger.New(os.Stdout, level)
+	// lines still logged with the log package go out as json too
+	log.SetFlags(log.Lshortfile)
+	log.SetOutput(appLogger.Writer(logger.InfoLevel))
+
+	appLogger.Info("load config", logger.Fields{"config": logger.Redact(conf)})
 }
 
-// StartHandler starts handler to listen.
+// StartHandler starts handler to listen, until SIGTERM drains it.
 func startHandler(db dbservice.DBService) {
 	// var srv micro.Service
 	// New Service
 	srv := micro2.NewService()
 
+	registry := prometheus.NewRegistry()
+	if err := metrics.Register(registry, db); err != nil {
+		log.Fatal(err)
+	}
+	healthServer := grpchealth.NewServer()
+	healthpb.RegisterHealthServer(srv.GetServer(), healthServer)
+	checker := health.NewChecker(time.Duration(conf.HealthCheckInterval)*time.Second, healthServer)
+	checker.Add("db", func(ctx context.Context) error { return db.Ping() })
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics.Handler(registry))
+	mux.HandleFunc("/healthz", checker.Healthz)
+	mux.HandleFunc("/readyz", checker.Readyz)
+	go func() {
+		log.Fatal(http.ListenAndServe(conf.MetricsAddr, mux))
+	}()
+
 	broker := rabbitmq.NewBroker(conf.RabbitMQUrl)
 
-	appSubscriber := subscriber.New(db)
+	// New Publisher to deploy new app action.
+	dcmgrPublisher, err := broker.Publisher("ankr.topic.appmgr.dcmgr", true)
+	if err != nil {
+		log.Fatal(err)
+	}
+	deployAppPublisher := metrics.Publisher(dcmgrPublisher)
+	// publishers are closed once nothing publishes anymore, before the broker
+	publishers := []interface{}{dcmgrPublisher}
+
+	// drain holds off the shutdown until the rpcs, deliveries and loops in flight are done
+	drain := shutdown.NewDrain()
+
+	appSubscriber := subscriber.New(db, deployAppPublisher, appLogger)
 	// Register Function as AppStatusFeedback to update app by data center manager's feedback.
-	if err := broker.Subscribe("appmgr.dcmgr", "ankr.topic.dcmgr.appmgr", true, false, appSubscriber.HandlerFeedbackEventFromDataCenter); err != nil {
+	dcmgrSubscription := health.NewSubscription("appmgr.dcmgr")
+	checker.Add("appmgr.dcmgr", dcmgrSubscription.Check)
+	handleFeedback := metrics.DCStreamHandler("appmgr.dcmgr", appSubscriber.HandlerFeedbackEventFromDataCenter)
+	if err := broker.Subscribe("appmgr.dcmgr", "ankr.topic.dcmgr.appmgr", true, false,
+		drain.DCStreamHandler(handleFeedback)); err != nil {
 		log.Fatal(err)
 	}
-	metricsSubscriber := subscriber.MetricsSubscriber{DB: db}
-	if err := broker.Subscribe("appmgr.metrics", "ankr.topic.metrics", false, false, metricsSubscriber.Handle); err != nil {
+	dcmgrSubscription.Set(true)
+	metricsSubscriber := subscriber.MetricsSubscriber{DB: db, Logger: appLogger}
+	metricsSubscription := health.NewSubscription("appmgr.metrics")
+	checker.Add("appmgr.metrics", metricsSubscription.Check)
+	handleStatus := metrics.DataCenterStatusHandler("appmgr.metrics", metricsSubscriber.Handle)
+	if err := broker.Subscribe("appmgr.metrics", "ankr.topic.metrics", false, false,
+		drain.DataCenterStatusHandler(handleStatus)); err != nil {
 		log.Fatal(err)
 	}
-
-	// New Publisher to deploy new app action.
-	deployAppPublisher, err := broker.Publisher("ankr.topic.appmgr.dcmgr", true)
+	metricsSubscription.Set(true)
+	// Register Handler
+	deployAppHandler, err := handler.New(db, deployAppPublisher, conf, appLogger)
 	if err != nil {
 		log.Fatal(err)
 	}
-	// Register Handler
-	deployAppHandler := handler.New(db, deployAppPublisher)
-	appmgr.RegisterAppMgrServer(srv.GetServer(), deployAppHandler)
+	interceptors := []grpc.UnaryServerInterceptor{grpctrace.UnaryServerInterceptor(tracing.Tracer()),
+		metrics.UnaryServerInterceptor, appLogger.UnaryServerInterceptor, drain.UnaryServerInterceptor,
+		handler.StatusInterceptor, deployAppHandler.AuthInterceptor}
+	appmgr.RegisterAppMgrServer(srv.GetServer(), handler.NewServer(deployAppHandler, interceptors...))
+	streamInterceptors := []grpc.StreamServerInterceptor{grpctrace.StreamServerInterceptor(tracing.Tracer()),
+		metrics.StreamServerInterceptor, appLogger.StreamServerInterceptor, drain.StreamServerInterceptor,
+		handler.StreamStatusInterceptor, deployAppHandler.AuthStreamInterceptor}
+	appmgrext.RegisterAppMgrExtServer(srv.GetServer(),
+		handler.NewExtServer(deployAppHandler, interceptors, streamInterceptors))
+	checker.Add("chartmuseum", deployAppHandler.ChartmuseumHealth)
+	go checker.Run()
+
+	loops, stopLoops := context.WithCancel(context.Background())
+	if conf.HeartbeatTimeout > 0 {
+		drain.Go(func() {
+			metricsSubscriber.RunStalenessCheck(loops, time.Duration(conf.HeartbeatTimeout)*time.Second)
+		})
+	}
+
+	if conf.MeteringInterval > 0 {
+		exporters := make([]metering.Exporter, 0, len(conf.MeteringExports))
+		for _, format := range conf.MeteringExports {
+			switch format {
+			case metering.FormatJSON, metering.FormatCSV:
+				exporters = append(exporters, &metering.FileExporter{Dir: conf.MeteringDir, Format: format})
+			case metering.FormatBroker:
+				meteringPublisher, err := broker.Publisher(conf.MeteringTopic, true)
+				if err != nil {
+					log.Fatal(err)
+				}
+				exporters = append(exporters, &metering.BrokerExporter{Publisher: meteringPublisher})
+				publishers = append(publishers, meteringPublisher)
+			default:
+				log.Fatalf("unknown metering export %s", format)
+			}
+		}
+		meter := metering.New(db, appLogger, exporters...)
+		drain.Go(func() { meter.Run(loops, time.Duration(conf.MeteringInterval)*time.Second) })
+	}
+
+	if conf.UpgradeCheckInterval > 0 {
+		drain.Go(func() {
+			deployAppHandler.RunUpgradeCheck(loops, time.Duration(conf.UpgradeCheckInterval)*time.Second)
+		})
+	}
+
+	// Run srv until SIGTERM or SIGINT
+	serving := make(chan struct{})
+	go func() {
+		srv.Start()
+		close(serving)
+	}()
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGTERM, os.Interrupt)
+	select {
+	case sig := <-signals:
+		appLogger.Info("shutting down", logger.Fields{"signal": sig.String()})
+	case <-serving:
+		appLogger.Warn("grpc server stopped, shutting down")
+	}
+
+	// go unready first and stop taking deliveries, then refuse new rpcs and let the work in flight finish
+	checker.Shutdown()
+	unsubscribe(broker, "appmgr.dcmgr", "appmgr.metrics")
+	dcmgrSubscription.Set(false)
+	metricsSubscription.Set(false)
+	stopLoops()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(conf.ShutdownTimeout)*time.Second)
+	defer cancel()
+	stopped := make(chan struct{})
+	go func() {
+		srv.GetServer().GracefulStop()
+		close(stopped)
+	}()
+	if err := drain.Close(ctx); err != nil {
+		appLogger.Error("shutdown timed out", logger.Fields{"error": err})
+	}
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		srv.GetServer().Stop()
+	}
 
-	// Run srv
-	srv.Start()
+	// nothing publishes anymore, flush what the publishers hold
+	closeAll(append(publishers, broker)...)
+	appLogger.Info("drained")
 }
@@ -0,0 +1,105 @@
+package metering
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	db "github.com/Ankr-network/dccn-appmgr/db_service"
+	"github.com/Ankr-network/dccn-common/broker"
+	"github.com/golang/protobuf/ptypes/wrappers"
+)
+
+// export formats
+const (
+	FormatJSON   = "json"
+	FormatCSV    = "csv"
+	FormatBroker = "broker"
+)
+
+// csvHeader are the columns of csv exports, in the order of the json fields
+var csvHeader = []string{
+	"id", "schema_version", "scope", "namespace_id", "team_id", "cluster_id", "start", "end", "seconds",
+	"cpu_usage", "mem_usage", "storage_usage", "cpu_limit", "mem_limit", "storage_limit",
+}
+
+// FileExporter writes the records of each hour to a json or csv file of its own in Dir, replacing the
+// file of an hour exported again
+type FileExporter struct {
+	Dir    string
+	Format string
+}
+
+func (e *FileExporter) Export(start int64, records []db.MeteringRecord) error {
+	name := "metering-" + time.Unix(start, 0).UTC().Format("2006010215") + "." + e.Format
+	tmp, err := ioutil.TempFile(e.Dir, "."+name)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	switch e.Format {
+	case FormatJSON:
+		err = json.NewEncoder(tmp).Encode(records)
+	case FormatCSV:
+		err = writeCSV(tmp, records)
+	default:
+		err = fmt.Errorf("unknown metering export format %s", e.Format)
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), filepath.Join(e.Dir, name))
+}
+
+func writeCSV(w io.Writer, records []db.MeteringRecord) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, r := range records {
+		if err := cw.Write([]string{
+			r.ID, strconv.Itoa(r.SchemaVersion), r.Scope, r.NamespaceID, r.TeamID, r.ClusterID,
+			strconv.FormatInt(r.Start, 10), strconv.FormatInt(r.End, 10), strconv.FormatInt(r.Seconds, 10),
+			formatFloat(r.CpuUsage), formatFloat(r.MemUsage), formatFloat(r.StorageUsage),
+			formatFloat(r.CpuLimit), formatFloat(r.MemLimit), formatFloat(r.StorageLimit),
+		}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+// BrokerExporter publishes each record to the billing topic. The broker carries protobuf messages, so
+// a record is sent as a google.protobuf.BytesValue holding its json.
+type BrokerExporter struct {
+	Publisher broker.Publisher
+}
+
+func (e *BrokerExporter) Export(start int64, records []db.MeteringRecord) error {
+	for _, r := range records {
+		body, err := json.Marshal(r)
+		if err != nil {
+			return err
+		}
+		if err := e.Publisher.Publish(&wrappers.BytesValue{Value: body}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+package metering
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	db "github.com/Ankr-network/dccn-appmgr/db_service"
+	"github.com/Ankr-network/dccn-appmgr/logger"
+	"gopkg.in/mgo.v2"
+)
+
+// SchemaVersion of the metering records, raised when a field changes meaning
+const SchemaVersion = 1
+
+// scopes of metering records
+const (
+	ScopeNamespace = "namespace"
+	ScopeTeam      = "team"
+)
+
+const (
+	// hour is the period usage is rolled up over
+	hour = time.Hour
+	// grace is how long after an hour ends heartbeats of it may still arrive
+	grace = 5 * time.Minute
+)
+
+// Exporter sends the metering records of an hour to billing, it may be called again for the same hour
+type Exporter interface {
+	Export(start int64, records []db.MeteringRecord) error
+}
+
+// Meter rolls the usage history of namespaces up into hourly metering records and exports them
+type Meter struct {
+	db        db.DBService
+	logger    *logger.Logger
+	exporters []Exporter
+	// tier is the usage history rolled up, its resolution divides an hour
+	tier db.UsageTier
+}
+
+func New(dbService db.DBService, appLogger *logger.Logger, exporters ...Exporter) *Meter {
+	return &Meter{db: dbService, logger: appLogger, exporters: exporters, tier: db.UsageTiers[len(db.UsageTiers)-1]}
+}
+
+// Run meters the hours which ended since the last run every interval, until ctx is done
+func (m *Meter) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := m.MeterHours(time.Now()); err != nil {
+			m.logger.Error("metering failed", logger.Fields{"error": err})
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// MeterHours meters and exports each hour ended before now, from the last hour metered or from the
+// previous hour on the first run, but no further back than the usage history is kept
+func (m *Meter) MeterHours(now time.Time) error {
+	last := now.Add(-grace).Truncate(hour).Unix()
+	until, err := m.db.GetMeteredUntil()
+	if err == mgo.ErrNotFound {
+		until = last - int64(hour/time.Second)
+	} else if err != nil {
+		return err
+	}
+	if oldest := now.Add(-m.tier.Retention).Truncate(hour).Add(hour).Unix(); until < oldest {
+		until = oldest
+	}
+
+	for start := until; start < last; start += int64(hour / time.Second) {
+		if err := m.meterHour(start); err != nil {
+			return err
+		}
+		if err := m.db.SetMeteredUntil(start + int64(hour/time.Second)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *Meter) meterHour(start int64) error {
+	end := start + int64(hour/time.Second)
+	usage, err := m.db.GetUsageRecords(int64(m.tier.Resolution/time.Second), start, end)
+	if err != nil {
+		return err
+	}
+
+	records := rollup(usage, start, end)
+	if err := m.db.SaveMeteringRecords(records); err != nil {
+		return err
+	}
+	for _, exporter := range m.exporters {
+		if err := exporter.Export(start, records); err != nil {
+			return err
+		}
+	}
+	m.logger.Info("metered hour", logger.Fields{"records": len(records), "hour": time.Unix(start, 0).UTC().Format(time.RFC3339)})
+	return nil
+}
+
+// rollup integrates the usage buckets of the hour per namespace, each bucket with samples counting for its
+// whole resolution, and sums the namespaces of each team
+func rollup(usage []db.UsageRecord, start, end int64) []db.MeteringRecord {
+	namespaces := make(map[string]*db.MeteringRecord)
+	teams := make(map[string]*db.MeteringRecord)
+	for _, u := range usage {
+		if u.Samples == 0 {
+			continue
+		}
+		ns, ok := namespaces[u.NamespaceID]
+		if !ok {
+			ns = newRecord(ScopeNamespace, u.NamespaceID, start, end)
+			ns.NamespaceID, ns.TeamID, ns.ClusterID = u.NamespaceID, u.TeamID, u.ClusterID
+			namespaces[u.NamespaceID] = ns
+		}
+		team, ok := teams[u.TeamID]
+		if !ok {
+			team = newRecord(ScopeTeam, u.TeamID, start, end)
+			team.TeamID = u.TeamID
+			teams[u.TeamID] = team
+		}
+		for _, r := range []*db.MeteringRecord{ns, team} {
+			seconds := float64(u.Resolution) / float64(u.Samples)
+			r.Seconds += u.Resolution
+			r.CpuUsage += float64(u.CpuSum) * seconds
+			r.MemUsage += float64(u.MemSum) * seconds
+			r.StorageUsage += float64(u.StorageSum) * seconds
+			r.CpuLimit += float64(u.CpuLimitSum) * seconds
+			r.MemLimit += float64(u.MemLimitSum) * seconds
+			r.StorageLimit += float64(u.StorageLimitSum) * seconds
+		}
+	}
+
+	records := make([]db.MeteringRecord, 0, len(namespaces)+len(teams))
+	for _, r := range namespaces {
+		records = append(records, *r)
+	}
+	for _, r := range teams {
+		records = append(records, *r)
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].ID < records[j].ID })
+	return records
+}
+
+func newRecord(scope, id string, start, end int64) *db.MeteringRecord {
+	return &db.MeteringRecord{
+		ID:            fmt.Sprintf("%s/%s/%d", scope, id, start),
+		SchemaVersion: SchemaVersion,
+		Scope:         scope,
+		Start:         start,
+		End:           end,
+	}
+}
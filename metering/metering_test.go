@@ -0,0 +1,72 @@
+package metering
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+
+	db "github.com/Ankr-network/dccn-appmgr/db_service"
+)
+
+func TestRollup(t *testing.T) {
+	usage := []db.UsageRecord{
+		// two buckets of ns-1, the first with two samples
+		{NamespaceID: "ns-1", TeamID: "team-1", ClusterID: "dc-1", Resolution: 300, Time: 3600, Samples: 2,
+			CpuSum: 200, MemSum: 20, StorageSum: 2, CpuLimitSum: 2000, MemLimitSum: 200, StorageLimitSum: 20},
+		{NamespaceID: "ns-1", TeamID: "team-1", ClusterID: "dc-1", Resolution: 300, Time: 3900, Samples: 1,
+			CpuSum: 300, MemSum: 30, StorageSum: 3, CpuLimitSum: 1000, MemLimitSum: 100, StorageLimitSum: 10},
+		{NamespaceID: "ns-2", TeamID: "team-1", ClusterID: "dc-2", Resolution: 300, Time: 3600, Samples: 1,
+			CpuSum: 50, CpuLimitSum: 500},
+		{NamespaceID: "ns-3", TeamID: "team-2", ClusterID: "dc-1", Resolution: 300, Time: 3600},
+	}
+
+	records := rollup(usage, 3600, 7200)
+	byId := make(map[string]db.MeteringRecord)
+	for _, r := range records {
+		byId[r.ID] = r
+		if r.Start != 3600 || r.End != 7200 || r.SchemaVersion != SchemaVersion {
+			t.Errorf("record %s covers %d-%d schema %d", r.ID, r.Start, r.End, r.SchemaVersion)
+		}
+	}
+	if len(records) != 3 {
+		t.Fatalf("got records %v, want ns-1, ns-2 and team-1", byId)
+	}
+
+	ns := byId["namespace/ns-1/3600"]
+	if ns.Seconds != 600 || ns.CpuUsage != 100*300+300*300 || ns.MemUsage != 10*300+30*300 ||
+		ns.CpuLimit != 1000*300+1000*300 || ns.TeamID != "team-1" || ns.ClusterID != "dc-1" {
+		t.Errorf("namespace ns-1 metered as %+v", ns)
+	}
+	team := byId["team/team-1/3600"]
+	if team.Seconds != 900 || team.CpuUsage != ns.CpuUsage+50*300 || team.CpuLimit != ns.CpuLimit+500*300 ||
+		team.NamespaceID != "" {
+		t.Errorf("team team-1 metered as %+v", team)
+	}
+
+	// rolling up again gives the same ids
+	again := rollup(usage, 3600, 7200)
+	for i := range records {
+		if again[i].ID != records[i].ID {
+			t.Errorf("record %d is %s then %s", i, records[i].ID, again[i].ID)
+		}
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeCSV(&buf, []db.MeteringRecord{{ID: "team/team-1/3600", Scope: ScopeTeam, TeamID: "team-1",
+		Start: 3600, End: 7200, Seconds: 300, CpuUsage: 1.5}}); err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 2 || len(rows[1]) != len(csvHeader) {
+		t.Fatalf("csv rows %v", rows)
+	}
+	if rows[1][0] != "team/team-1/3600" || rows[1][9] != "1.5" {
+		t.Errorf("csv row %v", rows[1])
+	}
+}
@@ -0,0 +1,110 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Ankr-network/dccn-common/broker"
+	common_proto "github.com/Ankr-network/dccn-common/protos/common"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryServerInterceptor observes the latency and errors of each rpc, it should run before the interceptors
+// which turn errors into grpc status errors so it sees the codes returned to clients
+func UnaryServerInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler) (interface{}, error) {
+	start := time.Now()
+	rsp, err := handler(ctx, req)
+	observeRPC(info.FullMethod, start, err)
+	return rsp, err
+}
+
+// StreamServerInterceptor is UnaryServerInterceptor for streaming rpcs, their latency lasts until the stream ends
+func StreamServerInterceptor(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler) error {
+	start := time.Now()
+	err := handler(srv, stream)
+	observeRPC(info.FullMethod, start, err)
+	return err
+}
+
+func observeRPC(fullMethod string, start time.Time, err error) {
+	method := fullMethod[strings.LastIndex(fullMethod, "/")+1:]
+	code := status.Code(err)
+	RPCDuration.WithLabelValues(method, code.String()).Observe(time.Since(start).Seconds())
+	if code != codes.OK {
+		RPCErrors.WithLabelValues(method, code.String()).Inc()
+	}
+}
+
+// publisher counts the DCStreams published through it
+type publisher struct {
+	broker.Publisher
+}
+
+// Publisher wraps p to count the DCStreams published by operation
+func Publisher(p broker.Publisher) broker.Publisher {
+	return &publisher{p}
+}
+
+func (p *publisher) Publish(m interface{}) error {
+	if err := p.Publisher.Publish(m); err != nil {
+		return err
+	}
+	if stream, ok := m.(*common_proto.DCStream); ok {
+		DCStreamsPublished.WithLabelValues(stream.GetOpType().String()).Inc()
+	}
+	return nil
+}
+
+// DCStreamHandler wraps a DCStream subscriber handler to count the streams consumed and the errors of
+// handling them under subscription
+func DCStreamHandler(subscription string, handle func(*common_proto.DCStream) error) func(*common_proto.DCStream) error {
+	return func(stream *common_proto.DCStream) error {
+		DCStreamsConsumed.WithLabelValues(stream.GetOpType().String()).Inc()
+		err := handle(stream)
+		if err != nil {
+			SubscriberErrors.WithLabelValues(subscription).Inc()
+		}
+		return err
+	}
+}
+
+// DataCenterStatusHandler wraps a DataCenterStatus subscriber handler to count the errors of handling them
+// under subscription
+func DataCenterStatusHandler(subscription string,
+	handle func(*common_proto.DataCenterStatus) error) func(*common_proto.DataCenterStatus) error {
+	return func(dc *common_proto.DataCenterStatus) error {
+		err := handle(dc)
+		if err != nil {
+			SubscriberErrors.WithLabelValues(subscription).Inc()
+		}
+		return err
+	}
+}
+
+// transport observes the latency of the requests made through it
+type transport struct {
+	next http.RoundTripper
+}
+
+// Transport wraps next to observe the latency of chartmuseum requests
+func Transport(next http.RoundTripper) http.RoundTripper {
+	return &transport{next: next}
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	res, err := t.next.RoundTrip(req)
+	code := "error"
+	if err == nil {
+		code = strconv.Itoa(res.StatusCode)
+	}
+	ChartmuseumDuration.WithLabelValues(req.Method, code).Observe(time.Since(start).Seconds())
+	return res, err
+}
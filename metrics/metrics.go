@@ -0,0 +1,82 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// namespace prefixes the names of all appmgr metrics
+const namespace = "appmgr"
+
+var (
+	// RPCDuration is the latency of each rpc by method and grpc code
+	RPCDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "rpc_duration_seconds",
+		Help:      "Latency of the appmgr rpcs.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "code"})
+
+	// RPCErrors counts the rpcs which did not return OK by method and grpc code
+	RPCErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rpc_errors_total",
+		Help:      "Rpcs which returned an error.",
+	}, []string{"method", "code"})
+
+	// DCStreamsPublished counts the DCStreams published to dcmgr by operation
+	DCStreamsPublished = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "dcstreams_published_total",
+		Help:      "DCStreams published to the data center manager.",
+	}, []string{"op_type"})
+
+	// DCStreamsConsumed counts the DCStreams received from dcmgr by operation
+	DCStreamsConsumed = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "dcstreams_consumed_total",
+		Help:      "DCStreams received from the data center manager.",
+	}, []string{"op_type"})
+
+	// SubscriberErrors counts the messages a subscriber failed to process by subscription
+	SubscriberErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "subscriber_errors_total",
+		Help:      "Broker messages the subscribers failed to process.",
+	}, []string{"subscription"})
+
+	// ChartmuseumDuration is the latency of chartmuseum requests by http method and status code, "error" if
+	// no response came back
+	ChartmuseumDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "chartmuseum_request_duration_seconds",
+		Help:      "Latency of the requests to chartmuseum.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "code"})
+)
+
+// Register registers the appmgr metrics, and the counts of records by status read from db at each scrape
+func Register(reg prometheus.Registerer, db StatusCounter) error {
+	collectors := []prometheus.Collector{
+		RPCDuration,
+		RPCErrors,
+		DCStreamsPublished,
+		DCStreamsConsumed,
+		SubscriberErrors,
+		ChartmuseumDuration,
+		NewStatusCollector(db),
+	}
+	for _, collector := range collectors {
+		if err := reg.Register(collector); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Handler serves the metrics of reg in the prometheus text format
+func Handler(reg prometheus.Gatherer) http.Handler {
+	return promhttp.HandlerFor(reg, promhttp.HandlerOpts{})
+}
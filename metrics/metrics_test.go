@@ -0,0 +1,201 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	common_proto "github.com/Ankr-network/dccn-common/protos/common"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type statusCounts map[string]map[int32]int
+
+func (c statusCounts) CountByStatus(collection string) (map[int32]int, error) {
+	counts, ok := c[collection]
+	if !ok {
+		return nil, errors.New("no collection " + collection)
+	}
+	return counts, nil
+}
+
+type fakePublisher struct {
+	err error
+}
+
+func (p *fakePublisher) Publish(m interface{}) error {
+	return p.err
+}
+
+// gather collects c through a registry of its own
+func gather(t *testing.T, c prometheus.Collector) []*dto.MetricFamily {
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(c)
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return families
+}
+
+func TestRegister(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	if err := Register(reg, statusCounts{}); err != nil {
+		t.Fatal(err)
+	}
+	// a second registry gets the same collectors, as tests and main each build their own
+	if err := Register(prometheus.NewRegistry(), statusCounts{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := Register(reg, statusCounts{}); err == nil {
+		t.Error("registered twice in one registry")
+	}
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	info := &grpc.UnaryServerInfo{FullMethod: "/appmgr.AppMgr/TestInterceptor"}
+	ok := func(ctx context.Context, req interface{}) (interface{}, error) { return "rsp", nil }
+	denied := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, status.Error(codes.PermissionDenied, "denied")
+	}
+
+	if rsp, err := UnaryServerInterceptor(context.Background(), nil, info, ok); rsp != "rsp" || err != nil {
+		t.Errorf("got %v, %v through the interceptor", rsp, err)
+	}
+	if _, err := UnaryServerInterceptor(context.Background(), nil, info, denied); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("got error %v through the interceptor", err)
+	}
+
+	if n := testutil.ToFloat64(RPCErrors.WithLabelValues("TestInterceptor", codes.PermissionDenied.String())); n != 1 {
+		t.Errorf("got %v errors, want 1", n)
+	}
+	if n := testutil.ToFloat64(RPCErrors.WithLabelValues("TestInterceptor", codes.OK.String())); n != 0 {
+		t.Errorf("got %v errors counted as OK, want 0", n)
+	}
+	observed := map[string]uint64{}
+	for _, metric := range gather(t, RPCDuration)[0].GetMetric() {
+		for _, label := range metric.GetLabel() {
+			if label.GetName() == "code" {
+				observed[label.GetValue()] += metric.GetHistogram().GetSampleCount()
+			}
+		}
+	}
+	if observed["OK"] != 1 || observed["PermissionDenied"] != 1 {
+		t.Errorf("got latencies observed by code %v, want one OK and one PermissionDenied", observed)
+	}
+}
+
+func TestStreamServerInterceptor(t *testing.T) {
+	info := &grpc.StreamServerInfo{FullMethod: "/appmgrext.AppMgrExt/TestStreamInterceptor", IsClientStream: true}
+	failed := func(srv interface{}, stream grpc.ServerStream) error {
+		return status.Error(codes.DataLoss, "checksum")
+	}
+
+	if err := StreamServerInterceptor(nil, nil, info, failed); status.Code(err) != codes.DataLoss {
+		t.Errorf("got error %v through the interceptor", err)
+	}
+	if n := testutil.ToFloat64(RPCErrors.WithLabelValues("TestStreamInterceptor", codes.DataLoss.String())); n != 1 {
+		t.Errorf("got %v stream errors, want 1", n)
+	}
+}
+
+func TestPublisherAndHandlers(t *testing.T) {
+	op := common_proto.DCOperation_APP_CANCEL.String()
+	published := testutil.ToFloat64(DCStreamsPublished.WithLabelValues(op))
+	consumed := testutil.ToFloat64(DCStreamsConsumed.WithLabelValues(op))
+	stream := &common_proto.DCStream{OpType: common_proto.DCOperation_APP_CANCEL}
+
+	if err := Publisher(&fakePublisher{}).Publish(stream); err != nil {
+		t.Fatal(err)
+	}
+	if err := Publisher(&fakePublisher{err: errors.New("closed")}).Publish(stream); err == nil {
+		t.Error("publish error was dropped")
+	}
+	if n := testutil.ToFloat64(DCStreamsPublished.WithLabelValues(op)) - published; n != 1 {
+		t.Errorf("got %v streams published, want only the one which succeeded", n)
+	}
+
+	handle := DCStreamHandler("test.dcmgr", func(*common_proto.DCStream) error { return errors.New("no app") })
+	if err := handle(stream); err == nil {
+		t.Error("handler error was dropped")
+	}
+	if n := testutil.ToFloat64(DCStreamsConsumed.WithLabelValues(op)) - consumed; n != 1 {
+		t.Errorf("got %v streams consumed, want 1", n)
+	}
+	if n := testutil.ToFloat64(SubscriberErrors.WithLabelValues("test.dcmgr")); n != 1 {
+		t.Errorf("got %v subscriber errors, want 1", n)
+	}
+
+	handleStatus := DataCenterStatusHandler("test.metrics", func(*common_proto.DataCenterStatus) error { return nil })
+	if err := handleStatus(&common_proto.DataCenterStatus{}); err != nil {
+		t.Fatal(err)
+	}
+	if n := testutil.ToFloat64(SubscriberErrors.WithLabelValues("test.metrics")); n != 0 {
+		t.Errorf("got %v subscriber errors, want 0", n)
+	}
+}
+
+func TestTransport(t *testing.T) {
+	museum := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}))
+	defer museum.Close()
+
+	client := &http.Client{Transport: Transport(http.DefaultTransport)}
+	res, err := client.Head(museum.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+
+	if _, err := client.Head("http://127.0.0.1:0"); err == nil {
+		t.Fatal("request to no server succeeded")
+	}
+
+	codes := map[string]uint64{}
+	for _, metric := range gather(t, ChartmuseumDuration)[0].GetMetric() {
+		for _, label := range metric.GetLabel() {
+			if label.GetName() == "code" {
+				codes[label.GetValue()] += metric.GetHistogram().GetSampleCount()
+			}
+		}
+	}
+	if codes["418"] != 1 || codes["error"] != 1 {
+		t.Errorf("got chartmuseum requests by code %v, want one 418 and one error", codes)
+	}
+}
+
+func TestStatusCollector(t *testing.T) {
+	collector := NewStatusCollector(statusCounts{
+		"app": {
+			int32(common_proto.AppStatus_APP_RUNNING): 3,
+			int32(common_proto.AppStatus_APP_FAILED):  1,
+		},
+		"clusterconnection": {int32(common_proto.DCStatus_AVAILABLE): 2},
+		// namespace cannot be counted, the other gauges are still reported
+	})
+
+	gauges, series := map[string]float64{}, 0
+	for _, family := range gather(t, collector) {
+		for _, metric := range family.GetMetric() {
+			gauges[family.GetName()] += metric.GetGauge().GetValue()
+			series++
+		}
+	}
+	if gauges["appmgr_apps"] != 4 || gauges["appmgr_cluster_connections"] != 2 {
+		t.Errorf("got gauges %v", gauges)
+	}
+	if _, ok := gauges["appmgr_namespaces"]; ok {
+		t.Errorf("got namespaces gauge %v though they could not be counted", gauges["appmgr_namespaces"])
+	}
+	if series != 3 {
+		t.Errorf("got %d status series, want 3", series)
+	}
+}
@@ -0,0 +1,65 @@
+package metrics
+
+import (
+	"log"
+
+	common_proto "github.com/Ankr-network/dccn-common/protos/common"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// StatusCounter counts the records of a collection by status, as db.DBService does
+type StatusCounter interface {
+	CountByStatus(collection string) (map[int32]int, error)
+}
+
+var (
+	appsDesc = prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "apps"),
+		"Apps by status.", []string{"status"}, nil)
+	namespacesDesc = prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "namespaces"),
+		"Namespaces by status.", []string{"status"}, nil)
+	clustersDesc = prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "cluster_connections"),
+		"Cluster connections by status.", []string{"status"}, nil)
+)
+
+// statusCollector reads the counts of apps, namespaces and cluster connections by status at each scrape, so
+// they are right whichever appmgr replica changed the records
+type statusCollector struct {
+	db StatusCounter
+}
+
+// NewStatusCollector returns a collector of the apps, namespaces and cluster connections by status in db
+func NewStatusCollector(db StatusCounter) prometheus.Collector {
+	return &statusCollector{db: db}
+}
+
+func (c *statusCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- appsDesc
+	ch <- namespacesDesc
+	ch <- clustersDesc
+}
+
+func (c *statusCollector) Collect(ch chan<- prometheus.Metric) {
+	c.collect(ch, appsDesc, "app", func(status int32) string {
+		return common_proto.AppStatus(status).String()
+	})
+	c.collect(ch, namespacesDesc, "namespace", func(status int32) string {
+		return common_proto.NamespaceStatus(status).String()
+	})
+	c.collect(ch, clustersDesc, "clusterconnection", func(status int32) string {
+		return common_proto.DCStatus(status).String()
+	})
+}
+
+// collect sends a gauge per status of the collection, or none if the db cannot be read so the scrape still
+// reports the other metrics
+func (c *statusCollector) collect(ch chan<- prometheus.Metric, desc *prometheus.Desc, collection string,
+	name func(int32) string) {
+	counts, err := c.db.CountByStatus(collection)
+	if err != nil {
+		log.Printf("cannot count %s by status for metrics, %s", collection, err.Error())
+		return
+	}
+	for status, count := range counts {
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, float64(count), name(status))
+	}
+}
@@ -0,0 +1,109 @@
+package migration
+
+import (
+	"context"
+	"time"
+
+	db "github.com/Ankr-network/dccn-appmgr/db_service"
+	common_proto "github.com/Ankr-network/dccn-common/protos/common"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/google/uuid"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// Publish sends a DCStream to dcmgr
+type Publish func(ctx context.Context, event *common_proto.DCStream) error
+
+// Namespace is the namespace of a record as it is sent to dcmgr
+func Namespace(record db.NamespaceRecord) *common_proto.Namespace {
+	return &common_proto.Namespace{
+		NsId:           record.ID,
+		NsName:         record.Name,
+		ClusterId:      record.ClusterID,
+		ClusterName:    record.ClusterName,
+		NsCpuLimit:     record.CpuLimit,
+		NsMemLimit:     record.MemLimit,
+		NsStorageLimit: record.StorageLimit,
+	}
+}
+
+// Apps recreates the running apps of a namespace moved off a draining cluster which were not moved yet, or whose
+// replacement failed, in its replacement with the same charts and custom values, and returns the ids of the
+// replacement apps. The replacement namespace must be running. Each replacement is recorded and linked to its original before it is published, so the feedback
+// of dcmgr always finds it; if the publish fails it is marked failed and the original is unlinked to be moved again.
+func Apps(ctx context.Context, database db.DBService, publish Publish, nsId string,
+	namespace *common_proto.Namespace) ([]string, error) {
+	apps, err := database.GetRunningAppsByNamespaceId(nsId)
+	if err != nil {
+		return nil, err
+	}
+
+	appIds := make([]string, 0, len(apps))
+	for _, app := range apps {
+		if len(app.MigratedTo) > 0 {
+			replacement, err := database.GetApp(app.MigratedTo)
+			if err != nil {
+				return appIds, err
+			}
+			if replacement.Status != common_proto.AppStatus_APP_FAILED {
+				continue
+			}
+		}
+		chartDetail := app.ChartDetail
+		appDeployment := &common_proto.AppDeployment{
+			AppId:        "app-" + uuid.New().String(),
+			AppName:      app.Name,
+			Namespace:    namespace,
+			ChartDetail:  &chartDetail,
+			CustomValues: app.CustomValues,
+			TeamId:       app.TeamID,
+		}
+
+		if err := database.CreateApp(appDeployment, app.TeamID, app.Creator); err != nil {
+			return appIds, err
+		}
+		if err := database.Update("app", appDeployment.AppId, bson.M{"$set": bson.M{
+			"migratedfrom": app.ID,
+			"autoupgrade":  app.AutoUpgrade,
+		}}); err != nil {
+			return appIds, err
+		}
+		if err := database.Update("app", app.ID, bson.M{"$set": bson.M{"migratedto": appDeployment.AppId}}); err != nil {
+			return appIds, err
+		}
+
+		event := common_proto.DCStream{
+			OpType:    common_proto.DCOperation_APP_CREATE,
+			OpPayload: &common_proto.DCStream_AppDeployment{AppDeployment: appDeployment},
+		}
+		if err := publish(ctx, &event); err != nil {
+			unlink(database, "app", app.ID, appDeployment.AppId, common_proto.AppStatus_APP_FAILED)
+			return appIds, err
+		}
+		appIds = append(appIds, appDeployment.AppId)
+	}
+
+	return appIds, nil
+}
+
+// unlink hides a replacement which was never published or failed to launch with status failed, and clears the
+// link of its original so the next drain moves it again. Errors are not returned, the publish error is what the caller reports.
+func unlink(database db.DBService, collection, id, replacementId string, failed interface{}) {
+	database.Update(collection, replacementId, bson.M{"$set": bson.M{
+		"status":           failed,
+		"hidden":           true,
+		"lastmodifieddate": &timestamp.Timestamp{Seconds: time.Now().Unix()},
+	}})
+	database.Update(collection, id, bson.M{"$unset": bson.M{"migratedto": ""}})
+}
+
+// UnlinkNamespace undoes a replacement namespace recorded for a namespace of a draining cluster whose publish
+// or launch failed
+func UnlinkNamespace(database db.DBService, nsId, replacementId string) {
+	unlink(database, "namespace", nsId, replacementId, common_proto.NamespaceStatus_NS_FAILED)
+}
+
+// UnlinkApp undoes a replacement app whose launch failed as UnlinkNamespace does
+func UnlinkApp(database db.DBService, appId, replacementId string) {
+	unlink(database, "app", appId, replacementId, common_proto.AppStatus_APP_FAILED)
+}
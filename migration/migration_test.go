@@ -0,0 +1,95 @@
+package migration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	db "github.com/Ankr-network/dccn-appmgr/db_service"
+	common_proto "github.com/Ankr-network/dccn-common/protos/common"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// migrationDB has the running apps of a namespace and keeps the writes made to them in order
+type migrationDB struct {
+	db.DBService
+	apps         []db.AppRecord
+	replacements map[string]db.AppRecord
+	ops          []string
+}
+
+func (d *migrationDB) GetRunningAppsByNamespaceId(namespaceId string) ([]db.AppRecord, error) {
+	return d.apps, nil
+}
+
+func (d *migrationDB) GetApp(id string) (db.AppRecord, error) {
+	return d.replacements[id], nil
+}
+
+func (d *migrationDB) CreateApp(appDeployment *common_proto.AppDeployment, teamId string, creator string) error {
+	d.ops = append(d.ops, "create "+appDeployment.AppName+" in "+appDeployment.Namespace.NsId)
+	return nil
+}
+
+func (d *migrationDB) Update(collection string, id string, update bson.M) error {
+	// replacement ids are generated
+	if id != "app-a" && id != "app-c" {
+		id = "replacement"
+	}
+	for op := range update {
+		d.ops = append(d.ops, fmt.Sprint(op, " ", id))
+	}
+	return nil
+}
+
+func TestApps(t *testing.T) {
+	fake := &migrationDB{
+		apps: []db.AppRecord{
+			{ID: "app-a", Name: "a"},
+			{ID: "app-b", Name: "b", MigratedTo: "app-moved"},
+			{ID: "app-c", Name: "c", MigratedTo: "app-failed"},
+		},
+		replacements: map[string]db.AppRecord{
+			"app-moved":  {ID: "app-moved", Status: common_proto.AppStatus_APP_LAUNCHING},
+			"app-failed": {ID: "app-failed", Status: common_proto.AppStatus_APP_FAILED},
+		},
+	}
+	var published []string
+	publish := func(ctx context.Context, event *common_proto.DCStream) error {
+		published = append(published, event.OpType.String())
+		fake.ops = append(fake.ops, "publish")
+		return nil
+	}
+
+	appIds, err := Apps(context.Background(), fake, publish, "ns-1", &common_proto.Namespace{NsId: "ns-2"})
+	if err != nil || len(appIds) != 2 {
+		t.Fatalf("migrated %v, %v, want two apps", appIds, err)
+	}
+	// the replacement is recorded and linked before it is published, an app whose replacement failed is moved again
+	want := []string{"create a in ns-2", "$set replacement", "$set app-a", "publish",
+		"create c in ns-2", "$set replacement", "$set app-c", "publish"}
+	if fmt.Sprint(fake.ops) != fmt.Sprint(want) {
+		t.Errorf("got %q, want %q", fake.ops, want)
+	}
+	if len(published) != 2 || published[0] != common_proto.DCOperation_APP_CREATE.String() {
+		t.Errorf("published %v", published)
+	}
+}
+
+func TestAppsPublishFailed(t *testing.T) {
+	fake := &migrationDB{apps: []db.AppRecord{{ID: "app-a", Name: "a"}}}
+	publish := func(ctx context.Context, event *common_proto.DCStream) error {
+		return errors.New("broker down")
+	}
+
+	appIds, err := Apps(context.Background(), fake, publish, "ns-1", &common_proto.Namespace{NsId: "ns-2"})
+	if err == nil || len(appIds) != 0 {
+		t.Fatalf("migrated %v, %v, want the publish error", appIds, err)
+	}
+	// the replacement is hidden as failed and the original is unlinked, so the next drain moves it again
+	want := []string{"create a in ns-2", "$set replacement", "$set app-a", "$set replacement", "$unset app-a"}
+	if fmt.Sprint(fake.ops) != fmt.Sprint(want) {
+		t.Errorf("got %q, want %q", fake.ops, want)
+	}
+}
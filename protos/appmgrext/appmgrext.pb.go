@@ -0,0 +1,2670 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: appmgrext/appmgrext.proto
+
+// appmgrext holds the rpcs appmgr serves besides the AppMgr service of dccn-common, on the same grpc server.
+// Regenerate appmgrext.pb.go with `make proto` after changing it.
+
+package appmgrext
+
+import (
+	context "context"
+	fmt "fmt"
+	common "github.com/Ankr-network/dccn-common/protos/common"
+	proto "github.com/golang/protobuf/proto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+// PreviewAppRequest carries the same chart detail and custom values as CreateApp
+type PreviewAppRequest struct {
+	AppName              string                `protobuf:"bytes,1,opt,name=app_name,json=appName,proto3" json:"app_name,omitempty"`
+	NsName               string                `protobuf:"bytes,2,opt,name=ns_name,json=nsName,proto3" json:"ns_name,omitempty"`
+	ChartDetail          *common.ChartDetail   `protobuf:"bytes,3,opt,name=chart_detail,json=chartDetail,proto3" json:"chart_detail,omitempty"`
+	CustomValues         []*common.CustomValue `protobuf:"bytes,4,rep,name=custom_values,json=customValues,proto3" json:"custom_values,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
+}
+
+func (m *PreviewAppRequest) Reset()         { *m = PreviewAppRequest{} }
+func (m *PreviewAppRequest) String() string { return proto.CompactTextString(m) }
+func (*PreviewAppRequest) ProtoMessage()    {}
+func (*PreviewAppRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_96ee702a13382adb, []int{0}
+}
+
+func (m *PreviewAppRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PreviewAppRequest.Unmarshal(m, b)
+}
+func (m *PreviewAppRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PreviewAppRequest.Marshal(b, m, deterministic)
+}
+func (m *PreviewAppRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PreviewAppRequest.Merge(m, src)
+}
+func (m *PreviewAppRequest) XXX_Size() int {
+	return xxx_messageInfo_PreviewAppRequest.Size(m)
+}
+func (m *PreviewAppRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PreviewAppRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PreviewAppRequest proto.InternalMessageInfo
+
+func (m *PreviewAppRequest) GetAppName() string {
+	if m != nil {
+		return m.AppName
+	}
+	return ""
+}
+
+func (m *PreviewAppRequest) GetNsName() string {
+	if m != nil {
+		return m.NsName
+	}
+	return ""
+}
+
+func (m *PreviewAppRequest) GetChartDetail() *common.ChartDetail {
+	if m != nil {
+		return m.ChartDetail
+	}
+	return nil
+}
+
+func (m *PreviewAppRequest) GetCustomValues() []*common.CustomValue {
+	if m != nil {
+		return m.CustomValues
+	}
+	return nil
+}
+
+// PreviewAppResponse is the rendered result of a chart before it is deployed
+type PreviewAppResponse struct {
+	Manifests            []*AppManifest    `protobuf:"bytes,1,rep,name=manifests,proto3" json:"manifests,omitempty"`
+	ValuesYaml           string            `protobuf:"bytes,2,opt,name=values_yaml,json=valuesYaml,proto3" json:"values_yaml,omitempty"`
+	Requests             *ResourceRequests `protobuf:"bytes,3,opt,name=requests,proto3" json:"requests,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *PreviewAppResponse) Reset()         { *m = PreviewAppResponse{} }
+func (m *PreviewAppResponse) String() string { return proto.CompactTextString(m) }
+func (*PreviewAppResponse) ProtoMessage()    {}
+func (*PreviewAppResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_96ee702a13382adb, []int{1}
+}
+
+func (m *PreviewAppResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PreviewAppResponse.Unmarshal(m, b)
+}
+func (m *PreviewAppResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PreviewAppResponse.Marshal(b, m, deterministic)
+}
+func (m *PreviewAppResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PreviewAppResponse.Merge(m, src)
+}
+func (m *PreviewAppResponse) XXX_Size() int {
+	return xxx_messageInfo_PreviewAppResponse.Size(m)
+}
+func (m *PreviewAppResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_PreviewAppResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PreviewAppResponse proto.InternalMessageInfo
+
+func (m *PreviewAppResponse) GetManifests() []*AppManifest {
+	if m != nil {
+		return m.Manifests
+	}
+	return nil
+}
+
+func (m *PreviewAppResponse) GetValuesYaml() string {
+	if m != nil {
+		return m.ValuesYaml
+	}
+	return ""
+}
+
+func (m *PreviewAppResponse) GetRequests() *ResourceRequests {
+	if m != nil {
+		return m.Requests
+	}
+	return nil
+}
+
+// AppManifest is a single kubernetes object rendered from a chart template
+type AppManifest struct {
+	Source               string   `protobuf:"bytes,1,opt,name=source,proto3" json:"source,omitempty"`
+	Kind                 string   `protobuf:"bytes,2,opt,name=kind,proto3" json:"kind,omitempty"`
+	Name                 string   `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Hook                 string   `protobuf:"bytes,4,opt,name=hook,proto3" json:"hook,omitempty"`
+	Content              string   `protobuf:"bytes,5,opt,name=content,proto3" json:"content,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AppManifest) Reset()         { *m = AppManifest{} }
+func (m *AppManifest) String() string { return proto.CompactTextString(m) }
+func (*AppManifest) ProtoMessage()    {}
+func (*AppManifest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_96ee702a13382adb, []int{2}
+}
+
+func (m *AppManifest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AppManifest.Unmarshal(m, b)
+}
+func (m *AppManifest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AppManifest.Marshal(b, m, deterministic)
+}
+func (m *AppManifest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AppManifest.Merge(m, src)
+}
+func (m *AppManifest) XXX_Size() int {
+	return xxx_messageInfo_AppManifest.Size(m)
+}
+func (m *AppManifest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AppManifest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AppManifest proto.InternalMessageInfo
+
+func (m *AppManifest) GetSource() string {
+	if m != nil {
+		return m.Source
+	}
+	return ""
+}
+
+func (m *AppManifest) GetKind() string {
+	if m != nil {
+		return m.Kind
+	}
+	return ""
+}
+
+func (m *AppManifest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *AppManifest) GetHook() string {
+	if m != nil {
+		return m.Hook
+	}
+	return ""
+}
+
+func (m *AppManifest) GetContent() string {
+	if m != nil {
+		return m.Content
+	}
+	return ""
+}
+
+// ResourceRequests is the total cpu (millicores), memory (MiB) and storage (MiB) requested by rendered manifests
+type ResourceRequests struct {
+	Cpu                  uint32   `protobuf:"varint,1,opt,name=cpu,proto3" json:"cpu,omitempty"`
+	Mem                  uint32   `protobuf:"varint,2,opt,name=mem,proto3" json:"mem,omitempty"`
+	Storage              uint32   `protobuf:"varint,3,opt,name=storage,proto3" json:"storage,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ResourceRequests) Reset()         { *m = ResourceRequests{} }
+func (m *ResourceRequests) String() string { return proto.CompactTextString(m) }
+func (*ResourceRequests) ProtoMessage()    {}
+func (*ResourceRequests) Descriptor() ([]byte, []int) {
+	return fileDescriptor_96ee702a13382adb, []int{3}
+}
+
+func (m *ResourceRequests) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ResourceRequests.Unmarshal(m, b)
+}
+func (m *ResourceRequests) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ResourceRequests.Marshal(b, m, deterministic)
+}
+func (m *ResourceRequests) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ResourceRequests.Merge(m, src)
+}
+func (m *ResourceRequests) XXX_Size() int {
+	return xxx_messageInfo_ResourceRequests.Size(m)
+}
+func (m *ResourceRequests) XXX_DiscardUnknown() {
+	xxx_messageInfo_ResourceRequests.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ResourceRequests proto.InternalMessageInfo
+
+func (m *ResourceRequests) GetCpu() uint32 {
+	if m != nil {
+		return m.Cpu
+	}
+	return 0
+}
+
+func (m *ResourceRequests) GetMem() uint32 {
+	if m != nil {
+		return m.Mem
+	}
+	return 0
+}
+
+func (m *ResourceRequests) GetStorage() uint32 {
+	if m != nil {
+		return m.Storage
+	}
+	return 0
+}
+
+// SearchChartsRequest searches query in repos, the configured public repos and the team's user repo if empty
+type SearchChartsRequest struct {
+	Query                string   `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	Repos                []string `protobuf:"bytes,2,rep,name=repos,proto3" json:"repos,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SearchChartsRequest) Reset()         { *m = SearchChartsRequest{} }
+func (m *SearchChartsRequest) String() string { return proto.CompactTextString(m) }
+func (*SearchChartsRequest) ProtoMessage()    {}
+func (*SearchChartsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_96ee702a13382adb, []int{4}
+}
+
+func (m *SearchChartsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SearchChartsRequest.Unmarshal(m, b)
+}
+func (m *SearchChartsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SearchChartsRequest.Marshal(b, m, deterministic)
+}
+func (m *SearchChartsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SearchChartsRequest.Merge(m, src)
+}
+func (m *SearchChartsRequest) XXX_Size() int {
+	return xxx_messageInfo_SearchChartsRequest.Size(m)
+}
+func (m *SearchChartsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SearchChartsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SearchChartsRequest proto.InternalMessageInfo
+
+func (m *SearchChartsRequest) GetQuery() string {
+	if m != nil {
+		return m.Query
+	}
+	return ""
+}
+
+func (m *SearchChartsRequest) GetRepos() []string {
+	if m != nil {
+		return m.Repos
+	}
+	return nil
+}
+
+// SearchChartsResponse lists the matching charts, best match first
+type SearchChartsResponse struct {
+	Results              []*ChartSearchResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *SearchChartsResponse) Reset()         { *m = SearchChartsResponse{} }
+func (m *SearchChartsResponse) String() string { return proto.CompactTextString(m) }
+func (*SearchChartsResponse) ProtoMessage()    {}
+func (*SearchChartsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_96ee702a13382adb, []int{5}
+}
+
+func (m *SearchChartsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SearchChartsResponse.Unmarshal(m, b)
+}
+func (m *SearchChartsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SearchChartsResponse.Marshal(b, m, deterministic)
+}
+func (m *SearchChartsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SearchChartsResponse.Merge(m, src)
+}
+func (m *SearchChartsResponse) XXX_Size() int {
+	return xxx_messageInfo_SearchChartsResponse.Size(m)
+}
+func (m *SearchChartsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SearchChartsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SearchChartsResponse proto.InternalMessageInfo
+
+func (m *SearchChartsResponse) GetResults() []*ChartSearchResult {
+	if m != nil {
+		return m.Results
+	}
+	return nil
+}
+
+// ChartSearchResult is a matching chart with the repo it came from in chart.chart_repo
+type ChartSearchResult struct {
+	Chart                *common.Chart `protobuf:"bytes,1,opt,name=chart,proto3" json:"chart,omitempty"`
+	Score                int32         `protobuf:"varint,2,opt,name=score,proto3" json:"score,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *ChartSearchResult) Reset()         { *m = ChartSearchResult{} }
+func (m *ChartSearchResult) String() string { return proto.CompactTextString(m) }
+func (*ChartSearchResult) ProtoMessage()    {}
+func (*ChartSearchResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_96ee702a13382adb, []int{6}
+}
+
+func (m *ChartSearchResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ChartSearchResult.Unmarshal(m, b)
+}
+func (m *ChartSearchResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ChartSearchResult.Marshal(b, m, deterministic)
+}
+func (m *ChartSearchResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ChartSearchResult.Merge(m, src)
+}
+func (m *ChartSearchResult) XXX_Size() int {
+	return xxx_messageInfo_ChartSearchResult.Size(m)
+}
+func (m *ChartSearchResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_ChartSearchResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ChartSearchResult proto.InternalMessageInfo
+
+func (m *ChartSearchResult) GetChart() *common.Chart {
+	if m != nil {
+		return m.Chart
+	}
+	return nil
+}
+
+func (m *ChartSearchResult) GetScore() int32 {
+	if m != nil {
+		return m.Score
+	}
+	return 0
+}
+
+// ListChartsRequest lists the charts of chart_repo, stable if empty
+type ListChartsRequest struct {
+	ChartRepo            string   `protobuf:"bytes,1,opt,name=chart_repo,json=chartRepo,proto3" json:"chart_repo,omitempty"`
+	IncludePrerelease    bool     `protobuf:"varint,2,opt,name=include_prerelease,json=includePrerelease,proto3" json:"include_prerelease,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListChartsRequest) Reset()         { *m = ListChartsRequest{} }
+func (m *ListChartsRequest) String() string { return proto.CompactTextString(m) }
+func (*ListChartsRequest) ProtoMessage()    {}
+func (*ListChartsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_96ee702a13382adb, []int{7}
+}
+
+func (m *ListChartsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListChartsRequest.Unmarshal(m, b)
+}
+func (m *ListChartsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListChartsRequest.Marshal(b, m, deterministic)
+}
+func (m *ListChartsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListChartsRequest.Merge(m, src)
+}
+func (m *ListChartsRequest) XXX_Size() int {
+	return xxx_messageInfo_ListChartsRequest.Size(m)
+}
+func (m *ListChartsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListChartsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListChartsRequest proto.InternalMessageInfo
+
+func (m *ListChartsRequest) GetChartRepo() string {
+	if m != nil {
+		return m.ChartRepo
+	}
+	return ""
+}
+
+func (m *ListChartsRequest) GetIncludePrerelease() bool {
+	if m != nil {
+		return m.IncludePrerelease
+	}
+	return false
+}
+
+// ListChartsResponse has the latest version of each chart, by name
+type ListChartsResponse struct {
+	Charts               []*common.Chart `protobuf:"bytes,1,rep,name=charts,proto3" json:"charts,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *ListChartsResponse) Reset()         { *m = ListChartsResponse{} }
+func (m *ListChartsResponse) String() string { return proto.CompactTextString(m) }
+func (*ListChartsResponse) ProtoMessage()    {}
+func (*ListChartsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_96ee702a13382adb, []int{8}
+}
+
+func (m *ListChartsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListChartsResponse.Unmarshal(m, b)
+}
+func (m *ListChartsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListChartsResponse.Marshal(b, m, deterministic)
+}
+func (m *ListChartsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListChartsResponse.Merge(m, src)
+}
+func (m *ListChartsResponse) XXX_Size() int {
+	return xxx_messageInfo_ListChartsResponse.Size(m)
+}
+func (m *ListChartsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListChartsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListChartsResponse proto.InternalMessageInfo
+
+func (m *ListChartsResponse) GetCharts() []*common.Chart {
+	if m != nil {
+		return m.Charts
+	}
+	return nil
+}
+
+// GetChartDetailRequest shows show_version of the chart, the latest version if empty
+type GetChartDetailRequest struct {
+	Chart                *common.Chart `protobuf:"bytes,1,opt,name=chart,proto3" json:"chart,omitempty"`
+	ShowVersion          string        `protobuf:"bytes,2,opt,name=show_version,json=showVersion,proto3" json:"show_version,omitempty"`
+	IncludePrerelease    bool          `protobuf:"varint,3,opt,name=include_prerelease,json=includePrerelease,proto3" json:"include_prerelease,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *GetChartDetailRequest) Reset()         { *m = GetChartDetailRequest{} }
+func (m *GetChartDetailRequest) String() string { return proto.CompactTextString(m) }
+func (*GetChartDetailRequest) ProtoMessage()    {}
+func (*GetChartDetailRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_96ee702a13382adb, []int{9}
+}
+
+func (m *GetChartDetailRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetChartDetailRequest.Unmarshal(m, b)
+}
+func (m *GetChartDetailRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetChartDetailRequest.Marshal(b, m, deterministic)
+}
+func (m *GetChartDetailRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetChartDetailRequest.Merge(m, src)
+}
+func (m *GetChartDetailRequest) XXX_Size() int {
+	return xxx_messageInfo_GetChartDetailRequest.Size(m)
+}
+func (m *GetChartDetailRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetChartDetailRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetChartDetailRequest proto.InternalMessageInfo
+
+func (m *GetChartDetailRequest) GetChart() *common.Chart {
+	if m != nil {
+		return m.Chart
+	}
+	return nil
+}
+
+func (m *GetChartDetailRequest) GetShowVersion() string {
+	if m != nil {
+		return m.ShowVersion
+	}
+	return ""
+}
+
+func (m *GetChartDetailRequest) GetIncludePrerelease() bool {
+	if m != nil {
+		return m.IncludePrerelease
+	}
+	return false
+}
+
+// GetChartDetailResponse has the versions of the chart newest first, and the readme and values of the shown version
+type GetChartDetailResponse struct {
+	ChartName            string                       `protobuf:"bytes,1,opt,name=chart_name,json=chartName,proto3" json:"chart_name,omitempty"`
+	ChartRepo            string                       `protobuf:"bytes,2,opt,name=chart_repo,json=chartRepo,proto3" json:"chart_repo,omitempty"`
+	ChartDescription     string                       `protobuf:"bytes,3,opt,name=chart_description,json=chartDescription,proto3" json:"chart_description,omitempty"`
+	ChartVersionDetails  []*common.ChartVersionDetail `protobuf:"bytes,4,rep,name=chart_version_details,json=chartVersionDetails,proto3" json:"chart_version_details,omitempty"`
+	ReadmeMd             string                       `protobuf:"bytes,5,opt,name=readme_md,json=readmeMd,proto3" json:"readme_md,omitempty"`
+	ValuesYaml           string                       `protobuf:"bytes,6,opt,name=values_yaml,json=valuesYaml,proto3" json:"values_yaml,omitempty"`
+	CustomValues         []*common.CustomValue        `protobuf:"bytes,7,rep,name=custom_values,json=customValues,proto3" json:"custom_values,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                     `json:"-"`
+	XXX_unrecognized     []byte                       `json:"-"`
+	XXX_sizecache        int32                        `json:"-"`
+}
+
+func (m *GetChartDetailResponse) Reset()         { *m = GetChartDetailResponse{} }
+func (m *GetChartDetailResponse) String() string { return proto.CompactTextString(m) }
+func (*GetChartDetailResponse) ProtoMessage()    {}
+func (*GetChartDetailResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_96ee702a13382adb, []int{10}
+}
+
+func (m *GetChartDetailResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetChartDetailResponse.Unmarshal(m, b)
+}
+func (m *GetChartDetailResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetChartDetailResponse.Marshal(b, m, deterministic)
+}
+func (m *GetChartDetailResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetChartDetailResponse.Merge(m, src)
+}
+func (m *GetChartDetailResponse) XXX_Size() int {
+	return xxx_messageInfo_GetChartDetailResponse.Size(m)
+}
+func (m *GetChartDetailResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetChartDetailResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetChartDetailResponse proto.InternalMessageInfo
+
+func (m *GetChartDetailResponse) GetChartName() string {
+	if m != nil {
+		return m.ChartName
+	}
+	return ""
+}
+
+func (m *GetChartDetailResponse) GetChartRepo() string {
+	if m != nil {
+		return m.ChartRepo
+	}
+	return ""
+}
+
+func (m *GetChartDetailResponse) GetChartDescription() string {
+	if m != nil {
+		return m.ChartDescription
+	}
+	return ""
+}
+
+func (m *GetChartDetailResponse) GetChartVersionDetails() []*common.ChartVersionDetail {
+	if m != nil {
+		return m.ChartVersionDetails
+	}
+	return nil
+}
+
+func (m *GetChartDetailResponse) GetReadmeMd() string {
+	if m != nil {
+		return m.ReadmeMd
+	}
+	return ""
+}
+
+func (m *GetChartDetailResponse) GetValuesYaml() string {
+	if m != nil {
+		return m.ValuesYaml
+	}
+	return ""
+}
+
+func (m *GetChartDetailResponse) GetCustomValues() []*common.CustomValue {
+	if m != nil {
+		return m.CustomValues
+	}
+	return nil
+}
+
+// AppUpgradesRequest asks for the upgrade of app_id, or of every app of the team if empty
+type AppUpgradesRequest struct {
+	AppId                string   `protobuf:"bytes,1,opt,name=app_id,json=appId,proto3" json:"app_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AppUpgradesRequest) Reset()         { *m = AppUpgradesRequest{} }
+func (m *AppUpgradesRequest) String() string { return proto.CompactTextString(m) }
+func (*AppUpgradesRequest) ProtoMessage()    {}
+func (*AppUpgradesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_96ee702a13382adb, []int{11}
+}
+
+func (m *AppUpgradesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AppUpgradesRequest.Unmarshal(m, b)
+}
+func (m *AppUpgradesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AppUpgradesRequest.Marshal(b, m, deterministic)
+}
+func (m *AppUpgradesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AppUpgradesRequest.Merge(m, src)
+}
+func (m *AppUpgradesRequest) XXX_Size() int {
+	return xxx_messageInfo_AppUpgradesRequest.Size(m)
+}
+func (m *AppUpgradesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AppUpgradesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AppUpgradesRequest proto.InternalMessageInfo
+
+func (m *AppUpgradesRequest) GetAppId() string {
+	if m != nil {
+		return m.AppId
+	}
+	return ""
+}
+
+// AppUpgradesResponse lists the running apps which have a newer chart version
+type AppUpgradesResponse struct {
+	Upgrades             []*AppUpgrade `protobuf:"bytes,1,rep,name=upgrades,proto3" json:"upgrades,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *AppUpgradesResponse) Reset()         { *m = AppUpgradesResponse{} }
+func (m *AppUpgradesResponse) String() string { return proto.CompactTextString(m) }
+func (*AppUpgradesResponse) ProtoMessage()    {}
+func (*AppUpgradesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_96ee702a13382adb, []int{12}
+}
+
+func (m *AppUpgradesResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AppUpgradesResponse.Unmarshal(m, b)
+}
+func (m *AppUpgradesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AppUpgradesResponse.Marshal(b, m, deterministic)
+}
+func (m *AppUpgradesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AppUpgradesResponse.Merge(m, src)
+}
+func (m *AppUpgradesResponse) XXX_Size() int {
+	return xxx_messageInfo_AppUpgradesResponse.Size(m)
+}
+func (m *AppUpgradesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_AppUpgradesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AppUpgradesResponse proto.InternalMessageInfo
+
+func (m *AppUpgradesResponse) GetUpgrades() []*AppUpgrade {
+	if m != nil {
+		return m.Upgrades
+	}
+	return nil
+}
+
+// AppUpgrade is the newest chart version of an app, kind is patch, minor or major
+type AppUpgrade struct {
+	AppId   string `protobuf:"bytes,1,opt,name=app_id,json=appId,proto3" json:"app_id,omitempty"`
+	Kind    string `protobuf:"bytes,2,opt,name=kind,proto3" json:"kind,omitempty"`
+	Version string `protobuf:"bytes,3,opt,name=version,proto3" json:"version,omitempty"`
+	// found_date is when the check found the version, in unix seconds
+	FoundDate            int64    `protobuf:"varint,4,opt,name=found_date,json=foundDate,proto3" json:"found_date,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AppUpgrade) Reset()         { *m = AppUpgrade{} }
+func (m *AppUpgrade) String() string { return proto.CompactTextString(m) }
+func (*AppUpgrade) ProtoMessage()    {}
+func (*AppUpgrade) Descriptor() ([]byte, []int) {
+	return fileDescriptor_96ee702a13382adb, []int{13}
+}
+
+func (m *AppUpgrade) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AppUpgrade.Unmarshal(m, b)
+}
+func (m *AppUpgrade) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AppUpgrade.Marshal(b, m, deterministic)
+}
+func (m *AppUpgrade) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AppUpgrade.Merge(m, src)
+}
+func (m *AppUpgrade) XXX_Size() int {
+	return xxx_messageInfo_AppUpgrade.Size(m)
+}
+func (m *AppUpgrade) XXX_DiscardUnknown() {
+	xxx_messageInfo_AppUpgrade.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AppUpgrade proto.InternalMessageInfo
+
+func (m *AppUpgrade) GetAppId() string {
+	if m != nil {
+		return m.AppId
+	}
+	return ""
+}
+
+func (m *AppUpgrade) GetKind() string {
+	if m != nil {
+		return m.Kind
+	}
+	return ""
+}
+
+func (m *AppUpgrade) GetVersion() string {
+	if m != nil {
+		return m.Version
+	}
+	return ""
+}
+
+func (m *AppUpgrade) GetFoundDate() int64 {
+	if m != nil {
+		return m.FoundDate
+	}
+	return 0
+}
+
+// SetUpgradePolicyRequest sets how far the app is upgraded automatically: "" for never, "patch" or "minor"
+type SetUpgradePolicyRequest struct {
+	AppId                string   `protobuf:"bytes,1,opt,name=app_id,json=appId,proto3" json:"app_id,omitempty"`
+	Policy               string   `protobuf:"bytes,2,opt,name=policy,proto3" json:"policy,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SetUpgradePolicyRequest) Reset()         { *m = SetUpgradePolicyRequest{} }
+func (m *SetUpgradePolicyRequest) String() string { return proto.CompactTextString(m) }
+func (*SetUpgradePolicyRequest) ProtoMessage()    {}
+func (*SetUpgradePolicyRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_96ee702a13382adb, []int{14}
+}
+
+func (m *SetUpgradePolicyRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetUpgradePolicyRequest.Unmarshal(m, b)
+}
+func (m *SetUpgradePolicyRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetUpgradePolicyRequest.Marshal(b, m, deterministic)
+}
+func (m *SetUpgradePolicyRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetUpgradePolicyRequest.Merge(m, src)
+}
+func (m *SetUpgradePolicyRequest) XXX_Size() int {
+	return xxx_messageInfo_SetUpgradePolicyRequest.Size(m)
+}
+func (m *SetUpgradePolicyRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetUpgradePolicyRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetUpgradePolicyRequest proto.InternalMessageInfo
+
+func (m *SetUpgradePolicyRequest) GetAppId() string {
+	if m != nil {
+		return m.AppId
+	}
+	return ""
+}
+
+func (m *SetUpgradePolicyRequest) GetPolicy() string {
+	if m != nil {
+		return m.Policy
+	}
+	return ""
+}
+
+// PromoteChartRequest copies chart_name@chart_ver from chart_repo to target_repo
+type PromoteChartRequest struct {
+	ChartName            string   `protobuf:"bytes,1,opt,name=chart_name,json=chartName,proto3" json:"chart_name,omitempty"`
+	ChartVer             string   `protobuf:"bytes,2,opt,name=chart_ver,json=chartVer,proto3" json:"chart_ver,omitempty"`
+	ChartRepo            string   `protobuf:"bytes,3,opt,name=chart_repo,json=chartRepo,proto3" json:"chart_repo,omitempty"`
+	TargetRepo           string   `protobuf:"bytes,4,opt,name=target_repo,json=targetRepo,proto3" json:"target_repo,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PromoteChartRequest) Reset()         { *m = PromoteChartRequest{} }
+func (m *PromoteChartRequest) String() string { return proto.CompactTextString(m) }
+func (*PromoteChartRequest) ProtoMessage()    {}
+func (*PromoteChartRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_96ee702a13382adb, []int{15}
+}
+
+func (m *PromoteChartRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PromoteChartRequest.Unmarshal(m, b)
+}
+func (m *PromoteChartRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PromoteChartRequest.Marshal(b, m, deterministic)
+}
+func (m *PromoteChartRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PromoteChartRequest.Merge(m, src)
+}
+func (m *PromoteChartRequest) XXX_Size() int {
+	return xxx_messageInfo_PromoteChartRequest.Size(m)
+}
+func (m *PromoteChartRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PromoteChartRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PromoteChartRequest proto.InternalMessageInfo
+
+func (m *PromoteChartRequest) GetChartName() string {
+	if m != nil {
+		return m.ChartName
+	}
+	return ""
+}
+
+func (m *PromoteChartRequest) GetChartVer() string {
+	if m != nil {
+		return m.ChartVer
+	}
+	return ""
+}
+
+func (m *PromoteChartRequest) GetChartRepo() string {
+	if m != nil {
+		return m.ChartRepo
+	}
+	return ""
+}
+
+func (m *PromoteChartRequest) GetTargetRepo() string {
+	if m != nil {
+		return m.TargetRepo
+	}
+	return ""
+}
+
+// DeleteChartVersionRequest deletes chart_name@chart_ver from chart_repo, even if apps use it when force is set
+type DeleteChartVersionRequest struct {
+	ChartName            string   `protobuf:"bytes,1,opt,name=chart_name,json=chartName,proto3" json:"chart_name,omitempty"`
+	ChartRepo            string   `protobuf:"bytes,2,opt,name=chart_repo,json=chartRepo,proto3" json:"chart_repo,omitempty"`
+	ChartVer             string   `protobuf:"bytes,3,opt,name=chart_ver,json=chartVer,proto3" json:"chart_ver,omitempty"`
+	Force                bool     `protobuf:"varint,4,opt,name=force,proto3" json:"force,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteChartVersionRequest) Reset()         { *m = DeleteChartVersionRequest{} }
+func (m *DeleteChartVersionRequest) String() string { return proto.CompactTextString(m) }
+func (*DeleteChartVersionRequest) ProtoMessage()    {}
+func (*DeleteChartVersionRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_96ee702a13382adb, []int{16}
+}
+
+func (m *DeleteChartVersionRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteChartVersionRequest.Unmarshal(m, b)
+}
+func (m *DeleteChartVersionRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteChartVersionRequest.Marshal(b, m, deterministic)
+}
+func (m *DeleteChartVersionRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteChartVersionRequest.Merge(m, src)
+}
+func (m *DeleteChartVersionRequest) XXX_Size() int {
+	return xxx_messageInfo_DeleteChartVersionRequest.Size(m)
+}
+func (m *DeleteChartVersionRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteChartVersionRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteChartVersionRequest proto.InternalMessageInfo
+
+func (m *DeleteChartVersionRequest) GetChartName() string {
+	if m != nil {
+		return m.ChartName
+	}
+	return ""
+}
+
+func (m *DeleteChartVersionRequest) GetChartRepo() string {
+	if m != nil {
+		return m.ChartRepo
+	}
+	return ""
+}
+
+func (m *DeleteChartVersionRequest) GetChartVer() string {
+	if m != nil {
+		return m.ChartVer
+	}
+	return ""
+}
+
+func (m *DeleteChartVersionRequest) GetForce() bool {
+	if m != nil {
+		return m.Force
+	}
+	return false
+}
+
+// ChartChunk is a piece of a streamed chart tarball, the first chunk of a stream also describes the whole tarball
+type ChartChunk struct {
+	ChartName string `protobuf:"bytes,1,opt,name=chart_name,json=chartName,proto3" json:"chart_name,omitempty"`
+	ChartRepo string `protobuf:"bytes,2,opt,name=chart_repo,json=chartRepo,proto3" json:"chart_repo,omitempty"`
+	ChartVer  string `protobuf:"bytes,3,opt,name=chart_ver,json=chartVer,proto3" json:"chart_ver,omitempty"`
+	// size of the whole tarball in bytes, -1 if unknown
+	Size int64 `protobuf:"varint,4,opt,name=size,proto3" json:"size,omitempty"`
+	// hex sha256 of the whole tarball
+	Sha256               string   `protobuf:"bytes,5,opt,name=sha256,proto3" json:"sha256,omitempty"`
+	Data                 []byte   `protobuf:"bytes,6,opt,name=data,proto3" json:"data,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ChartChunk) Reset()         { *m = ChartChunk{} }
+func (m *ChartChunk) String() string { return proto.CompactTextString(m) }
+func (*ChartChunk) ProtoMessage()    {}
+func (*ChartChunk) Descriptor() ([]byte, []int) {
+	return fileDescriptor_96ee702a13382adb, []int{17}
+}
+
+func (m *ChartChunk) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ChartChunk.Unmarshal(m, b)
+}
+func (m *ChartChunk) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ChartChunk.Marshal(b, m, deterministic)
+}
+func (m *ChartChunk) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ChartChunk.Merge(m, src)
+}
+func (m *ChartChunk) XXX_Size() int {
+	return xxx_messageInfo_ChartChunk.Size(m)
+}
+func (m *ChartChunk) XXX_DiscardUnknown() {
+	xxx_messageInfo_ChartChunk.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ChartChunk proto.InternalMessageInfo
+
+func (m *ChartChunk) GetChartName() string {
+	if m != nil {
+		return m.ChartName
+	}
+	return ""
+}
+
+func (m *ChartChunk) GetChartRepo() string {
+	if m != nil {
+		return m.ChartRepo
+	}
+	return ""
+}
+
+func (m *ChartChunk) GetChartVer() string {
+	if m != nil {
+		return m.ChartVer
+	}
+	return ""
+}
+
+func (m *ChartChunk) GetSize() int64 {
+	if m != nil {
+		return m.Size
+	}
+	return 0
+}
+
+func (m *ChartChunk) GetSha256() string {
+	if m != nil {
+		return m.Sha256
+	}
+	return ""
+}
+
+func (m *ChartChunk) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+// DownloadChartStreamRequest names the chart version to download
+type DownloadChartStreamRequest struct {
+	ChartName            string   `protobuf:"bytes,1,opt,name=chart_name,json=chartName,proto3" json:"chart_name,omitempty"`
+	ChartRepo            string   `protobuf:"bytes,2,opt,name=chart_repo,json=chartRepo,proto3" json:"chart_repo,omitempty"`
+	ChartVer             string   `protobuf:"bytes,3,opt,name=chart_ver,json=chartVer,proto3" json:"chart_ver,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DownloadChartStreamRequest) Reset()         { *m = DownloadChartStreamRequest{} }
+func (m *DownloadChartStreamRequest) String() string { return proto.CompactTextString(m) }
+func (*DownloadChartStreamRequest) ProtoMessage()    {}
+func (*DownloadChartStreamRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_96ee702a13382adb, []int{18}
+}
+
+func (m *DownloadChartStreamRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DownloadChartStreamRequest.Unmarshal(m, b)
+}
+func (m *DownloadChartStreamRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DownloadChartStreamRequest.Marshal(b, m, deterministic)
+}
+func (m *DownloadChartStreamRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DownloadChartStreamRequest.Merge(m, src)
+}
+func (m *DownloadChartStreamRequest) XXX_Size() int {
+	return xxx_messageInfo_DownloadChartStreamRequest.Size(m)
+}
+func (m *DownloadChartStreamRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DownloadChartStreamRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DownloadChartStreamRequest proto.InternalMessageInfo
+
+func (m *DownloadChartStreamRequest) GetChartName() string {
+	if m != nil {
+		return m.ChartName
+	}
+	return ""
+}
+
+func (m *DownloadChartStreamRequest) GetChartRepo() string {
+	if m != nil {
+		return m.ChartRepo
+	}
+	return ""
+}
+
+func (m *DownloadChartStreamRequest) GetChartVer() string {
+	if m != nil {
+		return m.ChartVer
+	}
+	return ""
+}
+
+// SetMemberRoleRequest sets the role of a user in the team of the caller: viewer, deployer or admin. Operators
+// name the team to set it in, to give a team its first admin.
+type SetMemberRoleRequest struct {
+	UserId               string   `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Role                 string   `protobuf:"bytes,2,opt,name=role,proto3" json:"role,omitempty"`
+	TeamId               string   `protobuf:"bytes,3,opt,name=team_id,json=teamId,proto3" json:"team_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SetMemberRoleRequest) Reset()         { *m = SetMemberRoleRequest{} }
+func (m *SetMemberRoleRequest) String() string { return proto.CompactTextString(m) }
+func (*SetMemberRoleRequest) ProtoMessage()    {}
+func (*SetMemberRoleRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_96ee702a13382adb, []int{19}
+}
+
+func (m *SetMemberRoleRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetMemberRoleRequest.Unmarshal(m, b)
+}
+func (m *SetMemberRoleRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetMemberRoleRequest.Marshal(b, m, deterministic)
+}
+func (m *SetMemberRoleRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetMemberRoleRequest.Merge(m, src)
+}
+func (m *SetMemberRoleRequest) XXX_Size() int {
+	return xxx_messageInfo_SetMemberRoleRequest.Size(m)
+}
+func (m *SetMemberRoleRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetMemberRoleRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetMemberRoleRequest proto.InternalMessageInfo
+
+func (m *SetMemberRoleRequest) GetUserId() string {
+	if m != nil {
+		return m.UserId
+	}
+	return ""
+}
+
+func (m *SetMemberRoleRequest) GetRole() string {
+	if m != nil {
+		return m.Role
+	}
+	return ""
+}
+
+func (m *SetMemberRoleRequest) GetTeamId() string {
+	if m != nil {
+		return m.TeamId
+	}
+	return ""
+}
+
+// OperatorListRequest filters the records of all teams by cluster and by status names such as APP_RUNNING
+// or NS_FAILED, any cluster or status if empty
+type OperatorListRequest struct {
+	ClusterId            string   `protobuf:"bytes,1,opt,name=cluster_id,json=clusterId,proto3" json:"cluster_id,omitempty"`
+	Status               []string `protobuf:"bytes,2,rep,name=status,proto3" json:"status,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *OperatorListRequest) Reset()         { *m = OperatorListRequest{} }
+func (m *OperatorListRequest) String() string { return proto.CompactTextString(m) }
+func (*OperatorListRequest) ProtoMessage()    {}
+func (*OperatorListRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_96ee702a13382adb, []int{20}
+}
+
+func (m *OperatorListRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_OperatorListRequest.Unmarshal(m, b)
+}
+func (m *OperatorListRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_OperatorListRequest.Marshal(b, m, deterministic)
+}
+func (m *OperatorListRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_OperatorListRequest.Merge(m, src)
+}
+func (m *OperatorListRequest) XXX_Size() int {
+	return xxx_messageInfo_OperatorListRequest.Size(m)
+}
+func (m *OperatorListRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_OperatorListRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_OperatorListRequest proto.InternalMessageInfo
+
+func (m *OperatorListRequest) GetClusterId() string {
+	if m != nil {
+		return m.ClusterId
+	}
+	return ""
+}
+
+func (m *OperatorListRequest) GetStatus() []string {
+	if m != nil {
+		return m.Status
+	}
+	return nil
+}
+
+// OperatorListAppsResponse are the apps of all teams matching the filter
+type OperatorListAppsResponse struct {
+	AppReports           []*common.AppReport `protobuf:"bytes,1,rep,name=app_reports,json=appReports,proto3" json:"app_reports,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
+}
+
+func (m *OperatorListAppsResponse) Reset()         { *m = OperatorListAppsResponse{} }
+func (m *OperatorListAppsResponse) String() string { return proto.CompactTextString(m) }
+func (*OperatorListAppsResponse) ProtoMessage()    {}
+func (*OperatorListAppsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_96ee702a13382adb, []int{21}
+}
+
+func (m *OperatorListAppsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_OperatorListAppsResponse.Unmarshal(m, b)
+}
+func (m *OperatorListAppsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_OperatorListAppsResponse.Marshal(b, m, deterministic)
+}
+func (m *OperatorListAppsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_OperatorListAppsResponse.Merge(m, src)
+}
+func (m *OperatorListAppsResponse) XXX_Size() int {
+	return xxx_messageInfo_OperatorListAppsResponse.Size(m)
+}
+func (m *OperatorListAppsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_OperatorListAppsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_OperatorListAppsResponse proto.InternalMessageInfo
+
+func (m *OperatorListAppsResponse) GetAppReports() []*common.AppReport {
+	if m != nil {
+		return m.AppReports
+	}
+	return nil
+}
+
+// OperatorListNamespacesResponse are the namespaces of all teams matching the filter
+type OperatorListNamespacesResponse struct {
+	NamespaceReports     []*common.NamespaceReport `protobuf:"bytes,1,rep,name=namespace_reports,json=namespaceReports,proto3" json:"namespace_reports,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                  `json:"-"`
+	XXX_unrecognized     []byte                    `json:"-"`
+	XXX_sizecache        int32                     `json:"-"`
+}
+
+func (m *OperatorListNamespacesResponse) Reset()         { *m = OperatorListNamespacesResponse{} }
+func (m *OperatorListNamespacesResponse) String() string { return proto.CompactTextString(m) }
+func (*OperatorListNamespacesResponse) ProtoMessage()    {}
+func (*OperatorListNamespacesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_96ee702a13382adb, []int{22}
+}
+
+func (m *OperatorListNamespacesResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_OperatorListNamespacesResponse.Unmarshal(m, b)
+}
+func (m *OperatorListNamespacesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_OperatorListNamespacesResponse.Marshal(b, m, deterministic)
+}
+func (m *OperatorListNamespacesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_OperatorListNamespacesResponse.Merge(m, src)
+}
+func (m *OperatorListNamespacesResponse) XXX_Size() int {
+	return xxx_messageInfo_OperatorListNamespacesResponse.Size(m)
+}
+func (m *OperatorListNamespacesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_OperatorListNamespacesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_OperatorListNamespacesResponse proto.InternalMessageInfo
+
+func (m *OperatorListNamespacesResponse) GetNamespaceReports() []*common.NamespaceReport {
+	if m != nil {
+		return m.NamespaceReports
+	}
+	return nil
+}
+
+// OperatorSetStatusRequest force sets the status of an app or namespace, kind is "app" or "namespace"
+// and status a status name such as APP_FAILED. The reason is kept in the audit trail.
+type OperatorSetStatusRequest struct {
+	Kind                 string   `protobuf:"bytes,1,opt,name=kind,proto3" json:"kind,omitempty"`
+	Id                   string   `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	Status               string   `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	Reason               string   `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *OperatorSetStatusRequest) Reset()         { *m = OperatorSetStatusRequest{} }
+func (m *OperatorSetStatusRequest) String() string { return proto.CompactTextString(m) }
+func (*OperatorSetStatusRequest) ProtoMessage()    {}
+func (*OperatorSetStatusRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_96ee702a13382adb, []int{23}
+}
+
+func (m *OperatorSetStatusRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_OperatorSetStatusRequest.Unmarshal(m, b)
+}
+func (m *OperatorSetStatusRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_OperatorSetStatusRequest.Marshal(b, m, deterministic)
+}
+func (m *OperatorSetStatusRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_OperatorSetStatusRequest.Merge(m, src)
+}
+func (m *OperatorSetStatusRequest) XXX_Size() int {
+	return xxx_messageInfo_OperatorSetStatusRequest.Size(m)
+}
+func (m *OperatorSetStatusRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_OperatorSetStatusRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_OperatorSetStatusRequest proto.InternalMessageInfo
+
+func (m *OperatorSetStatusRequest) GetKind() string {
+	if m != nil {
+		return m.Kind
+	}
+	return ""
+}
+
+func (m *OperatorSetStatusRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *OperatorSetStatusRequest) GetStatus() string {
+	if m != nil {
+		return m.Status
+	}
+	return ""
+}
+
+func (m *OperatorSetStatusRequest) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+// OperatorRepublishRequest names the app or namespace whose last DCStream is published again, kind is
+// "app" or "namespace"
+type OperatorRepublishRequest struct {
+	Kind                 string   `protobuf:"bytes,1,opt,name=kind,proto3" json:"kind,omitempty"`
+	Id                   string   `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *OperatorRepublishRequest) Reset()         { *m = OperatorRepublishRequest{} }
+func (m *OperatorRepublishRequest) String() string { return proto.CompactTextString(m) }
+func (*OperatorRepublishRequest) ProtoMessage()    {}
+func (*OperatorRepublishRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_96ee702a13382adb, []int{24}
+}
+
+func (m *OperatorRepublishRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_OperatorRepublishRequest.Unmarshal(m, b)
+}
+func (m *OperatorRepublishRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_OperatorRepublishRequest.Marshal(b, m, deterministic)
+}
+func (m *OperatorRepublishRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_OperatorRepublishRequest.Merge(m, src)
+}
+func (m *OperatorRepublishRequest) XXX_Size() int {
+	return xxx_messageInfo_OperatorRepublishRequest.Size(m)
+}
+func (m *OperatorRepublishRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_OperatorRepublishRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_OperatorRepublishRequest proto.InternalMessageInfo
+
+func (m *OperatorRepublishRequest) GetKind() string {
+	if m != nil {
+		return m.Kind
+	}
+	return ""
+}
+
+func (m *OperatorRepublishRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+// DrainClusterRequest drains a cluster into target_cluster_id, or into the available cluster with the fewest
+// running namespaces if empty. resume puts a drained cluster back in service instead.
+type DrainClusterRequest struct {
+	ClusterId            string   `protobuf:"bytes,1,opt,name=cluster_id,json=clusterId,proto3" json:"cluster_id,omitempty"`
+	TargetClusterId      string   `protobuf:"bytes,2,opt,name=target_cluster_id,json=targetClusterId,proto3" json:"target_cluster_id,omitempty"`
+	Resume               bool     `protobuf:"varint,3,opt,name=resume,proto3" json:"resume,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DrainClusterRequest) Reset()         { *m = DrainClusterRequest{} }
+func (m *DrainClusterRequest) String() string { return proto.CompactTextString(m) }
+func (*DrainClusterRequest) ProtoMessage()    {}
+func (*DrainClusterRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_96ee702a13382adb, []int{25}
+}
+
+func (m *DrainClusterRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DrainClusterRequest.Unmarshal(m, b)
+}
+func (m *DrainClusterRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DrainClusterRequest.Marshal(b, m, deterministic)
+}
+func (m *DrainClusterRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DrainClusterRequest.Merge(m, src)
+}
+func (m *DrainClusterRequest) XXX_Size() int {
+	return xxx_messageInfo_DrainClusterRequest.Size(m)
+}
+func (m *DrainClusterRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DrainClusterRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DrainClusterRequest proto.InternalMessageInfo
+
+func (m *DrainClusterRequest) GetClusterId() string {
+	if m != nil {
+		return m.ClusterId
+	}
+	return ""
+}
+
+func (m *DrainClusterRequest) GetTargetClusterId() string {
+	if m != nil {
+		return m.TargetClusterId
+	}
+	return ""
+}
+
+func (m *DrainClusterRequest) GetResume() bool {
+	if m != nil {
+		return m.Resume
+	}
+	return false
+}
+
+// DrainClusterResponse tells where the namespaces and apps of the cluster are recreated, namespaces and apps
+// are the ids of the replacements created by this call. Apps are recreated once their namespace runs there.
+type DrainClusterResponse struct {
+	TargetClusterId      string   `protobuf:"bytes,1,opt,name=target_cluster_id,json=targetClusterId,proto3" json:"target_cluster_id,omitempty"`
+	Namespaces           []string `protobuf:"bytes,2,rep,name=namespaces,proto3" json:"namespaces,omitempty"`
+	Apps                 []string `protobuf:"bytes,3,rep,name=apps,proto3" json:"apps,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DrainClusterResponse) Reset()         { *m = DrainClusterResponse{} }
+func (m *DrainClusterResponse) String() string { return proto.CompactTextString(m) }
+func (*DrainClusterResponse) ProtoMessage()    {}
+func (*DrainClusterResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_96ee702a13382adb, []int{26}
+}
+
+func (m *DrainClusterResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DrainClusterResponse.Unmarshal(m, b)
+}
+func (m *DrainClusterResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DrainClusterResponse.Marshal(b, m, deterministic)
+}
+func (m *DrainClusterResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DrainClusterResponse.Merge(m, src)
+}
+func (m *DrainClusterResponse) XXX_Size() int {
+	return xxx_messageInfo_DrainClusterResponse.Size(m)
+}
+func (m *DrainClusterResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DrainClusterResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DrainClusterResponse proto.InternalMessageInfo
+
+func (m *DrainClusterResponse) GetTargetClusterId() string {
+	if m != nil {
+		return m.TargetClusterId
+	}
+	return ""
+}
+
+func (m *DrainClusterResponse) GetNamespaces() []string {
+	if m != nil {
+		return m.Namespaces
+	}
+	return nil
+}
+
+func (m *DrainClusterResponse) GetApps() []string {
+	if m != nil {
+		return m.Apps
+	}
+	return nil
+}
+
+// UsageHistoryRequest asks for the usage of a namespace, or of the team of the caller if ns_id is empty, between
+// from and to in unix seconds. to defaults to now and from to a day before to.
+type UsageHistoryRequest struct {
+	NsId                 string   `protobuf:"bytes,1,opt,name=ns_id,json=nsId,proto3" json:"ns_id,omitempty"`
+	From                 int64    `protobuf:"varint,2,opt,name=from,proto3" json:"from,omitempty"`
+	To                   int64    `protobuf:"varint,3,opt,name=to,proto3" json:"to,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UsageHistoryRequest) Reset()         { *m = UsageHistoryRequest{} }
+func (m *UsageHistoryRequest) String() string { return proto.CompactTextString(m) }
+func (*UsageHistoryRequest) ProtoMessage()    {}
+func (*UsageHistoryRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_96ee702a13382adb, []int{27}
+}
+
+func (m *UsageHistoryRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UsageHistoryRequest.Unmarshal(m, b)
+}
+func (m *UsageHistoryRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UsageHistoryRequest.Marshal(b, m, deterministic)
+}
+func (m *UsageHistoryRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UsageHistoryRequest.Merge(m, src)
+}
+func (m *UsageHistoryRequest) XXX_Size() int {
+	return xxx_messageInfo_UsageHistoryRequest.Size(m)
+}
+func (m *UsageHistoryRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_UsageHistoryRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UsageHistoryRequest proto.InternalMessageInfo
+
+func (m *UsageHistoryRequest) GetNsId() string {
+	if m != nil {
+		return m.NsId
+	}
+	return ""
+}
+
+func (m *UsageHistoryRequest) GetFrom() int64 {
+	if m != nil {
+		return m.From
+	}
+	return 0
+}
+
+func (m *UsageHistoryRequest) GetTo() int64 {
+	if m != nil {
+		return m.To
+	}
+	return 0
+}
+
+// ClusterUsageHistoryRequest asks for the usage of a cluster between from and to, as UsageHistoryRequest does
+type ClusterUsageHistoryRequest struct {
+	ClusterId            string   `protobuf:"bytes,1,opt,name=cluster_id,json=clusterId,proto3" json:"cluster_id,omitempty"`
+	From                 int64    `protobuf:"varint,2,opt,name=from,proto3" json:"from,omitempty"`
+	To                   int64    `protobuf:"varint,3,opt,name=to,proto3" json:"to,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ClusterUsageHistoryRequest) Reset()         { *m = ClusterUsageHistoryRequest{} }
+func (m *ClusterUsageHistoryRequest) String() string { return proto.CompactTextString(m) }
+func (*ClusterUsageHistoryRequest) ProtoMessage()    {}
+func (*ClusterUsageHistoryRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_96ee702a13382adb, []int{28}
+}
+
+func (m *ClusterUsageHistoryRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ClusterUsageHistoryRequest.Unmarshal(m, b)
+}
+func (m *ClusterUsageHistoryRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ClusterUsageHistoryRequest.Marshal(b, m, deterministic)
+}
+func (m *ClusterUsageHistoryRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ClusterUsageHistoryRequest.Merge(m, src)
+}
+func (m *ClusterUsageHistoryRequest) XXX_Size() int {
+	return xxx_messageInfo_ClusterUsageHistoryRequest.Size(m)
+}
+func (m *ClusterUsageHistoryRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ClusterUsageHistoryRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ClusterUsageHistoryRequest proto.InternalMessageInfo
+
+func (m *ClusterUsageHistoryRequest) GetClusterId() string {
+	if m != nil {
+		return m.ClusterId
+	}
+	return ""
+}
+
+func (m *ClusterUsageHistoryRequest) GetFrom() int64 {
+	if m != nil {
+		return m.From
+	}
+	return 0
+}
+
+func (m *ClusterUsageHistoryRequest) GetTo() int64 {
+	if m != nil {
+		return m.To
+	}
+	return 0
+}
+
+// UsageHistoryResponse is the average usage over time, at the finest resolution kept for the range
+type UsageHistoryResponse struct {
+	Points               []*UsagePoint `protobuf:"bytes,1,rep,name=points,proto3" json:"points,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *UsageHistoryResponse) Reset()         { *m = UsageHistoryResponse{} }
+func (m *UsageHistoryResponse) String() string { return proto.CompactTextString(m) }
+func (*UsageHistoryResponse) ProtoMessage()    {}
+func (*UsageHistoryResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_96ee702a13382adb, []int{29}
+}
+
+func (m *UsageHistoryResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UsageHistoryResponse.Unmarshal(m, b)
+}
+func (m *UsageHistoryResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UsageHistoryResponse.Marshal(b, m, deterministic)
+}
+func (m *UsageHistoryResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UsageHistoryResponse.Merge(m, src)
+}
+func (m *UsageHistoryResponse) XXX_Size() int {
+	return xxx_messageInfo_UsageHistoryResponse.Size(m)
+}
+func (m *UsageHistoryResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_UsageHistoryResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UsageHistoryResponse proto.InternalMessageInfo
+
+func (m *UsageHistoryResponse) GetPoints() []*UsagePoint {
+	if m != nil {
+		return m.Points
+	}
+	return nil
+}
+
+// UsagePoint is the average usage at time in unix seconds, summed over the namespaces queried, in the units of
+// the namespace limits
+type UsagePoint struct {
+	Time                 int64    `protobuf:"varint,1,opt,name=time,proto3" json:"time,omitempty"`
+	Cpu                  float64  `protobuf:"fixed64,2,opt,name=cpu,proto3" json:"cpu,omitempty"`
+	Mem                  float64  `protobuf:"fixed64,3,opt,name=mem,proto3" json:"mem,omitempty"`
+	Storage              float64  `protobuf:"fixed64,4,opt,name=storage,proto3" json:"storage,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UsagePoint) Reset()         { *m = UsagePoint{} }
+func (m *UsagePoint) String() string { return proto.CompactTextString(m) }
+func (*UsagePoint) ProtoMessage()    {}
+func (*UsagePoint) Descriptor() ([]byte, []int) {
+	return fileDescriptor_96ee702a13382adb, []int{30}
+}
+
+func (m *UsagePoint) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UsagePoint.Unmarshal(m, b)
+}
+func (m *UsagePoint) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UsagePoint.Marshal(b, m, deterministic)
+}
+func (m *UsagePoint) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UsagePoint.Merge(m, src)
+}
+func (m *UsagePoint) XXX_Size() int {
+	return xxx_messageInfo_UsagePoint.Size(m)
+}
+func (m *UsagePoint) XXX_DiscardUnknown() {
+	xxx_messageInfo_UsagePoint.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UsagePoint proto.InternalMessageInfo
+
+func (m *UsagePoint) GetTime() int64 {
+	if m != nil {
+		return m.Time
+	}
+	return 0
+}
+
+func (m *UsagePoint) GetCpu() float64 {
+	if m != nil {
+		return m.Cpu
+	}
+	return 0
+}
+
+func (m *UsagePoint) GetMem() float64 {
+	if m != nil {
+		return m.Mem
+	}
+	return 0
+}
+
+func (m *UsagePoint) GetStorage() float64 {
+	if m != nil {
+		return m.Storage
+	}
+	return 0
+}
+
+func init() {
+	proto.RegisterType((*PreviewAppRequest)(nil), "appmgrext.PreviewAppRequest")
+	proto.RegisterType((*PreviewAppResponse)(nil), "appmgrext.PreviewAppResponse")
+	proto.RegisterType((*AppManifest)(nil), "appmgrext.AppManifest")
+	proto.RegisterType((*ResourceRequests)(nil), "appmgrext.ResourceRequests")
+	proto.RegisterType((*SearchChartsRequest)(nil), "appmgrext.SearchChartsRequest")
+	proto.RegisterType((*SearchChartsResponse)(nil), "appmgrext.SearchChartsResponse")
+	proto.RegisterType((*ChartSearchResult)(nil), "appmgrext.ChartSearchResult")
+	proto.RegisterType((*ListChartsRequest)(nil), "appmgrext.ListChartsRequest")
+	proto.RegisterType((*ListChartsResponse)(nil), "appmgrext.ListChartsResponse")
+	proto.RegisterType((*GetChartDetailRequest)(nil), "appmgrext.GetChartDetailRequest")
+	proto.RegisterType((*GetChartDetailResponse)(nil), "appmgrext.GetChartDetailResponse")
+	proto.RegisterType((*AppUpgradesRequest)(nil), "appmgrext.AppUpgradesRequest")
+	proto.RegisterType((*AppUpgradesResponse)(nil), "appmgrext.AppUpgradesResponse")
+	proto.RegisterType((*AppUpgrade)(nil), "appmgrext.AppUpgrade")
+	proto.RegisterType((*SetUpgradePolicyRequest)(nil), "appmgrext.SetUpgradePolicyRequest")
+	proto.RegisterType((*PromoteChartRequest)(nil), "appmgrext.PromoteChartRequest")
+	proto.RegisterType((*DeleteChartVersionRequest)(nil), "appmgrext.DeleteChartVersionRequest")
+	proto.RegisterType((*ChartChunk)(nil), "appmgrext.ChartChunk")
+	proto.RegisterType((*DownloadChartStreamRequest)(nil), "appmgrext.DownloadChartStreamRequest")
+	proto.RegisterType((*SetMemberRoleRequest)(nil), "appmgrext.SetMemberRoleRequest")
+	proto.RegisterType((*OperatorListRequest)(nil), "appmgrext.OperatorListRequest")
+	proto.RegisterType((*OperatorListAppsResponse)(nil), "appmgrext.OperatorListAppsResponse")
+	proto.RegisterType((*OperatorListNamespacesResponse)(nil), "appmgrext.OperatorListNamespacesResponse")
+	proto.RegisterType((*OperatorSetStatusRequest)(nil), "appmgrext.OperatorSetStatusRequest")
+	proto.RegisterType((*OperatorRepublishRequest)(nil), "appmgrext.OperatorRepublishRequest")
+	proto.RegisterType((*DrainClusterRequest)(nil), "appmgrext.DrainClusterRequest")
+	proto.RegisterType((*DrainClusterResponse)(nil), "appmgrext.DrainClusterResponse")
+	proto.RegisterType((*UsageHistoryRequest)(nil), "appmgrext.UsageHistoryRequest")
+	proto.RegisterType((*ClusterUsageHistoryRequest)(nil), "appmgrext.ClusterUsageHistoryRequest")
+	proto.RegisterType((*UsageHistoryResponse)(nil), "appmgrext.UsageHistoryResponse")
+	proto.RegisterType((*UsagePoint)(nil), "appmgrext.UsagePoint")
+}
+
+func init() {
+	proto.RegisterFile("appmgrext/appmgrext.proto", fileDescriptor_96ee702a13382adb)
+}
+
+var fileDescriptor_96ee702a13382adb = []byte{
+	// 1626 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x58, 0xeb, 0x4e, 0x1b, 0xc7,
+	0x17, 0xc7, 0x18, 0x0c, 0x3e, 0x86, 0xfc, 0xf1, 0x18, 0x88, 0x71, 0xfe, 0x5c, 0x32, 0xff, 0x7f,
+	0xa5, 0xa4, 0x11, 0xd0, 0xd2, 0x36, 0xad, 0xd4, 0x2a, 0x2a, 0x05, 0x94, 0xa4, 0x09, 0x84, 0x2c,
+	0xb9, 0x28, 0x55, 0x55, 0x6b, 0x58, 0x0f, 0x78, 0x85, 0x77, 0x77, 0x32, 0x33, 0x0b, 0xa1, 0x7d,
+	0x85, 0x7e, 0xa9, 0xd4, 0x47, 0xa8, 0xd4, 0x27, 0xe9, 0x33, 0xf4, 0x4b, 0x1f, 0xa6, 0x9a, 0xcb,
+	0xae, 0x77, 0xd7, 0xbb, 0x09, 0xf9, 0x90, 0x4f, 0xcc, 0xb9, 0xec, 0xb9, 0xcd, 0x99, 0xf3, 0x3b,
+	0x06, 0x96, 0x08, 0x63, 0xfe, 0x29, 0xa7, 0x6f, 0xe4, 0x66, 0x72, 0xda, 0x60, 0x3c, 0x94, 0x21,
+	0xaa, 0x27, 0x8c, 0x4e, 0xcb, 0x0d, 0x7d, 0x3f, 0x0c, 0x36, 0xcd, 0x1f, 0x23, 0xc7, 0x7f, 0x55,
+	0xa0, 0x79, 0xc8, 0xe9, 0xb9, 0x47, 0x2f, 0xb6, 0x19, 0x73, 0xe8, 0xeb, 0x88, 0x0a, 0x89, 0x96,
+	0x60, 0x9a, 0x30, 0xd6, 0x0d, 0x88, 0x4f, 0xdb, 0x95, 0xb5, 0xca, 0xad, 0xba, 0x33, 0x45, 0x18,
+	0x3b, 0x20, 0x3e, 0x45, 0xd7, 0x61, 0x2a, 0x10, 0x46, 0x32, 0xae, 0x25, 0xb5, 0x40, 0x68, 0xc1,
+	0x37, 0x30, 0xe3, 0xf6, 0x09, 0x97, 0xdd, 0x1e, 0x95, 0xc4, 0x1b, 0xb4, 0xab, 0x6b, 0x95, 0x5b,
+	0x8d, 0xad, 0xa5, 0x8d, 0xb4, 0xbb, 0x8d, 0x1d, 0xa5, 0xb1, 0xab, 0x15, 0x9c, 0x86, 0x3b, 0x24,
+	0xd0, 0x3d, 0x98, 0x75, 0x23, 0x21, 0x43, 0xbf, 0x7b, 0x4e, 0x06, 0x11, 0x15, 0xed, 0x89, 0xb5,
+	0x6a, 0xc1, 0xe7, 0x5a, 0xe5, 0x85, 0xd2, 0x70, 0x66, 0xdc, 0x21, 0x21, 0xf0, 0x1f, 0x15, 0x40,
+	0xe9, 0x3c, 0x04, 0x0b, 0x03, 0x41, 0xd1, 0xe7, 0x50, 0xf7, 0x49, 0xe0, 0x9d, 0x50, 0x21, 0x45,
+	0xbb, 0xa2, 0x4d, 0x2e, 0x6e, 0x0c, 0x6b, 0xb4, 0xcd, 0xd8, 0xbe, 0x15, 0x3b, 0x43, 0x45, 0xb4,
+	0x0a, 0x0d, 0x13, 0x45, 0xf7, 0x92, 0xf8, 0x03, 0x9b, 0x27, 0x18, 0xd6, 0x2b, 0xe2, 0x0f, 0xd0,
+	0x97, 0x30, 0xcd, 0x4d, 0xa9, 0x84, 0xcd, 0xf3, 0x46, 0xca, 0xaa, 0x43, 0x45, 0x18, 0x71, 0x97,
+	0xda, 0x6a, 0x0a, 0x27, 0x51, 0xc6, 0xbf, 0x40, 0x23, 0xe5, 0x13, 0x2d, 0x42, 0xcd, 0xa8, 0xda,
+	0x2a, 0x5b, 0x0a, 0x21, 0x98, 0x38, 0xf3, 0x82, 0x9e, 0xf5, 0xac, 0xcf, 0x8a, 0xa7, 0xab, 0x5e,
+	0x35, 0x3c, 0x75, 0x56, 0xbc, 0x7e, 0x18, 0x9e, 0xb5, 0x27, 0x0c, 0x4f, 0x9d, 0x51, 0x1b, 0xa6,
+	0xdc, 0x30, 0x90, 0x34, 0x90, 0xed, 0x49, 0x73, 0x75, 0x96, 0xc4, 0x87, 0x30, 0x97, 0x0f, 0x0d,
+	0xcd, 0x41, 0xd5, 0x65, 0x91, 0x76, 0x3f, 0xeb, 0xa8, 0xa3, 0xe2, 0xf8, 0xd4, 0xd7, 0xae, 0x67,
+	0x1d, 0x75, 0x54, 0x16, 0x85, 0x0c, 0x39, 0x39, 0x35, 0xce, 0x67, 0x9d, 0x98, 0xc4, 0xdb, 0xd0,
+	0x3a, 0xa2, 0x84, 0xbb, 0x7d, 0x7d, 0xaf, 0x22, 0x6e, 0x9f, 0x79, 0x98, 0x7c, 0x1d, 0x51, 0x7e,
+	0x69, 0xb3, 0x32, 0x84, 0xe2, 0x72, 0xca, 0x42, 0xd1, 0x1e, 0x5f, 0xab, 0x2a, 0xae, 0x26, 0xf0,
+	0x01, 0xcc, 0x67, 0x4d, 0xd8, 0x9b, 0xbb, 0x0b, 0x53, 0x9c, 0x8a, 0x68, 0x90, 0xdc, 0xdb, 0x7f,
+	0x53, 0x15, 0xd6, 0xba, 0xe6, 0x33, 0x47, 0x2b, 0x39, 0xb1, 0x32, 0x7e, 0x06, 0xcd, 0x11, 0x29,
+	0xba, 0x0d, 0x93, 0xba, 0xd9, 0x74, 0x40, 0x8d, 0xad, 0x56, 0x41, 0x53, 0x3a, 0x46, 0x43, 0x45,
+	0x29, 0xdc, 0x90, 0x9b, 0xee, 0x9e, 0x74, 0x0c, 0x81, 0x09, 0x34, 0x1f, 0x7b, 0x42, 0x66, 0xd3,
+	0x5c, 0x06, 0x30, 0x1d, 0xaf, 0x32, 0xb1, 0xb9, 0xd6, 0x35, 0xc7, 0xa1, 0x2c, 0x44, 0xeb, 0x80,
+	0xbc, 0xc0, 0x1d, 0x44, 0x3d, 0xda, 0x65, 0x9c, 0x72, 0x3a, 0xa0, 0x44, 0x18, 0xb3, 0xd3, 0x4e,
+	0xd3, 0x4a, 0x0e, 0x13, 0x01, 0xde, 0x06, 0x94, 0x76, 0x61, 0xcb, 0x70, 0x07, 0x6a, 0xda, 0x62,
+	0x5c, 0x85, 0xc2, 0xd0, 0xad, 0x0a, 0xfe, 0xbd, 0x02, 0x0b, 0xf7, 0xa9, 0x4c, 0x3f, 0x32, 0x1b,
+	0xea, 0x7b, 0x14, 0xe0, 0x26, 0xcc, 0x88, 0x7e, 0x78, 0xd1, 0x3d, 0xa7, 0x5c, 0x78, 0x61, 0x60,
+	0x7b, 0xb0, 0xa1, 0x78, 0x2f, 0x0c, 0xab, 0x24, 0xb3, 0x6a, 0x59, 0x66, 0x7f, 0x8f, 0xc3, 0x62,
+	0x3e, 0x2c, 0x9b, 0x5e, 0x52, 0xc2, 0xd4, 0xa8, 0x31, 0x25, 0xd4, 0x33, 0x25, 0x5b, 0xe1, 0xf1,
+	0x7c, 0x85, 0xef, 0x40, 0x33, 0x1e, 0x39, 0xc2, 0xe5, 0x1e, 0x93, 0x2a, 0x5e, 0xf3, 0x3e, 0xe6,
+	0xec, 0x70, 0x49, 0xf8, 0xe8, 0x19, 0x2c, 0x18, 0x65, 0x9b, 0x98, 0x9d, 0x53, 0xf1, 0xa4, 0x59,
+	0x2b, 0x28, 0x89, 0xcd, 0xd7, 0xc6, 0xdc, 0x72, 0x47, 0x78, 0x02, 0xdd, 0x80, 0x3a, 0xa7, 0xa4,
+	0xe7, 0xd3, 0xae, 0xdf, 0xb3, 0xef, 0x6d, 0xda, 0x30, 0xf6, 0x7b, 0xf9, 0x39, 0x52, 0x1b, 0x99,
+	0x23, 0x23, 0x53, 0x6f, 0xea, 0xfd, 0xa6, 0xde, 0x1d, 0x40, 0xdb, 0x8c, 0x3d, 0x67, 0xa7, 0x9c,
+	0xf4, 0x68, 0xd2, 0x97, 0x0b, 0x50, 0x53, 0xd3, 0xdb, 0xeb, 0xc5, 0xef, 0x8f, 0x30, 0xf6, 0xb0,
+	0x87, 0x1f, 0x40, 0x2b, 0xa3, 0x6c, 0xaf, 0xe0, 0x53, 0x98, 0x8e, 0x2c, 0xcf, 0xf6, 0xd8, 0x42,
+	0x76, 0x42, 0xda, 0x2f, 0x9c, 0x44, 0x0d, 0x33, 0x80, 0x21, 0xbf, 0xc4, 0x5d, 0xe1, 0x0c, 0x6b,
+	0xc3, 0x54, 0xdc, 0x56, 0xe6, 0x9a, 0x62, 0x52, 0xdd, 0xf4, 0x49, 0x18, 0x05, 0xbd, 0x6e, 0x8f,
+	0x48, 0xaa, 0xe7, 0x59, 0xd5, 0xa9, 0x6b, 0xce, 0x2e, 0x91, 0x14, 0x3f, 0x80, 0xeb, 0x47, 0x54,
+	0x5a, 0x8f, 0x87, 0xe1, 0xc0, 0x73, 0x2f, 0xdf, 0x9e, 0xad, 0x1a, 0xad, 0x4c, 0xeb, 0xc5, 0x30,
+	0x65, 0x28, 0xfc, 0x5b, 0x05, 0x5a, 0x87, 0x3c, 0xf4, 0x43, 0x49, 0x4d, 0xdb, 0xe7, 0x1f, 0x73,
+	0x71, 0x27, 0xde, 0x80, 0x7a, 0xd2, 0x3d, 0xd6, 0xe2, 0x74, 0xdc, 0x0f, 0xb9, 0x36, 0xad, 0xe6,
+	0xdb, 0x74, 0x15, 0x1a, 0x92, 0xf0, 0x53, 0x6a, 0xe5, 0x66, 0x58, 0x83, 0x61, 0x29, 0x05, 0xfc,
+	0x6b, 0x05, 0x96, 0x76, 0xe9, 0x80, 0xda, 0x90, 0x6c, 0x8b, 0x5d, 0x31, 0xb2, 0x77, 0xbc, 0x91,
+	0x4c, 0xe0, 0xd5, 0x5c, 0xe0, 0xf3, 0x30, 0x79, 0x12, 0x2a, 0xf8, 0x99, 0xd0, 0x6f, 0xd7, 0x10,
+	0xf8, 0xcf, 0x0a, 0x80, 0x0e, 0x64, 0xa7, 0x1f, 0x05, 0x67, 0x1f, 0xd2, 0x3f, 0x82, 0x09, 0xe1,
+	0xfd, 0x1c, 0xdf, 0xb7, 0x3e, 0x6b, 0x4c, 0xec, 0x93, 0xad, 0x2f, 0xee, 0xda, 0xe7, 0x64, 0x29,
+	0xa5, 0xdb, 0x23, 0x92, 0xe8, 0x57, 0x34, 0xe3, 0xe8, 0x33, 0xbe, 0x80, 0xce, 0x6e, 0x78, 0x11,
+	0x0c, 0x42, 0xd2, 0x33, 0x43, 0x5f, 0x72, 0x4a, 0xfc, 0x0f, 0x5f, 0x38, 0xfc, 0xa3, 0x42, 0x2d,
+	0xb9, 0x4f, 0xfd, 0x63, 0xca, 0x9d, 0x70, 0x10, 0xe3, 0xa9, 0xda, 0x8e, 0x22, 0x41, 0xf9, 0xb0,
+	0x1b, 0x6b, 0x8a, 0x34, 0xaf, 0x81, 0x87, 0x83, 0x78, 0x67, 0xd2, 0x67, 0xa5, 0x2c, 0x29, 0xf1,
+	0x95, 0xb2, 0xb1, 0x5f, 0x53, 0xe4, 0xc3, 0x1e, 0x7e, 0x0c, 0xad, 0x27, 0x8c, 0x72, 0x22, 0x43,
+	0xae, 0x20, 0x21, 0x9d, 0xcf, 0x20, 0x12, 0x32, 0x6d, 0xbf, 0x6e, 0x39, 0xa6, 0xe3, 0x85, 0x24,
+	0x32, 0x8a, 0x01, 0xd6, 0x52, 0xf8, 0x19, 0xb4, 0xd3, 0xd6, 0xb6, 0x19, 0x1b, 0x3e, 0xfe, 0xaf,
+	0xa0, 0xa1, 0x1e, 0x8f, 0xaa, 0xc0, 0x10, 0x63, 0xae, 0x67, 0xc7, 0x8f, 0xde, 0xa7, 0x94, 0xdc,
+	0x01, 0x12, 0x1f, 0x05, 0x1e, 0xc0, 0x4a, 0xda, 0xaa, 0xaa, 0xa8, 0x60, 0xc4, 0x4d, 0x0d, 0x96,
+	0xef, 0xa1, 0x19, 0xc4, 0xdc, 0x9c, 0x87, 0xe5, 0xac, 0x87, 0xe4, 0x63, 0xeb, 0x67, 0x2e, 0xc8,
+	0x32, 0x04, 0x0e, 0x86, 0x39, 0x1c, 0x51, 0x79, 0xa4, 0x13, 0x8b, 0xcb, 0x12, 0x0f, 0x9a, 0x4a,
+	0x6a, 0xd0, 0x5c, 0x83, 0x71, 0x2f, 0x1e, 0x3d, 0xe3, 0x5e, 0xba, 0x36, 0xb6, 0xd2, 0x86, 0x52,
+	0x7c, 0x4e, 0x89, 0x08, 0x03, 0xfb, 0x2a, 0x2d, 0x85, 0xef, 0x0d, 0xfd, 0x39, 0x94, 0x45, 0xc7,
+	0x03, 0x4f, 0xf4, 0xdf, 0xc3, 0x1f, 0x7e, 0x03, 0xad, 0x5d, 0x4e, 0xbc, 0x60, 0xc7, 0xdc, 0xce,
+	0x15, 0x6f, 0xf0, 0x63, 0x68, 0xda, 0x41, 0x91, 0xd2, 0x32, 0x46, 0xff, 0x63, 0x04, 0x3b, 0xe9,
+	0xdb, 0x56, 0x2b, 0x8f, 0x1f, 0xe3, 0xae, 0xa5, 0xf0, 0x39, 0xcc, 0x67, 0x3d, 0xdb, 0xdb, 0x28,
+	0xb4, 0x5d, 0x29, 0xb6, 0xbd, 0x02, 0x90, 0xdc, 0x40, 0xdc, 0x4d, 0x29, 0x8e, 0xaa, 0x00, 0x61,
+	0x4c, 0xd5, 0x52, 0x49, 0xf4, 0x19, 0x1f, 0x40, 0xeb, 0xb9, 0x20, 0xa7, 0xf4, 0x81, 0x27, 0x64,
+	0xc8, 0x93, 0xe9, 0xdc, 0x82, 0xc9, 0x40, 0x0c, 0x5d, 0x4d, 0x04, 0xc2, 0x3c, 0x86, 0x13, 0x1e,
+	0x9a, 0x1d, 0xb3, 0xea, 0xe8, 0xb3, 0xaa, 0xa0, 0x34, 0xb3, 0xb3, 0xea, 0x8c, 0xcb, 0x10, 0x77,
+	0xa1, 0x63, 0x03, 0x2a, 0x32, 0xfb, 0x8e, 0x42, 0x5e, 0xc5, 0xc1, 0x1e, 0xcc, 0x67, 0x2d, 0xdb,
+	0x42, 0xad, 0x2b, 0xe0, 0xf0, 0x02, 0x59, 0x84, 0x86, 0xfa, 0x83, 0x43, 0x25, 0x75, 0xac, 0x12,
+	0xfe, 0x09, 0x60, 0xc8, 0x55, 0x8e, 0xa5, 0x67, 0x87, 0x4d, 0xd5, 0xd1, 0xe7, 0x78, 0xc5, 0x56,
+	0xb1, 0x54, 0x32, 0x2b, 0x76, 0xd5, 0x70, 0x72, 0x2b, 0xf6, 0x84, 0xe6, 0xc6, 0xe4, 0xd6, 0x3f,
+	0x0d, 0xa8, 0xab, 0x9f, 0x0c, 0xa7, 0x7c, 0xef, 0x8d, 0x44, 0x8f, 0x00, 0x86, 0xbf, 0x72, 0x50,
+	0x7a, 0x25, 0x1e, 0xf9, 0x11, 0xd7, 0x59, 0x2e, 0x91, 0x9a, 0x3c, 0xf1, 0x18, 0x7a, 0x0a, 0x33,
+	0xe9, 0xd5, 0x1b, 0xad, 0xa4, 0x3e, 0x28, 0x58, 0xeb, 0x3b, 0xab, 0xa5, 0xf2, 0xc4, 0xe4, 0x23,
+	0x80, 0xe1, 0x12, 0x9b, 0x89, 0x6f, 0x64, 0x7d, 0xee, 0x2c, 0x97, 0x48, 0x13, 0x63, 0x2f, 0xe1,
+	0x5a, 0x76, 0x6d, 0x44, 0x6b, 0xa9, 0x4f, 0x0a, 0x17, 0xdd, 0xce, 0xcd, 0xb7, 0x68, 0x24, 0x86,
+	0x0f, 0xf4, 0xaf, 0xb0, 0x78, 0x13, 0x42, 0xcb, 0x85, 0xfb, 0x4e, 0x12, 0xe7, 0x4a, 0x99, 0x38,
+	0xb1, 0xb7, 0x0f, 0x73, 0xf9, 0xed, 0x04, 0xe1, 0x4c, 0xb1, 0x0a, 0x57, 0x97, 0x4e, 0x6e, 0x0d,
+	0xdf, 0xf3, 0x99, 0xbc, 0xc4, 0x63, 0x68, 0x0f, 0x66, 0xd2, 0x1b, 0x4a, 0xe6, 0x5e, 0x0a, 0x56,
+	0x97, 0x32, 0x33, 0x4f, 0x01, 0x8d, 0x2e, 0x15, 0xe8, 0xff, 0x29, 0x63, 0xa5, 0x3b, 0x47, 0x99,
+	0xc9, 0x1d, 0x68, 0x3e, 0x67, 0x39, 0xb4, 0x45, 0x0b, 0xf9, 0x1f, 0x66, 0x7a, 0x6d, 0x28, 0x31,
+	0x71, 0xab, 0x82, 0x5e, 0x42, 0xab, 0x00, 0xb4, 0xd1, 0x47, 0xe9, 0xc0, 0x4a, 0x41, 0xbd, 0x53,
+	0xec, 0x0d, 0x8f, 0x7d, 0x52, 0x41, 0xf7, 0x61, 0x36, 0x03, 0xca, 0x28, 0xdb, 0xb0, 0xa3, 0x70,
+	0x5d, 0x96, 0xe6, 0x2b, 0x98, 0xcb, 0x23, 0x66, 0xe6, 0x12, 0x0a, 0xc0, 0xb9, 0xf3, 0xbf, 0x12,
+	0x79, 0x1a, 0x6e, 0xf1, 0x18, 0xa2, 0xb0, 0x58, 0x0c, 0x9b, 0xef, 0x74, 0x70, 0xbb, 0x44, 0x3e,
+	0x8a, 0xbc, 0x78, 0x0c, 0x3d, 0x81, 0xe6, 0x08, 0x5e, 0xa2, 0xa2, 0x10, 0xf3, 0x68, 0x5a, 0x56,
+	0x92, 0x94, 0xc1, 0x04, 0x10, 0x0b, 0x0d, 0xe6, 0xe1, 0xb2, 0xbc, 0x3b, 0x67, 0xd2, 0x38, 0x95,
+	0x49, 0xbf, 0x00, 0x3a, 0x3b, 0xab, 0xa5, 0xf2, 0xf4, 0x3c, 0x4b, 0x4f, 0xf4, 0x8c, 0xc9, 0x02,
+	0x10, 0xe9, 0xac, 0x96, 0xca, 0x13, 0x93, 0x5d, 0x68, 0x15, 0xa0, 0x50, 0xa6, 0x57, 0xcb, 0x51,
+	0xea, 0x0a, 0x0e, 0xbe, 0xfb, 0xf6, 0x87, 0x7b, 0xa7, 0x9e, 0xec, 0x47, 0xc7, 0xaa, 0x4a, 0x9b,
+	0xdb, 0xc1, 0x19, 0x5f, 0x0f, 0xa8, 0xbc, 0x08, 0xf9, 0xd9, 0x66, 0xcf, 0x75, 0x83, 0x75, 0x63,
+	0x60, 0x53, 0xd7, 0x4e, 0x0c, 0xff, 0xbb, 0xf7, 0x75, 0x72, 0x3a, 0xae, 0x69, 0xd9, 0x67, 0xff,
+	0x0e, 0x00, 0x3f, 0x49, 0x80, 0x95, 0x05, 0x14, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// AppMgrExtClient is the client API for AppMgrExt service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type AppMgrExtClient interface {
+	// PreviewApp renders the chart of an app with its custom values, without deploying it
+	PreviewApp(ctx context.Context, in *PreviewAppRequest, opts ...grpc.CallOption) (*PreviewAppResponse, error)
+	// SearchCharts searches charts by name, keywords, description and maintainers across repos
+	SearchCharts(ctx context.Context, in *SearchChartsRequest, opts ...grpc.CallOption) (*SearchChartsResponse, error)
+	// ListCharts is ChartList with the option to show pre-release versions as the latest
+	ListCharts(ctx context.Context, in *ListChartsRequest, opts ...grpc.CallOption) (*ListChartsResponse, error)
+	// GetChartDetail is ChartDetail with the option to list pre-release versions
+	GetChartDetail(ctx context.Context, in *GetChartDetailRequest, opts ...grpc.CallOption) (*GetChartDetailResponse, error)
+	// AppUpgrades lists the newer chart versions found for running apps by the upgrade check
+	AppUpgrades(ctx context.Context, in *AppUpgradesRequest, opts ...grpc.CallOption) (*AppUpgradesResponse, error)
+	// SetUpgradePolicy sets the automatic upgrade policy of an app
+	SetUpgradePolicy(ctx context.Context, in *SetUpgradePolicyRequest, opts ...grpc.CallOption) (*common.Empty, error)
+	// PromoteChart copies a chart version to another repo unchanged, with its provenance file if there is one
+	PromoteChart(ctx context.Context, in *PromoteChartRequest, opts ...grpc.CallOption) (*common.Empty, error)
+	// DeleteChartVersion is DeleteChart with the option to delete a chart version apps still use
+	DeleteChartVersion(ctx context.Context, in *DeleteChartVersionRequest, opts ...grpc.CallOption) (*common.Empty, error)
+	// UploadChartStream is UploadChart for large charts, the tarball is sent in chunks
+	UploadChartStream(ctx context.Context, opts ...grpc.CallOption) (AppMgrExt_UploadChartStreamClient, error)
+	// DownloadChartStream is DownloadChart for large charts, the tarball is received in chunks
+	DownloadChartStream(ctx context.Context, in *DownloadChartStreamRequest, opts ...grpc.CallOption) (AppMgrExt_DownloadChartStreamClient, error)
+	// SetMemberRole sets which rpcs a member of the team of the caller may call, operators may set it in any team
+	SetMemberRole(ctx context.Context, in *SetMemberRoleRequest, opts ...grpc.CallOption) (*common.Empty, error)
+	// OperatorListApps lists the apps of all teams, for operators
+	OperatorListApps(ctx context.Context, in *OperatorListRequest, opts ...grpc.CallOption) (*OperatorListAppsResponse, error)
+	// OperatorListNamespaces lists the namespaces of all teams, for operators
+	OperatorListNamespaces(ctx context.Context, in *OperatorListRequest, opts ...grpc.CallOption) (*OperatorListNamespacesResponse, error)
+	// OperatorSetStatus force sets the status of a record of any team stuck in a status no feedback will end
+	OperatorSetStatus(ctx context.Context, in *OperatorSetStatusRequest, opts ...grpc.CallOption) (*common.Empty, error)
+	// OperatorRepublish publishes the last DCStream of a record of any team again
+	OperatorRepublish(ctx context.Context, in *OperatorRepublishRequest, opts ...grpc.CallOption) (*common.Empty, error)
+	// DrainCluster moves the running namespaces and apps of a cluster to another one and places nothing new on it
+	DrainCluster(ctx context.Context, in *DrainClusterRequest, opts ...grpc.CallOption) (*DrainClusterResponse, error)
+	// UsageHistory returns the usage of a namespace, or of all namespaces of the team, over time
+	UsageHistory(ctx context.Context, in *UsageHistoryRequest, opts ...grpc.CallOption) (*UsageHistoryResponse, error)
+	// ClusterUsageHistory returns the usage of all namespaces of a cluster over time, for operators
+	ClusterUsageHistory(ctx context.Context, in *ClusterUsageHistoryRequest, opts ...grpc.CallOption) (*UsageHistoryResponse, error)
+}
+
+type appMgrExtClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAppMgrExtClient(cc grpc.ClientConnInterface) AppMgrExtClient {
+	return &appMgrExtClient{cc}
+}
+
+func (c *appMgrExtClient) PreviewApp(ctx context.Context, in *PreviewAppRequest, opts ...grpc.CallOption) (*PreviewAppResponse, error) {
+	out := new(PreviewAppResponse)
+	err := c.cc.Invoke(ctx, "/appmgrext.AppMgrExt/PreviewApp", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *appMgrExtClient) SearchCharts(ctx context.Context, in *SearchChartsRequest, opts ...grpc.CallOption) (*SearchChartsResponse, error) {
+	out := new(SearchChartsResponse)
+	err := c.cc.Invoke(ctx, "/appmgrext.AppMgrExt/SearchCharts", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *appMgrExtClient) ListCharts(ctx context.Context, in *ListChartsRequest, opts ...grpc.CallOption) (*ListChartsResponse, error) {
+	out := new(ListChartsResponse)
+	err := c.cc.Invoke(ctx, "/appmgrext.AppMgrExt/ListCharts", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *appMgrExtClient) GetChartDetail(ctx context.Context, in *GetChartDetailRequest, opts ...grpc.CallOption) (*GetChartDetailResponse, error) {
+	out := new(GetChartDetailResponse)
+	err := c.cc.Invoke(ctx, "/appmgrext.AppMgrExt/GetChartDetail", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *appMgrExtClient) AppUpgrades(ctx context.Context, in *AppUpgradesRequest, opts ...grpc.CallOption) (*AppUpgradesResponse, error) {
+	out := new(AppUpgradesResponse)
+	err := c.cc.Invoke(ctx, "/appmgrext.AppMgrExt/AppUpgrades", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *appMgrExtClient) SetUpgradePolicy(ctx context.Context, in *SetUpgradePolicyRequest, opts ...grpc.CallOption) (*common.Empty, error) {
+	out := new(common.Empty)
+	err := c.cc.Invoke(ctx, "/appmgrext.AppMgrExt/SetUpgradePolicy", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *appMgrExtClient) PromoteChart(ctx context.Context, in *PromoteChartRequest, opts ...grpc.CallOption) (*common.Empty, error) {
+	out := new(common.Empty)
+	err := c.cc.Invoke(ctx, "/appmgrext.AppMgrExt/PromoteChart", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *appMgrExtClient) DeleteChartVersion(ctx context.Context, in *DeleteChartVersionRequest, opts ...grpc.CallOption) (*common.Empty, error) {
+	out := new(common.Empty)
+	err := c.cc.Invoke(ctx, "/appmgrext.AppMgrExt/DeleteChartVersion", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *appMgrExtClient) UploadChartStream(ctx context.Context, opts ...grpc.CallOption) (AppMgrExt_UploadChartStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_AppMgrExt_serviceDesc.Streams[0], "/appmgrext.AppMgrExt/UploadChartStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &appMgrExtUploadChartStreamClient{stream}
+	return x, nil
+}
+
+type AppMgrExt_UploadChartStreamClient interface {
+	Send(*ChartChunk) error
+	CloseAndRecv() (*common.Empty, error)
+	grpc.ClientStream
+}
+
+type appMgrExtUploadChartStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *appMgrExtUploadChartStreamClient) Send(m *ChartChunk) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *appMgrExtUploadChartStreamClient) CloseAndRecv() (*common.Empty, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(common.Empty)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *appMgrExtClient) DownloadChartStream(ctx context.Context, in *DownloadChartStreamRequest, opts ...grpc.CallOption) (AppMgrExt_DownloadChartStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_AppMgrExt_serviceDesc.Streams[1], "/appmgrext.AppMgrExt/DownloadChartStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &appMgrExtDownloadChartStreamClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type AppMgrExt_DownloadChartStreamClient interface {
+	Recv() (*ChartChunk, error)
+	grpc.ClientStream
+}
+
+type appMgrExtDownloadChartStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *appMgrExtDownloadChartStreamClient) Recv() (*ChartChunk, error) {
+	m := new(ChartChunk)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *appMgrExtClient) SetMemberRole(ctx context.Context, in *SetMemberRoleRequest, opts ...grpc.CallOption) (*common.Empty, error) {
+	out := new(common.Empty)
+	err := c.cc.Invoke(ctx, "/appmgrext.AppMgrExt/SetMemberRole", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *appMgrExtClient) OperatorListApps(ctx context.Context, in *OperatorListRequest, opts ...grpc.CallOption) (*OperatorListAppsResponse, error) {
+	out := new(OperatorListAppsResponse)
+	err := c.cc.Invoke(ctx, "/appmgrext.AppMgrExt/OperatorListApps", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *appMgrExtClient) OperatorListNamespaces(ctx context.Context, in *OperatorListRequest, opts ...grpc.CallOption) (*OperatorListNamespacesResponse, error) {
+	out := new(OperatorListNamespacesResponse)
+	err := c.cc.Invoke(ctx, "/appmgrext.AppMgrExt/OperatorListNamespaces", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *appMgrExtClient) OperatorSetStatus(ctx context.Context, in *OperatorSetStatusRequest, opts ...grpc.CallOption) (*common.Empty, error) {
+	out := new(common.Empty)
+	err := c.cc.Invoke(ctx, "/appmgrext.AppMgrExt/OperatorSetStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *appMgrExtClient) OperatorRepublish(ctx context.Context, in *OperatorRepublishRequest, opts ...grpc.CallOption) (*common.Empty, error) {
+	out := new(common.Empty)
+	err := c.cc.Invoke(ctx, "/appmgrext.AppMgrExt/OperatorRepublish", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *appMgrExtClient) DrainCluster(ctx context.Context, in *DrainClusterRequest, opts ...grpc.CallOption) (*DrainClusterResponse, error) {
+	out := new(DrainClusterResponse)
+	err := c.cc.Invoke(ctx, "/appmgrext.AppMgrExt/DrainCluster", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *appMgrExtClient) UsageHistory(ctx context.Context, in *UsageHistoryRequest, opts ...grpc.CallOption) (*UsageHistoryResponse, error) {
+	out := new(UsageHistoryResponse)
+	err := c.cc.Invoke(ctx, "/appmgrext.AppMgrExt/UsageHistory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *appMgrExtClient) ClusterUsageHistory(ctx context.Context, in *ClusterUsageHistoryRequest, opts ...grpc.CallOption) (*UsageHistoryResponse, error) {
+	out := new(UsageHistoryResponse)
+	err := c.cc.Invoke(ctx, "/appmgrext.AppMgrExt/ClusterUsageHistory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AppMgrExtServer is the server API for AppMgrExt service.
+type AppMgrExtServer interface {
+	// PreviewApp renders the chart of an app with its custom values, without deploying it
+	PreviewApp(context.Context, *PreviewAppRequest) (*PreviewAppResponse, error)
+	// SearchCharts searches charts by name, keywords, description and maintainers across repos
+	SearchCharts(context.Context, *SearchChartsRequest) (*SearchChartsResponse, error)
+	// ListCharts is ChartList with the option to show pre-release versions as the latest
+	ListCharts(context.Context, *ListChartsRequest) (*ListChartsResponse, error)
+	// GetChartDetail is ChartDetail with the option to list pre-release versions
+	GetChartDetail(context.Context, *GetChartDetailRequest) (*GetChartDetailResponse, error)
+	// AppUpgrades lists the newer chart versions found for running apps by the upgrade check
+	AppUpgrades(context.Context, *AppUpgradesRequest) (*AppUpgradesResponse, error)
+	// SetUpgradePolicy sets the automatic upgrade policy of an app
+	SetUpgradePolicy(context.Context, *SetUpgradePolicyRequest) (*common.Empty, error)
+	// PromoteChart copies a chart version to another repo unchanged, with its provenance file if there is one
+	PromoteChart(context.Context, *PromoteChartRequest) (*common.Empty, error)
+	// DeleteChartVersion is DeleteChart with the option to delete a chart version apps still use
+	DeleteChartVersion(context.Context, *DeleteChartVersionRequest) (*common.Empty, error)
+	// UploadChartStream is UploadChart for large charts, the tarball is sent in chunks
+	UploadChartStream(AppMgrExt_UploadChartStreamServer) error
+	// DownloadChartStream is DownloadChart for large charts, the tarball is received in chunks
+	DownloadChartStream(*DownloadChartStreamRequest, AppMgrExt_DownloadChartStreamServer) error
+	// SetMemberRole sets which rpcs a member of the team of the caller may call, operators may set it in any team
+	SetMemberRole(context.Context, *SetMemberRoleRequest) (*common.Empty, error)
+	// OperatorListApps lists the apps of all teams, for operators
+	OperatorListApps(context.Context, *OperatorListRequest) (*OperatorListAppsResponse, error)
+	// OperatorListNamespaces lists the namespaces of all teams, for operators
+	OperatorListNamespaces(context.Context, *OperatorListRequest) (*OperatorListNamespacesResponse, error)
+	// OperatorSetStatus force sets the status of a record of any team stuck in a status no feedback will end
+	OperatorSetStatus(context.Context, *OperatorSetStatusRequest) (*common.Empty, error)
+	// OperatorRepublish publishes the last DCStream of a record of any team again
+	OperatorRepublish(context.Context, *OperatorRepublishRequest) (*common.Empty, error)
+	// DrainCluster moves the running namespaces and apps of a cluster to another one and places nothing new on it
+	DrainCluster(context.Context, *DrainClusterRequest) (*DrainClusterResponse, error)
+	// UsageHistory returns the usage of a namespace, or of all namespaces of the team, over time
+	UsageHistory(context.Context, *UsageHistoryRequest) (*UsageHistoryResponse, error)
+	// ClusterUsageHistory returns the usage of all namespaces of a cluster over time, for operators
+	ClusterUsageHistory(context.Context, *ClusterUsageHistoryRequest) (*UsageHistoryResponse, error)
+}
+
+// UnimplementedAppMgrExtServer can be embedded to have forward compatible implementations.
+type UnimplementedAppMgrExtServer struct {
+}
+
+func (*UnimplementedAppMgrExtServer) PreviewApp(ctx context.Context, req *PreviewAppRequest) (*PreviewAppResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PreviewApp not implemented")
+}
+func (*UnimplementedAppMgrExtServer) SearchCharts(ctx context.Context, req *SearchChartsRequest) (*SearchChartsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchCharts not implemented")
+}
+func (*UnimplementedAppMgrExtServer) ListCharts(ctx context.Context, req *ListChartsRequest) (*ListChartsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListCharts not implemented")
+}
+func (*UnimplementedAppMgrExtServer) GetChartDetail(ctx context.Context, req *GetChartDetailRequest) (*GetChartDetailResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetChartDetail not implemented")
+}
+func (*UnimplementedAppMgrExtServer) AppUpgrades(ctx context.Context, req *AppUpgradesRequest) (*AppUpgradesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AppUpgrades not implemented")
+}
+func (*UnimplementedAppMgrExtServer) SetUpgradePolicy(ctx context.Context, req *SetUpgradePolicyRequest) (*common.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetUpgradePolicy not implemented")
+}
+func (*UnimplementedAppMgrExtServer) PromoteChart(ctx context.Context, req *PromoteChartRequest) (*common.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PromoteChart not implemented")
+}
+func (*UnimplementedAppMgrExtServer) DeleteChartVersion(ctx context.Context, req *DeleteChartVersionRequest) (*common.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteChartVersion not implemented")
+}
+func (*UnimplementedAppMgrExtServer) UploadChartStream(srv AppMgrExt_UploadChartStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method UploadChartStream not implemented")
+}
+func (*UnimplementedAppMgrExtServer) DownloadChartStream(req *DownloadChartStreamRequest, srv AppMgrExt_DownloadChartStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method DownloadChartStream not implemented")
+}
+func (*UnimplementedAppMgrExtServer) SetMemberRole(ctx context.Context, req *SetMemberRoleRequest) (*common.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetMemberRole not implemented")
+}
+func (*UnimplementedAppMgrExtServer) OperatorListApps(ctx context.Context, req *OperatorListRequest) (*OperatorListAppsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method OperatorListApps not implemented")
+}
+func (*UnimplementedAppMgrExtServer) OperatorListNamespaces(ctx context.Context, req *OperatorListRequest) (*OperatorListNamespacesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method OperatorListNamespaces not implemented")
+}
+func (*UnimplementedAppMgrExtServer) OperatorSetStatus(ctx context.Context, req *OperatorSetStatusRequest) (*common.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method OperatorSetStatus not implemented")
+}
+func (*UnimplementedAppMgrExtServer) OperatorRepublish(ctx context.Context, req *OperatorRepublishRequest) (*common.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method OperatorRepublish not implemented")
+}
+func (*UnimplementedAppMgrExtServer) DrainCluster(ctx context.Context, req *DrainClusterRequest) (*DrainClusterResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DrainCluster not implemented")
+}
+func (*UnimplementedAppMgrExtServer) UsageHistory(ctx context.Context, req *UsageHistoryRequest) (*UsageHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UsageHistory not implemented")
+}
+func (*UnimplementedAppMgrExtServer) ClusterUsageHistory(ctx context.Context, req *ClusterUsageHistoryRequest) (*UsageHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ClusterUsageHistory not implemented")
+}
+
+func RegisterAppMgrExtServer(s *grpc.Server, srv AppMgrExtServer) {
+	s.RegisterService(&_AppMgrExt_serviceDesc, srv)
+}
+
+func _AppMgrExt_PreviewApp_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PreviewAppRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AppMgrExtServer).PreviewApp(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/appmgrext.AppMgrExt/PreviewApp",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AppMgrExtServer).PreviewApp(ctx, req.(*PreviewAppRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AppMgrExt_SearchCharts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchChartsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AppMgrExtServer).SearchCharts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/appmgrext.AppMgrExt/SearchCharts",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AppMgrExtServer).SearchCharts(ctx, req.(*SearchChartsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AppMgrExt_ListCharts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListChartsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AppMgrExtServer).ListCharts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/appmgrext.AppMgrExt/ListCharts",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AppMgrExtServer).ListCharts(ctx, req.(*ListChartsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AppMgrExt_GetChartDetail_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetChartDetailRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AppMgrExtServer).GetChartDetail(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/appmgrext.AppMgrExt/GetChartDetail",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AppMgrExtServer).GetChartDetail(ctx, req.(*GetChartDetailRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AppMgrExt_AppUpgrades_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AppUpgradesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AppMgrExtServer).AppUpgrades(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/appmgrext.AppMgrExt/AppUpgrades",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AppMgrExtServer).AppUpgrades(ctx, req.(*AppUpgradesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AppMgrExt_SetUpgradePolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetUpgradePolicyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AppMgrExtServer).SetUpgradePolicy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/appmgrext.AppMgrExt/SetUpgradePolicy",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AppMgrExtServer).SetUpgradePolicy(ctx, req.(*SetUpgradePolicyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AppMgrExt_PromoteChart_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PromoteChartRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AppMgrExtServer).PromoteChart(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/appmgrext.AppMgrExt/PromoteChart",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AppMgrExtServer).PromoteChart(ctx, req.(*PromoteChartRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AppMgrExt_DeleteChartVersion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteChartVersionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AppMgrExtServer).DeleteChartVersion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/appmgrext.AppMgrExt/DeleteChartVersion",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AppMgrExtServer).DeleteChartVersion(ctx, req.(*DeleteChartVersionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AppMgrExt_UploadChartStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(AppMgrExtServer).UploadChartStream(&appMgrExtUploadChartStreamServer{stream})
+}
+
+type AppMgrExt_UploadChartStreamServer interface {
+	SendAndClose(*common.Empty) error
+	Recv() (*ChartChunk, error)
+	grpc.ServerStream
+}
+
+type appMgrExtUploadChartStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *appMgrExtUploadChartStreamServer) SendAndClose(m *common.Empty) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *appMgrExtUploadChartStreamServer) Recv() (*ChartChunk, error) {
+	m := new(ChartChunk)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _AppMgrExt_DownloadChartStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(DownloadChartStreamRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AppMgrExtServer).DownloadChartStream(m, &appMgrExtDownloadChartStreamServer{stream})
+}
+
+type AppMgrExt_DownloadChartStreamServer interface {
+	Send(*ChartChunk) error
+	grpc.ServerStream
+}
+
+type appMgrExtDownloadChartStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *appMgrExtDownloadChartStreamServer) Send(m *ChartChunk) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _AppMgrExt_SetMemberRole_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetMemberRoleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AppMgrExtServer).SetMemberRole(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/appmgrext.AppMgrExt/SetMemberRole",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AppMgrExtServer).SetMemberRole(ctx, req.(*SetMemberRoleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AppMgrExt_OperatorListApps_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OperatorListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AppMgrExtServer).OperatorListApps(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/appmgrext.AppMgrExt/OperatorListApps",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AppMgrExtServer).OperatorListApps(ctx, req.(*OperatorListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AppMgrExt_OperatorListNamespaces_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OperatorListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AppMgrExtServer).OperatorListNamespaces(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/appmgrext.AppMgrExt/OperatorListNamespaces",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AppMgrExtServer).OperatorListNamespaces(ctx, req.(*OperatorListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AppMgrExt_OperatorSetStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OperatorSetStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AppMgrExtServer).OperatorSetStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/appmgrext.AppMgrExt/OperatorSetStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AppMgrExtServer).OperatorSetStatus(ctx, req.(*OperatorSetStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AppMgrExt_OperatorRepublish_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OperatorRepublishRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AppMgrExtServer).OperatorRepublish(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/appmgrext.AppMgrExt/OperatorRepublish",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AppMgrExtServer).OperatorRepublish(ctx, req.(*OperatorRepublishRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AppMgrExt_DrainCluster_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DrainClusterRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AppMgrExtServer).DrainCluster(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/appmgrext.AppMgrExt/DrainCluster",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AppMgrExtServer).DrainCluster(ctx, req.(*DrainClusterRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AppMgrExt_UsageHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UsageHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AppMgrExtServer).UsageHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/appmgrext.AppMgrExt/UsageHistory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AppMgrExtServer).UsageHistory(ctx, req.(*UsageHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AppMgrExt_ClusterUsageHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClusterUsageHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AppMgrExtServer).ClusterUsageHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/appmgrext.AppMgrExt/ClusterUsageHistory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AppMgrExtServer).ClusterUsageHistory(ctx, req.(*ClusterUsageHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _AppMgrExt_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "appmgrext.AppMgrExt",
+	HandlerType: (*AppMgrExtServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "PreviewApp",
+			Handler:    _AppMgrExt_PreviewApp_Handler,
+		},
+		{
+			MethodName: "SearchCharts",
+			Handler:    _AppMgrExt_SearchCharts_Handler,
+		},
+		{
+			MethodName: "ListCharts",
+			Handler:    _AppMgrExt_ListCharts_Handler,
+		},
+		{
+			MethodName: "GetChartDetail",
+			Handler:    _AppMgrExt_GetChartDetail_Handler,
+		},
+		{
+			MethodName: "AppUpgrades",
+			Handler:    _AppMgrExt_AppUpgrades_Handler,
+		},
+		{
+			MethodName: "SetUpgradePolicy",
+			Handler:    _AppMgrExt_SetUpgradePolicy_Handler,
+		},
+		{
+			MethodName: "PromoteChart",
+			Handler:    _AppMgrExt_PromoteChart_Handler,
+		},
+		{
+			MethodName: "DeleteChartVersion",
+			Handler:    _AppMgrExt_DeleteChartVersion_Handler,
+		},
+		{
+			MethodName: "SetMemberRole",
+			Handler:    _AppMgrExt_SetMemberRole_Handler,
+		},
+		{
+			MethodName: "OperatorListApps",
+			Handler:    _AppMgrExt_OperatorListApps_Handler,
+		},
+		{
+			MethodName: "OperatorListNamespaces",
+			Handler:    _AppMgrExt_OperatorListNamespaces_Handler,
+		},
+		{
+			MethodName: "OperatorSetStatus",
+			Handler:    _AppMgrExt_OperatorSetStatus_Handler,
+		},
+		{
+			MethodName: "OperatorRepublish",
+			Handler:    _AppMgrExt_OperatorRepublish_Handler,
+		},
+		{
+			MethodName: "DrainCluster",
+			Handler:    _AppMgrExt_DrainCluster_Handler,
+		},
+		{
+			MethodName: "UsageHistory",
+			Handler:    _AppMgrExt_UsageHistory_Handler,
+		},
+		{
+			MethodName: "ClusterUsageHistory",
+			Handler:    _AppMgrExt_ClusterUsageHistory_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "UploadChartStream",
+			Handler:       _AppMgrExt_UploadChartStream_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "DownloadChartStream",
+			Handler:       _AppMgrExt_DownloadChartStream_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "appmgrext/appmgrext.proto",
+}
@@ -0,0 +1,130 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+
+	ankr_default "github.com/Ankr-network/dccn-common/protos"
+	common_proto "github.com/Ankr-network/dccn-common/protos/common"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrDraining is returned for work refused once the drain started
+var ErrDraining = errors.New(ankr_default.LogicError + "appmgr is shutting down")
+
+// Drain counts the rpcs, broker deliveries and background loops in flight, and refuses new ones once it
+// is closed, so shutting down does not cut work off between a publish and the db write which follows it
+type Drain struct {
+	mu       sync.Mutex
+	closed   bool
+	inFlight int
+	done     sync.WaitGroup
+}
+
+// NewDrain returns an open drain
+func NewDrain() *Drain {
+	return &Drain{}
+}
+
+// Enter counts a piece of work in flight, false once the drain is closed and the work must not start
+func (d *Drain) Enter() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.closed {
+		return false
+	}
+	d.inFlight++
+	d.done.Add(1)
+	return true
+}
+
+// Leave counts a piece of work entered done
+func (d *Drain) Leave() {
+	d.mu.Lock()
+	d.inFlight--
+	d.mu.Unlock()
+	d.done.Done()
+}
+
+// Go runs f in a goroutine counted in flight until it returns, unless the drain is closed
+func (d *Drain) Go(f func()) {
+	if !d.Enter() {
+		return
+	}
+	go func() {
+		defer d.Leave()
+		f()
+	}()
+}
+
+// Close refuses new work and waits for the work in flight until ctx is done, it then fails telling how much
+// work is left
+func (d *Drain) Close(ctx context.Context) error {
+	d.mu.Lock()
+	d.closed = true
+	d.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		d.done.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		return errors.New(strconv.Itoa(d.inFlight) + " still in flight, " + ctx.Err().Error())
+	}
+}
+
+// UnaryServerInterceptor counts each rpc in flight, and refuses new ones as Unavailable once the drain is closed
+// so clients retry them on another replica
+func (d *Drain) UnaryServerInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler) (interface{}, error) {
+	if !d.Enter() {
+		return nil, status.Error(codes.Unavailable, ErrDraining.Error())
+	}
+	defer d.Leave()
+	return handler(ctx, req)
+}
+
+// StreamServerInterceptor is UnaryServerInterceptor for streaming rpcs, a chart transfer counts in flight until
+// its stream ends so shutting down does not cut it off
+func (d *Drain) StreamServerInterceptor(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler) error {
+	if !d.Enter() {
+		return status.Error(codes.Unavailable, ErrDraining.Error())
+	}
+	defer d.Leave()
+	return handler(srv, stream)
+}
+
+// DCStreamHandler wraps a DCStream subscriber handler to count each delivery in flight, and to refuse those
+// arriving once the drain is closed with an error rather than start handling them
+func (d *Drain) DCStreamHandler(handle func(*common_proto.DCStream) error) func(*common_proto.DCStream) error {
+	return func(stream *common_proto.DCStream) error {
+		if !d.Enter() {
+			return ErrDraining
+		}
+		defer d.Leave()
+		return handle(stream)
+	}
+}
+
+// DataCenterStatusHandler wraps a DataCenterStatus subscriber handler as DCStreamHandler does
+func (d *Drain) DataCenterStatusHandler(
+	handle func(*common_proto.DataCenterStatus) error) func(*common_proto.DataCenterStatus) error {
+	return func(dc *common_proto.DataCenterStatus) error {
+		if !d.Enter() {
+			return ErrDraining
+		}
+		defer d.Leave()
+		return handle(dc)
+	}
+}
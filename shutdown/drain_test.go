@@ -0,0 +1,88 @@
+package shutdown
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	common_proto "github.com/Ankr-network/dccn-common/protos/common"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestDrainWaitsForWorkInFlight(t *testing.T) {
+	drain := NewDrain()
+	release := make(chan struct{})
+	finished := make(chan struct{})
+
+	handle := drain.DCStreamHandler(func(*common_proto.DCStream) error {
+		<-release
+		close(finished)
+		return nil
+	})
+	go handle(&common_proto.DCStream{})
+	// the delivery must be in flight before the drain closes
+	for !inFlight(drain) {
+		time.Sleep(time.Millisecond)
+	}
+
+	closed := make(chan error)
+	go func() { closed <- drain.Close(context.Background()) }()
+	select {
+	case err := <-closed:
+		t.Fatalf("drain closed with a delivery in flight, %v", err)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	if err := handle(&common_proto.DCStream{}); err != ErrDraining {
+		t.Errorf("delivery after close got %v, want it refused", err)
+	}
+	info := &grpc.UnaryServerInfo{FullMethod: "/appmgr.AppMgr/CreateApp"}
+	_, err := drain.UnaryServerInterceptor(context.Background(), nil, info,
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			t.Error("rpc ran after close")
+			return nil, nil
+		})
+	if status.Code(err) != codes.Unavailable {
+		t.Errorf("rpc after close got %v, want Unavailable", err)
+	}
+	streamInfo := &grpc.StreamServerInfo{FullMethod: "/appmgrext.AppMgrExt/UploadChartStream", IsClientStream: true}
+	err = drain.StreamServerInterceptor(nil, nil, streamInfo, func(srv interface{}, stream grpc.ServerStream) error {
+		t.Error("stream ran after close")
+		return nil
+	})
+	if status.Code(err) != codes.Unavailable {
+		t.Errorf("stream after close got %v, want Unavailable", err)
+	}
+	ran := false
+	drain.Go(func() { ran = true })
+
+	close(release)
+	if err := <-closed; err != nil {
+		t.Fatal(err)
+	}
+	<-finished
+	if ran {
+		t.Error("loop started after close")
+	}
+}
+
+func TestDrainDeadline(t *testing.T) {
+	drain := NewDrain()
+	stuck := make(chan struct{})
+	defer close(stuck)
+	drain.Go(func() { <-stuck })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := drain.Close(ctx); err == nil {
+		t.Error("drain closed with a loop stuck")
+	}
+}
+
+func inFlight(d *Drain) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.inFlight > 0
+}
@@ -0,0 +1,37 @@
+package subscriber
+
+import (
+	"context"
+	"time"
+
+	"github.com/Ankr-network/dccn-appmgr/logger"
+)
+
+// RunStalenessCheck marks clusters which sent no heartbeat for longer than timeout unavailable, until ctx
+// is done. Handle marks them available again with their next heartbeat.
+func (p *MetricsSubscriber) RunStalenessCheck(ctx context.Context, timeout time.Duration) {
+	ticker := time.NewTicker(timeout / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			p.CheckStaleness(timeout)
+		}
+	}
+}
+
+// CheckStaleness marks the clusters silent for longer than timeout unavailable, with their running
+// namespaces and apps, so no new work is sent to them
+func (p *MetricsSubscriber) CheckStaleness(timeout time.Duration) {
+	ids, err := p.DB.MarkStaleClustersUnavailable(time.Now().Add(-timeout).Unix())
+	if err != nil {
+		p.Logger.Error("mark stale clusters unavailable failed", logger.Fields{"error": err})
+	}
+	for _, id := range ids {
+		p.Logger.Warn("cluster sent no heartbeat, marked unavailable", logger.Fields{"cluster_id": id,
+			"timeout": timeout.String()})
+	}
+}
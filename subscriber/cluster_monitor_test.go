@@ -0,0 +1,92 @@
+package subscriber
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	dbservice "github.com/Ankr-network/dccn-appmgr/db_service"
+	"github.com/Ankr-network/dccn-appmgr/logger"
+)
+
+// staleDB returns ids as the stale clusters and keeps the cutoffs it is asked for
+type staleDB struct {
+	dbservice.DBService
+	ids []string
+	err error
+
+	mu      sync.Mutex
+	befores []int64
+}
+
+func (d *staleDB) MarkStaleClustersUnavailable(before int64) ([]string, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.befores = append(d.befores, before)
+	return d.ids, d.err
+}
+
+func (d *staleDB) calls() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return len(d.befores)
+}
+
+func TestCheckStaleness(t *testing.T) {
+	fake := &staleDB{ids: []string{"dc-stale"}}
+	var buf bytes.Buffer
+	p := &MetricsSubscriber{DB: fake, Logger: logger.New(&buf, logger.InfoLevel)}
+
+	start := time.Now()
+	p.CheckStaleness(time.Minute)
+
+	if len(fake.befores) != 1 {
+		t.Fatalf("marked stale clusters %d times, want once", len(fake.befores))
+	}
+	if before := fake.befores[0]; before < start.Add(-time.Minute).Unix() || before > time.Now().Add(-time.Minute).Unix() {
+		t.Errorf("clusters silent since before %d marked unavailable, want a minute ago", before)
+	}
+	if !strings.Contains(buf.String(), "dc-stale") {
+		t.Errorf("stale cluster not logged: %s", buf.String())
+	}
+
+	buf.Reset()
+	fake.err = errors.New("db down")
+	fake.ids = nil
+	p.CheckStaleness(time.Minute)
+	if !strings.Contains(buf.String(), "db down") {
+		t.Errorf("error not logged: %s", buf.String())
+	}
+}
+
+func TestRunStalenessCheck(t *testing.T) {
+	fake := &staleDB{}
+	p := &MetricsSubscriber{DB: fake, Logger: logger.New(&bytes.Buffer{}, logger.ErrorLevel)}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		p.RunStalenessCheck(ctx, 30*time.Millisecond)
+		close(done)
+	}()
+
+	// the check runs every third of the timeout
+	deadline := time.Now().Add(time.Second)
+	for fake.calls() < 2 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if fake.calls() < 2 {
+		t.Errorf("checked %d times in a second, want the check to repeat", fake.calls())
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("staleness check still running after its context is done")
+	}
+}
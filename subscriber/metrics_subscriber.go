@@ -1,25 +1,40 @@
 package subscriber
 
 import (
+	"context"
+	"time"
+
 	dbservice "github.com/Ankr-network/dccn-appmgr/db_service"
+	"github.com/Ankr-network/dccn-appmgr/logger"
+	"github.com/Ankr-network/dccn-appmgr/tracing"
 	common_proto "github.com/Ankr-network/dccn-common/protos/common"
 	"github.com/golang/protobuf/ptypes/timestamp"
+	"go.opentelemetry.io/otel/api/kv"
+	"go.opentelemetry.io/otel/api/trace"
 	"gopkg.in/mgo.v2"
 	"gopkg.in/mgo.v2/bson"
-	"log"
-	"time"
 )
 
 type MetricsSubscriber struct {
-	DB dbservice.DBService
+	DB     dbservice.DBService
+	Logger *logger.Logger
 }
 
 func (p *MetricsSubscriber) Handle(dc *common_proto.DataCenterStatus) error {
-	log.Printf("MetricsSubscriber handle metrics message: %+v", dc)
+	ctx, span := tracing.Start(context.Background(), "consume DataCenterStatus", trace.SpanKindConsumer,
+		kv.String("messaging.system", "rabbitmq"), kv.String("appmgr.cluster_id", dc.DcId))
+	err := p.handle(dbservice.Traced(ctx, p.DB), dc)
+	tracing.End(span, err)
+	return err
+}
+
+func (p *MetricsSubscriber) handle(db dbservice.DBService, dc *common_proto.DataCenterStatus) error {
+	log := p.Logger.With(logger.Fields{"cluster_id": dc.DcId})
+	log.Debug("received metrics", logger.Fields{"status": dc.DcStatus.String()})
 	if dc.DcHeartbeatReport != nil && dc.DcHeartbeatReport.MetricsRaw != nil {
-		if _, err := p.DB.GetClusterConnection(dc.DcId); err == mgo.ErrNotFound {
-			if err = p.DB.CreateClusterConnection(dc.DcId, dc.DcStatus, dc.DcHeartbeatReport.MetricsRaw); err != nil {
-				log.Printf("Create cluster connection failed %v", err)
+		if _, err := db.GetClusterConnection(dc.DcId); err == mgo.ErrNotFound {
+			if err = db.CreateClusterConnection(dc.DcId, dc.DcStatus, dc.DcHeartbeatReport.MetricsRaw); err != nil {
+				log.Error("create cluster connection failed", logger.Fields{"error": err})
 				return err
 			}
 		} else {
@@ -27,15 +42,18 @@ func (p *MetricsSubscriber) Handle(dc *common_proto.DataCenterStatus) error {
 			update := bson.M{}
 			update["metrics"] = dc.DcHeartbeatReport.MetricsRaw
 			update["status"] = dc.DcStatus
-			update["lastmodifieddate"] = &timestamp.Timestamp{Seconds: time.Now().Unix()}
-			if err := p.DB.Update("clusterconnection", dc.DcId, bson.M{"$set": update}); err != nil {
+			now := &timestamp.Timestamp{Seconds: time.Now().Unix()}
+			update["lastmodifieddate"] = now
+			update["lastheartbeat"] = now
+			if err := db.Update("clusterconnection", dc.DcId, bson.M{"$set": update}); err != nil {
+				log.Error("update cluster connection failed", logger.Fields{"error": err})
 				return err
 			}
 		}
-		log.Printf("update namespace & app by heartbeat metrics")
-		p.DB.UpdateByHeartbeatMetrics(dc.DcId, dc.DcHeartbeatReport.MetricsRaw)
+		log.Debug("update namespace & app by heartbeat metrics")
+		db.UpdateByHeartbeatMetrics(dc.DcId, dc.DcHeartbeatReport.MetricsRaw)
 	} else {
-		log.Printf("metrics is miss, skip")
+		log.Warn("metrics is miss, skip")
 	}
 	return nil
 }
@@ -0,0 +1,156 @@
+package subscriber
+
+import (
+	"context"
+	"time"
+
+	"github.com/Ankr-network/dccn-appmgr/logger"
+	"github.com/Ankr-network/dccn-appmgr/migration"
+	common_proto "github.com/Ankr-network/dccn-common/protos/common"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// migrateApps recreates the running apps of a namespace moved off a draining cluster in its replacement, now
+// that the replacement runs
+func (p *AppStatusFeedback) migrateApps(ctx context.Context, nsId, replacementId string) {
+	log := p.logger.WithContext(ctx).With(logger.Fields{"namespace_id": nsId, "replaced_by": replacementId})
+	replacement, err := p.db.GetNamespace(replacementId)
+	if err != nil {
+		log.Error("get replacement namespace failed", logger.Fields{"error": err})
+		return
+	}
+	appIds, err := migration.Apps(ctx, p.db, p.publish, nsId, migration.Namespace(replacement))
+	if err != nil {
+		log.Error("cannot migrate apps of drained namespace", logger.Fields{"error": err, "app_ids": appIds})
+		return
+	}
+	if len(appIds) > 0 {
+		log.Info("apps of drained namespace recreated", logger.Fields{"app_ids": appIds})
+	}
+}
+
+// unlinkFailedReplacement hides the replacement of an app or namespace moved off a draining cluster which failed
+// to launch, and clears the link of the original so draining the cluster again moves it anew
+func (p *AppStatusFeedback) unlinkFailedReplacement(ctx context.Context, collection, id, replacementId string) {
+	if collection == "app" {
+		migration.UnlinkApp(p.db, id, replacementId)
+	} else {
+		migration.UnlinkNamespace(p.db, id, replacementId)
+	}
+	p.logger.WithContext(ctx).Warn("replacement of drained "+collection+" failed to launch, unlinked",
+		logger.Fields{collection + "_id": id, "replaced_by": replacementId})
+}
+
+// cancelMigratedApp cancels the app a replacement on another cluster now runs for, then the namespace it
+// leaves if nothing runs there anymore
+func (p *AppStatusFeedback) cancelMigratedApp(ctx context.Context, appId string) {
+	log := p.logger.WithContext(ctx).With(logger.Fields{"app_id": appId})
+	appRecord, err := p.db.GetApp(appId)
+	if err != nil {
+		log.Error("get migrated app failed", logger.Fields{"error": err})
+		return
+	}
+
+	switch appRecord.Status {
+	case common_proto.AppStatus_APP_CANCELING, common_proto.AppStatus_APP_CANCELED:
+	case common_proto.AppStatus_APP_UNAVAILABLE, common_proto.AppStatus_APP_FAILED:
+		log.Info("migrated app is unavailable or failed, cancel directly")
+		if err := p.db.Update("app", appId, bson.M{"$set": bson.M{
+			"status":           common_proto.AppStatus_APP_CANCELED,
+			"hidden":           true,
+			"lastmodifieddate": &timestamp.Timestamp{Seconds: time.Now().Unix()},
+		}}); err != nil {
+			log.Error("cancel migrated app failed", logger.Fields{"error": err})
+			return
+		}
+	default:
+		nsRecord, err := p.db.GetNamespace(appRecord.NamespaceID)
+		if err != nil {
+			log.Error("get namespace of migrated app failed", logger.Fields{"error": err})
+			return
+		}
+		chartDetail := appRecord.ChartDetail
+		event := common_proto.DCStream{
+			OpType: common_proto.DCOperation_APP_CANCEL,
+			OpPayload: &common_proto.DCStream_AppDeployment{AppDeployment: &common_proto.AppDeployment{
+				AppId:       appRecord.ID,
+				AppName:     appRecord.Name,
+				TeamId:      appRecord.TeamID,
+				ChartDetail: &chartDetail,
+				Namespace: &common_proto.Namespace{
+					NsId:        nsRecord.ID,
+					NsName:      nsRecord.Name,
+					ClusterId:   nsRecord.ClusterID,
+					ClusterName: nsRecord.ClusterName,
+				},
+			}},
+		}
+		if err := p.publish(ctx, &event); err != nil {
+			log.Error("cannot cancel migrated app", logger.Fields{"error": err})
+			return
+		}
+		// the replacement is what the team sees from now on
+		if err := p.db.Update("app", appId, bson.M{"$set": bson.M{
+			"status":           common_proto.AppStatus_APP_CANCELING,
+			"hidden":           true,
+			"lastmodifieddate": &timestamp.Timestamp{Seconds: time.Now().Unix()},
+		}}); err != nil {
+			log.Error("cancel migrated app failed", logger.Fields{"error": err})
+			return
+		}
+	}
+	log.Info("migrated app canceled", logger.Fields{"replaced_by": appRecord.MigratedTo})
+
+	p.cancelDrainedNamespace(ctx, appRecord.NamespaceID)
+}
+
+// cancelDrainedNamespace cancels a namespace moved off a draining cluster once its replacement runs and
+// none of its apps runs anymore
+func (p *AppStatusFeedback) cancelDrainedNamespace(ctx context.Context, nsId string) {
+	log := p.logger.WithContext(ctx).With(logger.Fields{"namespace_id": nsId})
+	nsRecord, err := p.db.GetNamespace(nsId)
+	if err != nil {
+		log.Error("get drained namespace failed", logger.Fields{"error": err})
+		return
+	}
+	if len(nsRecord.MigratedTo) == 0 || nsRecord.Status == common_proto.NamespaceStatus_NS_CANCELING ||
+		nsRecord.Status == common_proto.NamespaceStatus_NS_CANCELED {
+		return
+	}
+
+	replacement, err := p.db.GetNamespace(nsRecord.MigratedTo)
+	if err != nil || replacement.Status != common_proto.NamespaceStatus_NS_RUNNING {
+		return
+	}
+	apps, err := p.db.GetRunningAppsByNamespaceId(nsId)
+	if err != nil || len(apps) > 0 {
+		return
+	}
+
+	event := common_proto.DCStream{
+		OpType: common_proto.DCOperation_NS_CANCEL,
+		OpPayload: &common_proto.DCStream_Namespace{Namespace: &common_proto.Namespace{
+			NsId:           nsRecord.ID,
+			NsName:         nsRecord.Name,
+			ClusterId:      nsRecord.ClusterID,
+			ClusterName:    nsRecord.ClusterName,
+			NsCpuLimit:     nsRecord.CpuLimit,
+			NsMemLimit:     nsRecord.MemLimit,
+			NsStorageLimit: nsRecord.StorageLimit,
+		}},
+	}
+	if err := p.publish(ctx, &event); err != nil {
+		log.Error("cannot cancel drained namespace", logger.Fields{"error": err})
+		return
+	}
+	if err := p.db.Update("namespace", nsId, bson.M{"$set": bson.M{
+		"status":           common_proto.NamespaceStatus_NS_CANCELING,
+		"hidden":           true,
+		"lastmodifieddate": &timestamp.Timestamp{Seconds: time.Now().Unix()},
+	}}); err != nil {
+		log.Error("cancel drained namespace failed", logger.Fields{"error": err})
+		return
+	}
+	log.Info("drained namespace canceled", logger.Fields{"replaced_by": nsRecord.MigratedTo})
+}
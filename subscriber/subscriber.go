@@ -1,31 +1,52 @@
 package subscriber
 
 import (
+	"context"
 	"errors"
 	"fmt"
-	"log"
 	"time"
 
 	db "github.com/Ankr-network/dccn-appmgr/db_service"
+	"github.com/Ankr-network/dccn-appmgr/logger"
+	"github.com/Ankr-network/dccn-appmgr/tracing"
+	"github.com/Ankr-network/dccn-common/broker"
 	common_proto "github.com/Ankr-network/dccn-common/protos/common"
 	"github.com/golang/protobuf/ptypes/timestamp"
+	"go.opentelemetry.io/otel/api/kv"
+	"go.opentelemetry.io/otel/api/trace"
 	"gopkg.in/mgo.v2/bson"
 )
 
 type AppStatusFeedback struct {
-	db db.DBService
+	db        db.DBService
+	deployApp broker.Publisher
+	logger    *logger.Logger
 }
 
-func New(db db.DBService) *AppStatusFeedback {
-	return &AppStatusFeedback{db}
+func New(db db.DBService, deployApp broker.Publisher, appLogger *logger.Logger) *AppStatusFeedback {
+	return &AppStatusFeedback{db, deployApp, appLogger}
 }
 
 // UHandlerFeedbackEventFromDataCenter receives app report result from data center and update record
 func (p *AppStatusFeedback) HandlerFeedbackEventFromDataCenter(stream *common_proto.DCStream) error {
-	log.Printf(">>>>>>>>HandlerFeedbackEventFromDataCenter: Receive New Event: %+v with payload: %+v ", stream.GetOpType(), stream.GetOpPayload())
+	ctx, span := tracing.Start(p.streamContext(stream), "consume "+stream.GetOpType().String(), trace.SpanKindConsumer,
+		kv.String("messaging.system", "rabbitmq"), kv.String("appmgr.target", db.StreamTarget(stream)))
+	traced := *p
+	traced.db = db.Traced(ctx, p.db)
+	err := traced.handleFeedback(ctx, stream)
+	tracing.End(span, err)
+	return err
+}
+
+func (p *AppStatusFeedback) handleFeedback(ctx context.Context, stream *common_proto.DCStream) error {
+	log := p.logger.WithContext(ctx).With(logger.Fields{"op_type": stream.GetOpType().String(), "target": db.StreamTarget(stream)})
+	log.Debug("received DCStream", logger.Fields{"payload": logger.Redact(stream.GetOpPayload())})
 	update := bson.M{}
 	var collection string
 	var id string
+	// replaced is the app or namespace moved off a draining cluster which the running record replaces, unlinked
+	// the one whose replacement failed to launch
+	var replaced, unlinked string
 	switch x := stream.OpPayload.(type) {
 
 	case *common_proto.DCStream_AppReport:
@@ -34,7 +55,7 @@ func (p *AppStatusFeedback) HandlerFeedbackEventFromDataCenter(stream *common_pr
 
 		appRecord, err := p.db.GetApp(appReport.AppDeployment.AppId)
 		if err != nil {
-			log.Println(err.Error())
+			log.Error("cannot get app of DCStream", logger.Fields{"error": err})
 			return err
 		}
 
@@ -48,8 +69,10 @@ func (p *AppStatusFeedback) HandlerFeedbackEventFromDataCenter(stream *common_pr
 				switch appReport.AppEvent {
 				case common_proto.AppEvent_LAUNCH_APP_SUCCEED:
 					update["status"] = common_proto.AppStatus_APP_RUNNING
+					replaced = appRecord.MigratedFrom
 				case common_proto.AppEvent_LAUNCH_APP_FAILED:
 					update["status"] = common_proto.AppStatus_APP_FAILED
+					unlinked = appRecord.MigratedFrom
 				case common_proto.AppEvent_DISPATCH_APP:
 					update["status"] = common_proto.AppStatus_APP_LAUNCHING
 				}
@@ -62,7 +85,11 @@ func (p *AppStatusFeedback) HandlerFeedbackEventFromDataCenter(stream *common_pr
 					update["status"] = common_proto.AppStatus_APP_RUNNING
 					update["chartdetail"] = appRecord.ChartUpdating
 					update["customvalues"] = appRecord.CustomValuesUpdating
+					update["autoupgrade.upgrading"] = false
 				case common_proto.AppEvent_UPDATE_APP_FAILED:
+					if appRecord.AutoUpgrade.Upgrading {
+						return p.rollbackAutoUpgrade(ctx, appRecord, appReport)
+					}
 					update["status"] = common_proto.AppStatus_APP_UPDATE_FAILED
 				}
 				update["event"] = appReport.AppEvent
@@ -73,7 +100,7 @@ func (p *AppStatusFeedback) HandlerFeedbackEventFromDataCenter(stream *common_pr
 				case common_proto.AppEvent_CANCEL_APP_SUCCEED:
 					update["status"] = common_proto.AppStatus_APP_CANCELED
 				case common_proto.AppEvent_CANCEL_APP_FAILED:
-					log.Printf("cancel app %s failed", appReport.AppDeployment.AppId)
+					log.Warn("cancel app failed")
 				}
 				update["event"] = appReport.AppEvent
 			}
@@ -82,7 +109,7 @@ func (p *AppStatusFeedback) HandlerFeedbackEventFromDataCenter(stream *common_pr
 			update["nodeports"] = appReport.NodePorts
 			update["gatewayaddr"] = appReport.GatewayAddr
 		default:
-			log.Printf("OpType has unexpected type %v", opType)
+			log.Error("OpType has unexpected type")
 			return fmt.Errorf("OpType has unexpected type %v", opType)
 		}
 
@@ -95,7 +122,7 @@ func (p *AppStatusFeedback) HandlerFeedbackEventFromDataCenter(stream *common_pr
 
 		nsRecord, err := p.db.GetNamespace(nsReport.Namespace.NsId)
 		if err != nil {
-			log.Println(err.Error())
+			log.Error("cannot get namespace of DCStream", logger.Fields{"error": err})
 			return err
 		}
 
@@ -103,7 +130,7 @@ func (p *AppStatusFeedback) HandlerFeedbackEventFromDataCenter(stream *common_pr
 
 		// ignore running namespace feedback
 		if nsRecord.Status == common_proto.NamespaceStatus_NS_RUNNING {
-			log.Printf("ignore running namespace feedback, ns_report: %+v, ns_record: %+v", nsReport, nsRecord)
+			log.Debug("ignore running namespace feedback", logger.Fields{"event": nsReport.NsEvent.String()})
 			return nil
 		}
 
@@ -119,8 +146,10 @@ func (p *AppStatusFeedback) HandlerFeedbackEventFromDataCenter(stream *common_pr
 				switch nsReport.NsEvent {
 				case common_proto.NamespaceEvent_LAUNCH_NS_SUCCEED:
 					update["status"] = common_proto.NamespaceStatus_NS_RUNNING
+					replaced = nsRecord.MigratedFrom
 				case common_proto.NamespaceEvent_LAUNCH_NS_FAILED:
 					update["status"] = common_proto.NamespaceStatus_NS_FAILED
+					unlinked = nsRecord.MigratedFrom
 				case common_proto.NamespaceEvent_DISPATCH_NS:
 					update["status"] = common_proto.NamespaceStatus_NS_LAUNCHING
 				}
@@ -143,11 +172,11 @@ func (p *AppStatusFeedback) HandlerFeedbackEventFromDataCenter(stream *common_pr
 				case common_proto.NamespaceEvent_CANCEL_NS_SUCCEED:
 					update["status"] = common_proto.NamespaceStatus_NS_CANCELED
 				case common_proto.NamespaceEvent_CANCEL_NS_FAILED:
-					log.Printf("cancel namespace %s failed", nsReport.Namespace.NsId)
+					log.Warn("cancel namespace failed")
 				}
 			}
 		default:
-			log.Printf("OpType has unexpected type %v", opType)
+			log.Error("OpType has unexpected type")
 			return fmt.Errorf("OpType has unexpected type %v", opType)
 		}
 
@@ -157,7 +186,7 @@ func (p *AppStatusFeedback) HandlerFeedbackEventFromDataCenter(stream *common_pr
 	case *common_proto.DCStream_DataCenter:
 		if stream.GetOpType() != common_proto.DCOperation_DCSTATUS_UPDATE {
 			err := errors.New("stream OpType for dc is not for update, skip")
-			log.Print(err)
+			log.Error(err.Error())
 			return err
 		}
 		dc := stream.GetDataCenter()
@@ -166,12 +195,114 @@ func (p *AppStatusFeedback) HandlerFeedbackEventFromDataCenter(stream *common_pr
 		update["status"] = dc.DcStatus
 
 	default:
-		log.Printf("OpPayload has unexpected type %T", x)
+		log.Error(fmt.Sprintf("OpPayload has unexpected type %T", x))
 		return fmt.Errorf("OpPayload has unexpected type %T", x)
 	}
 
-	log.Printf(">>>>>>>>HandlerFeedbackEventFromDataCenter: Update Collection %s on ID %s Update: %s", collection, id, update)
+	log.Info("update by DCStream", logger.Fields{"collection": collection, "id": id, "status": update["status"],
+		"event": update["event"]})
 
 	update["lastmodifieddate"] = &timestamp.Timestamp{Seconds: time.Now().Unix()}
-	return p.db.Update(collection, id, bson.M{"$set": update})
+	if err := p.db.Update(collection, id, bson.M{"$set": update}); err != nil {
+		log.Error("cannot update by DCStream", logger.Fields{"error": err})
+		return err
+	}
+
+	if len(replaced) > 0 {
+		if collection == "app" {
+			p.cancelMigratedApp(ctx, replaced)
+		} else {
+			p.migrateApps(ctx, replaced, id)
+			p.cancelDrainedNamespace(ctx, replaced)
+		}
+	}
+	if len(unlinked) > 0 {
+		p.unlinkFailedReplacement(ctx, collection, unlinked, id)
+	}
+	return nil
+}
+
+// rollbackAutoUpgrade moves an app whose automatic upgrade failed back to the chart it ran before,
+// the failed version is not tried again. A failing rollback ends in APP_UPDATE_FAILED as usual.
+func (p *AppStatusFeedback) rollbackAutoUpgrade(ctx context.Context, appRecord db.AppRecord,
+	appReport *common_proto.AppReport) error {
+	log := p.logger.WithContext(ctx).With(logger.Fields{"app_id": appRecord.ID})
+	log.Warn("automatic upgrade failed, roll back", logger.Fields{"failed_version": appRecord.ChartUpdating.ChartVer,
+		"version": appRecord.ChartDetail.ChartVer})
+
+	appDeployment := *appReport.AppDeployment
+	appDeployment.ChartDetail = &appRecord.ChartDetail
+	appDeployment.CustomValues = appRecord.CustomValues
+	if appDeployment.Namespace == nil {
+		nsRecord, err := p.db.GetNamespace(appRecord.NamespaceID)
+		if err != nil {
+			log.Error("cannot get namespace of app", logger.Fields{"error": err})
+			return err
+		}
+		appDeployment.Namespace = &common_proto.Namespace{
+			NsId:        nsRecord.ID,
+			NsName:      nsRecord.Name,
+			ClusterId:   nsRecord.ClusterID,
+			ClusterName: nsRecord.ClusterName,
+		}
+	}
+
+	event := common_proto.DCStream{
+		OpType:    common_proto.DCOperation_APP_UPDATE,
+		OpPayload: &common_proto.DCStream_AppDeployment{AppDeployment: &appDeployment},
+	}
+	if err := p.publish(ctx, &event); err != nil {
+		log.Error("cannot roll back app", logger.Fields{"error": err})
+		return p.db.Update("app", appRecord.ID, bson.M{"$set": bson.M{
+			"status":                    common_proto.AppStatus_APP_UPDATE_FAILED,
+			"event":                     common_proto.AppEvent_UPDATE_APP_FAILED,
+			"autoupgrade.upgrading":     false,
+			"autoupgrade.failedversion": appRecord.ChartUpdating.ChartVer,
+			"lastmodifieddate":          &timestamp.Timestamp{Seconds: time.Now().Unix()},
+		}})
+	}
+
+	return p.db.Update("app", appRecord.ID, bson.M{"$set": bson.M{
+		"report":                    appReport.Report,
+		"event":                     common_proto.AppEvent_UPDATE_APP,
+		"chartupdating":             appRecord.ChartDetail,
+		"customvaluesupdating":      appRecord.CustomValues,
+		"autoupgrade.upgrading":     false,
+		"autoupgrade.failedversion": appRecord.ChartUpdating.ChartVer,
+		"lastmodifieddate":          &timestamp.Timestamp{Seconds: time.Now().Unix()},
+	}})
+}
+
+// streamContext carries the correlation id and trace of the call which published the DCStream of the op type a
+// feedback answers, or a new correlation id for feedback no call led to, so the actions it triggers are logged and
+// traced together
+func (p *AppStatusFeedback) streamContext(stream *common_proto.DCStream) context.Context {
+	correlation, err := p.db.GetStreamCorrelation(stream)
+	if err != nil || len(correlation.CorrelationID) == 0 {
+		correlation.CorrelationID = logger.NewCorrelationID()
+	}
+	ctx := tracing.Extract(context.Background(), correlation.TraceContext)
+	return logger.WithCorrelationID(ctx, correlation.CorrelationID)
+}
+
+// publish sends a DCStream to dcmgr and keeps the correlation id and trace context of ctx for the op type and the
+// app or namespace it names, before it goes out so its feedback always finds them
+func (p *AppStatusFeedback) publish(ctx context.Context, event *common_proto.DCStream) error {
+	target := db.StreamTarget(event)
+	ctx, span := tracing.Start(ctx, "publish "+event.OpType.String(), trace.SpanKindProducer,
+		kv.String("messaging.system", "rabbitmq"), kv.String("appmgr.target", target))
+	log := p.logger.WithContext(ctx).With(logger.Fields{"op_type": event.OpType.String(), "target": target})
+
+	correlation := db.CorrelationRecord{CorrelationID: logger.CorrelationID(ctx), TraceContext: tracing.Inject(ctx)}
+	if err := p.db.SetStreamCorrelation(event, correlation); err != nil {
+		log.Warn("cannot keep the correlation of DCStream", logger.Fields{"error": err})
+	}
+
+	if err := p.deployApp.Publish(event); err != nil {
+		tracing.End(span, err)
+		return err
+	}
+	log.Info("published DCStream")
+	span.End()
+	return nil
 }
@@ -0,0 +1,168 @@
+package subscriber
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	dbservice "github.com/Ankr-network/dccn-appmgr/db_service"
+	"github.com/Ankr-network/dccn-appmgr/logger"
+	"github.com/Ankr-network/dccn-appmgr/tracing"
+	common_proto "github.com/Ankr-network/dccn-common/protos/common"
+	"go.opentelemetry.io/otel/api/global"
+	"go.opentelemetry.io/otel/api/trace"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// correlationDB keeps correlations by target and op type as the db does, and the correlation kept for each
+// DCStream at the time it is published
+type correlationDB struct {
+	dbservice.DBService
+	correlations map[string]dbservice.CorrelationRecord
+	published    []dbservice.CorrelationRecord
+}
+
+func streamKey(stream *common_proto.DCStream) string {
+	return dbservice.StreamTarget(stream) + " " + stream.GetOpType().String()
+}
+
+func (d *correlationDB) SetStreamCorrelation(stream *common_proto.DCStream, correlation dbservice.CorrelationRecord) error {
+	d.correlations[streamKey(stream)] = correlation
+	return nil
+}
+
+func (d *correlationDB) GetStreamCorrelation(stream *common_proto.DCStream) (dbservice.CorrelationRecord, error) {
+	correlation, ok := d.correlations[streamKey(stream)]
+	if !ok {
+		return correlation, mgo.ErrNotFound
+	}
+	return correlation, nil
+}
+
+func (d *correlationDB) Publish(m interface{}) error {
+	d.published = append(d.published, d.correlations[streamKey(m.(*common_proto.DCStream))])
+	return nil
+}
+
+func TestFeedbackContinuesPublish(t *testing.T) {
+	provider, err := sdktrace.NewProvider(sdktrace.WithConfig(sdktrace.Config{DefaultSampler: sdktrace.AlwaysSample()}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	global.SetTraceProvider(provider)
+
+	fake := &correlationDB{correlations: map[string]dbservice.CorrelationRecord{}}
+	p := New(fake, fake, logger.New(&bytes.Buffer{}, logger.ErrorLevel))
+
+	ctx, call := tracing.Start(logger.WithCorrelationID(context.Background(), "correlation-1"), "call",
+		trace.SpanKindServer)
+	app := &common_proto.AppDeployment{AppId: "app-1"}
+	if err := p.publish(ctx, &common_proto.DCStream{
+		OpType:    common_proto.DCOperation_APP_CREATE,
+		OpPayload: &common_proto.DCStream_AppDeployment{AppDeployment: app},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	call.End()
+
+	// the trace context of the publish span is kept before the DCStream goes out
+	if len(fake.published) != 1 || fake.published[0].CorrelationID != "correlation-1" ||
+		len(fake.published[0].TraceContext["traceparent"]) == 0 {
+		t.Fatalf("published with correlation %+v, want the correlation id and trace context kept first", fake.published)
+	}
+
+	feedback := func(opType common_proto.DCOperation) *common_proto.DCStream {
+		return &common_proto.DCStream{OpType: opType, OpPayload: &common_proto.DCStream_AppReport{
+			AppReport: &common_proto.AppReport{AppDeployment: app}}}
+	}
+
+	created := p.streamContext(feedback(common_proto.DCOperation_APP_CREATE))
+	if id := logger.CorrelationID(created); id != "correlation-1" {
+		t.Errorf("create feedback logged under %q, want correlation-1", id)
+	}
+	if parent := trace.RemoteSpanContextFromContext(created); parent.TraceID != call.SpanContext().TraceID {
+		t.Errorf("create feedback traced under %s, want the trace of the call %s", parent.TraceID, call.SpanContext().TraceID)
+	}
+
+	// feedback of an op nothing published is not taken for the create
+	updated := p.streamContext(feedback(common_proto.DCOperation_APP_UPDATE))
+	if id := logger.CorrelationID(updated); len(id) == 0 || id == "correlation-1" {
+		t.Errorf("update feedback logged under %q, want a new correlation id", id)
+	}
+	if parent := trace.RemoteSpanContextFromContext(updated); parent.IsValid() {
+		t.Errorf("update feedback traced under %s, want a new trace", parent.TraceID)
+	}
+}
+
+// feedbackDB has the records feedback is about and keeps the updates made to them
+type feedbackDB struct {
+	dbservice.DBService
+	apps       map[string]dbservice.AppRecord
+	namespaces map[string]dbservice.NamespaceRecord
+	updates    []string
+}
+
+func (d *feedbackDB) GetApp(id string) (dbservice.AppRecord, error) {
+	return d.apps[id], nil
+}
+
+func (d *feedbackDB) GetNamespace(id string) (dbservice.NamespaceRecord, error) {
+	return d.namespaces[id], nil
+}
+
+func (d *feedbackDB) Update(collection string, id string, update bson.M) error {
+	for op, fields := range update {
+		for field := range fields.(bson.M) {
+			if field != "lastmodifieddate" && field != "report" && field != "event" {
+				d.updates = append(d.updates, fmt.Sprint(op, " ", collection, "/", id, " ", field))
+			}
+		}
+	}
+	return nil
+}
+
+func (d *feedbackDB) GetStreamCorrelation(stream *common_proto.DCStream) (dbservice.CorrelationRecord, error) {
+	return dbservice.CorrelationRecord{}, mgo.ErrNotFound
+}
+
+func TestFeedbackUnlinksFailedReplacement(t *testing.T) {
+	fake := &feedbackDB{
+		apps: map[string]dbservice.AppRecord{
+			"app-2": {ID: "app-2", MigratedFrom: "app-1", Status: common_proto.AppStatus_APP_LAUNCHING},
+		},
+		namespaces: map[string]dbservice.NamespaceRecord{
+			"ns-2": {ID: "ns-2", MigratedFrom: "ns-1", Status: common_proto.NamespaceStatus_NS_LAUNCHING},
+		},
+	}
+	p := New(fake, nil, logger.New(&bytes.Buffer{}, logger.ErrorLevel))
+
+	// the original no longer points at a replacement which will never run, so draining again moves it anew
+	if err := p.HandlerFeedbackEventFromDataCenter(&common_proto.DCStream{
+		OpType: common_proto.DCOperation_NS_CREATE,
+		OpPayload: &common_proto.DCStream_NsReport{NsReport: &common_proto.NamespaceReport{
+			Namespace: &common_proto.Namespace{NsId: "ns-2"}, NsEvent: common_proto.NamespaceEvent_LAUNCH_NS_FAILED}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.HandlerFeedbackEventFromDataCenter(&common_proto.DCStream{
+		OpType: common_proto.DCOperation_APP_CREATE,
+		OpPayload: &common_proto.DCStream_AppReport{AppReport: &common_proto.AppReport{
+			AppDeployment: &common_proto.AppDeployment{AppId: "app-2"}, AppEvent: common_proto.AppEvent_LAUNCH_APP_FAILED}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{"$unset namespace/ns-1 migratedto", "$set namespace/ns-2 hidden",
+		"$unset app/app-1 migratedto", "$set app/app-2 hidden"} {
+		found := false
+		for _, update := range fake.updates {
+			found = found || update == want
+		}
+		if !found {
+			t.Errorf("no %q in %q", want, fake.updates)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/api/global"
+	"go.opentelemetry.io/otel/api/propagation"
+)
+
+// Carrier holds trace context as the W3C traceparent and tracestate headers would, for messages which have no
+// headers to carry it in
+type Carrier map[string]string
+
+func (c Carrier) Get(key string) string {
+	return c[key]
+}
+
+func (c Carrier) Set(key string, value string) {
+	c[key] = value
+}
+
+// Inject returns the trace context of the span of ctx, empty if ctx has no span
+func Inject(ctx context.Context) Carrier {
+	carrier := Carrier{}
+	propagation.InjectHTTP(ctx, global.Propagators(), carrier)
+	return carrier
+}
+
+// Extract returns ctx with the span of the carrier as remote parent, ctx as is if the carrier holds none
+func Extract(ctx context.Context, carrier Carrier) context.Context {
+	if len(carrier) == 0 {
+		return ctx
+	}
+	return propagation.ExtractHTTP(ctx, global.Propagators(), carrier)
+}
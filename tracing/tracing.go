@@ -0,0 +1,90 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"os"
+
+	"go.opentelemetry.io/otel/api/global"
+	"go.opentelemetry.io/otel/api/kv"
+	"go.opentelemetry.io/otel/api/trace"
+	"go.opentelemetry.io/otel/exporters/otlp"
+	"go.opentelemetry.io/otel/exporters/trace/stdout"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"google.golang.org/grpc/status"
+)
+
+// ServiceName names the service spans are exported for and the tracer which starts them
+const ServiceName = "dccn-appmgr"
+
+const (
+	// ExporterOTLP exports spans in batches to an OTLP collector
+	ExporterOTLP = "otlp"
+	// ExporterStdout writes each span as a JSON line to stdout, for local testing
+	ExporterStdout = "stdout"
+)
+
+// Init sets the global trace provider to export all spans with exporter, to the collector at endpoint for otlp.
+// Tracing stays a no-op if exporter is empty. The returned func flushes the spans not exported yet.
+func Init(exporter, endpoint string) (func(), error) {
+	config := sdktrace.WithConfig(sdktrace.Config{DefaultSampler: sdktrace.AlwaysSample()})
+	service := sdktrace.WithResource(resource.New(kv.String("service.name", ServiceName)))
+
+	switch exporter {
+	case "":
+		return func() {}, nil
+	case ExporterStdout:
+		exp, err := stdout.NewExporter(stdout.Options{Writer: os.Stdout})
+		if err != nil {
+			return nil, err
+		}
+		provider, err := sdktrace.NewProvider(config, service, sdktrace.WithSyncer(exp))
+		if err != nil {
+			return nil, err
+		}
+		global.SetTraceProvider(provider)
+		return func() {}, nil
+	case ExporterOTLP:
+		exp, err := otlp.NewExporter(otlp.WithInsecure(), otlp.WithAddress(endpoint))
+		if err != nil {
+			return nil, err
+		}
+		batcher, err := sdktrace.NewBatchSpanProcessor(exp)
+		if err != nil {
+			return nil, err
+		}
+		provider, err := sdktrace.NewProvider(config, service)
+		if err != nil {
+			return nil, err
+		}
+		provider.RegisterSpanProcessor(batcher)
+		global.SetTraceProvider(provider)
+		return func() {
+			// unregistering shuts the batcher down, which exports what it still queues
+			provider.UnregisterSpanProcessor(batcher)
+			_ = exp.Stop()
+		}, nil
+	}
+	return nil, errors.New("unknown trace exporter " + exporter)
+}
+
+// Tracer starts the spans of appmgr, through the global trace provider
+func Tracer() trace.Tracer {
+	return global.Tracer(ServiceName)
+}
+
+// Start starts a span of kind as a child of the span of ctx
+func Start(ctx context.Context, name string, kind trace.SpanKind, attrs ...kv.KeyValue) (context.Context, trace.Span) {
+	return Tracer().Start(ctx, name, trace.WithSpanKind(kind), trace.WithAttributes(attrs...))
+}
+
+// End ends span, failed with the grpc code of err if err is not nil
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(context.Background(), err)
+		s := status.Convert(err)
+		span.SetStatus(s.Code(), s.Message())
+	}
+	span.End()
+}
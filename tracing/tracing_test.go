@@ -0,0 +1,91 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"go.opentelemetry.io/otel/api/global"
+	"go.opentelemetry.io/otel/api/trace"
+	export "go.opentelemetry.io/otel/sdk/export/trace"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type recorder struct {
+	mu    sync.Mutex
+	spans map[string]*export.SpanData
+}
+
+func (r *recorder) ExportSpan(_ context.Context, span *export.SpanData) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.spans[span.Name] = span
+}
+
+// record makes the global provider export spans to the returned recorder, by name
+func record(t *testing.T) *recorder {
+	r := &recorder{spans: map[string]*export.SpanData{}}
+	provider, err := sdktrace.NewProvider(sdktrace.WithSyncer(r))
+	if err != nil {
+		t.Fatal(err)
+	}
+	global.SetTraceProvider(provider)
+	return r
+}
+
+func TestInjectExtract(t *testing.T) {
+	r := record(t)
+
+	if carrier := Inject(context.Background()); len(carrier) != 0 {
+		t.Errorf("injected %v without a span", carrier)
+	}
+	if ctx := context.Background(); Extract(ctx, nil) != ctx {
+		t.Error("extracting nothing changed the context")
+	}
+
+	ctx, publish := Start(context.Background(), "publish", trace.SpanKindProducer)
+	publish.End()
+	carrier := Inject(ctx)
+	if len(carrier["traceparent"]) == 0 {
+		t.Fatalf("injected %v, want a traceparent", carrier)
+	}
+
+	_, consume := Start(Extract(context.Background(), carrier), "consume", trace.SpanKindConsumer)
+	consume.End()
+
+	parent, child := r.spans["publish"], r.spans["consume"]
+	if child.SpanContext.TraceID != parent.SpanContext.TraceID || child.ParentSpanID != parent.SpanContext.SpanID ||
+		!child.HasRemoteParent {
+		t.Errorf("consume span %+v does not continue publish span %+v", child, parent)
+	}
+}
+
+func TestEnd(t *testing.T) {
+	r := record(t)
+
+	_, rejected := Start(context.Background(), "rejected", trace.SpanKindServer)
+	End(rejected, status.Error(codes.InvalidArgument, "bad chunk"))
+	_, failed := Start(context.Background(), "failed", trace.SpanKindServer)
+	End(failed, errors.New("closed"))
+
+	if got := r.spans["rejected"]; got.StatusCode != codes.InvalidArgument || got.StatusMessage != "bad chunk" {
+		t.Errorf("span ended with %s %q", got.StatusCode, got.StatusMessage)
+	}
+	if got := r.spans["failed"]; got.StatusCode != codes.Unknown {
+		t.Errorf("span of an error without status ended with %s", got.StatusCode)
+	}
+}
+
+func TestInit(t *testing.T) {
+	stop, err := Init("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	stop()
+	if _, err := Init("zipkin", ""); err == nil {
+		t.Error("initialized an unknown exporter")
+	}
+}